@@ -0,0 +1,69 @@
+package agentsdk
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	defaultReconnectInitialBackoff = 500 * time.Millisecond
+	defaultReconnectMaxBackoff     = 30 * time.Second
+	reconnectBackoffJitter         = 0.2
+	reconnectHealthyResetAfter     = 60 * time.Second
+)
+
+// reconnectBackoff computes jittered exponential delays between stream reconnects
+type reconnectBackoff struct {
+	initial time.Duration
+	max     time.Duration
+	current time.Duration
+}
+
+func newReconnectBackoff(initial, max time.Duration) *reconnectBackoff {
+	if initial <= 0 {
+		initial = defaultReconnectInitialBackoff
+	}
+	if max < initial {
+		max = initial
+	}
+	return &reconnectBackoff{initial: initial, max: max, current: initial}
+}
+
+// next returns the delay before the next reconnect. A stream that stayed up for
+// reconnectHealthyResetAfter resets the backoff to its initial interval.
+func (b *reconnectBackoff) next(connectedFor time.Duration) time.Duration {
+	if connectedFor >= reconnectHealthyResetAfter {
+		b.current = b.initial
+	}
+
+	delay := b.current
+	b.current *= 2
+	if b.current > b.max {
+		b.current = b.max
+	}
+
+	return withJitter(delay, reconnectBackoffJitter)
+}
+
+// withJitter spreads d uniformly by ±fraction so many clients do not retry in lockstep
+func withJitter(d time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 {
+		return d
+	}
+	jitter := (rand.Float64()*2 - 1) * fraction
+	return time.Duration(float64(d) * (1 + jitter))
+}
+
+// wait sleeps for the next backoff delay, returning false if ctx is cancelled first
+func (b *reconnectBackoff) wait(ctx context.Context, connectedAt time.Time) bool {
+	timer := time.NewTimer(b.next(time.Since(connectedAt)))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,53 @@
+package agentsdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconnectBackoffGrowsAndResets(t *testing.T) {
+	backoff := newReconnectBackoff(500*time.Millisecond, 2*time.Second)
+
+	within := func(got, want time.Duration) bool {
+		return got >= time.Duration(float64(want)*0.8) && got <= time.Duration(float64(want)*1.2)
+	}
+
+	for i, want := range []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 2 * time.Second} {
+		if got := backoff.next(0); !within(got, want) {
+			t.Fatalf("attempt %d: delay %v outside ±20%% of %v", i, got, want)
+		}
+	}
+
+	if got := backoff.next(reconnectHealthyResetAfter); !within(got, 500*time.Millisecond) {
+		t.Fatalf("expected reset to initial after healthy stream, got %v", got)
+	}
+}
+
+func TestNegativeReportRetryJitterDisablesJitter(t *testing.T) {
+	build := func(jitter float64) *Config {
+		cfg, err := NewConfigBuilder().
+			WithSubnetID("subnet-1").
+			WithAgentID("agent-1").
+			WithMatcherAddr("localhost:8090").
+			WithCapabilities("compute").
+			WithReportRetryJitter(jitter).
+			Build()
+		if err != nil {
+			t.Fatalf("Build with jitter %v: %v", jitter, err)
+		}
+		return cfg
+	}
+
+	if cfg := build(0); cfg.ReportRetryJitter != defaultReportRetryJitter {
+		t.Fatalf("expected unset jitter to default to %v, got %v", defaultReportRetryJitter, cfg.ReportRetryJitter)
+	}
+	cfg := build(-1)
+	if cfg.ReportRetryJitter != -1 {
+		t.Fatalf("expected negative jitter to be kept, got %v", cfg.ReportRetryJitter)
+	}
+	for i := 0; i < 10; i++ {
+		if got := withJitter(time.Second, cfg.ReportRetryJitter); got != time.Second {
+			t.Fatalf("expected exact retry delays with jitter disabled, got %v", got)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package agentsdk
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "subnet/proto/subnet"
+)
+
+// pendingBid is a strategy bid waiting to be flushed in a SubmitBidBatch request
+type pendingBid struct {
+	intent *Intent
+	bid    *Bid
+	proto  *pb.Bid
+}
+
+// bidBatchingEnabled reports whether strategy bids are batched instead of submitted one by one
+func (c *Config) bidBatchingEnabled() bool {
+	return c.BidBatchSize > 0
+}
+
+// enqueueBid hands a strategy bid to the batch flusher; bids arriving after shutdown are discarded
+func (sdk *SDK) enqueueBid(ctx context.Context, intent *Intent, bid *Bid) {
+	if ctx.Err() != nil {
+		return
+	}
+	select {
+	case sdk.bidQueue <- pendingBid{intent: intent, bid: bid, proto: sdk.newBidProto(intent.ID, bid)}:
+	case <-ctx.Done():
+	}
+}
+
+// bidBatchLoop accumulates queued bids and submits them once BidBatchSize bids are pending or
+// BidBatchFlushInterval elapses. Bids still pending when ctx is cancelled are flushed before it returns.
+func (sdk *SDK) bidBatchLoop(ctx context.Context) {
+	defer sdk.matcherWG.Done()
+
+	ticker := time.NewTicker(sdk.config.BidBatchFlushInterval)
+	defer ticker.Stop()
+
+	var batch []pendingBid
+	for {
+		select {
+		case <-ctx.Done():
+			batch = append(batch, sdk.drainBidQueue()...)
+			if len(batch) > 0 {
+				sdk.flushBids(context.Background(), batch)
+			}
+			return
+		case pending := <-sdk.bidQueue:
+			batch = append(batch, pending)
+			if len(batch) >= sdk.config.BidBatchSize {
+				sdk.flushBids(ctx, batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				sdk.flushBids(ctx, batch)
+				batch = nil
+			}
+		}
+	}
+}
+
+// drainBidQueue removes the bids currently waiting in the queue without blocking
+func (sdk *SDK) drainBidQueue() []pendingBid {
+	var drained []pendingBid
+	for {
+		select {
+		case pending := <-sdk.bidQueue:
+			drained = append(drained, pending)
+		default:
+			return drained
+		}
+	}
+}
+
+// flushBids submits a batch of bids in one SubmitBidBatch call and maps the per-bid acks, which the
+// matcher returns in request order, back to metrics and bid callbacks
+func (sdk *SDK) flushBids(ctx context.Context, batch []pendingBid) {
+	partialOK := true
+	req := &pb.SubmitBidBatchRequest{
+		Bids:      make([]*pb.Bid, 0, len(batch)),
+		BatchId:   fmt.Sprintf("batch-%s", hex.EncodeToString(randomBytes(sdk.config.RandSource, 16))),
+		PartialOk: &partialOK,
+	}
+	for _, pending := range batch {
+		req.Bids = append(req.Bids, pending.proto)
+	}
+
+	bidCtx, cancel := context.WithTimeout(ctx, sdk.config.BidResponseTimeout)
+	defer cancel()
+
+	var (
+		resp *pb.SubmitBidBatchResponse
+		err  error
+	)
+	matcherClient := sdk.clients().matcher
+	if matcherClient == nil {
+		err = errors.New("matcher client not initialized")
+	} else {
+		resp, err = matcherClient.SubmitBidBatch(bidCtx, req)
+	}
+	if err != nil {
+		for range batch {
+			sdk.metrics.RecordBid(false)
+		}
+		sdk.logger.Warn("Failed to submit bid batch", "batch_id", req.BatchId, "bids", len(batch), "error", err)
+		sdk.fireCallback("OnError", fmt.Errorf("bid batch submission failed: %w", err))
+		return
+	}
+
+	for i, pending := range batch {
+		var ackProto *pb.BidSubmissionAck
+		if i < len(resp.Acks) {
+			ackProto = resp.Acks[i]
+		}
+		ack := bidAckFromProto(ackProto, pending.proto.BidId)
+		sdk.metrics.RecordBid(ack.Accepted)
+		sdk.notifyBidAck(pending.intent, pending.bid, ack)
+	}
+	sdk.logger.Debug("Bid batch submitted", "batch_id", req.BatchId, "bids", len(batch), "accepted", resp.Success)
+}
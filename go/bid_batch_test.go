@@ -0,0 +1,94 @@
+package agentsdk
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type bidAckCallbacks struct {
+	recordingCallbacks
+	ackMu    sync.Mutex
+	accepted []string
+	rejected []string
+}
+
+func (c *bidAckCallbacks) OnBidAccepted(intent *Intent, bid *Bid, ack *BidAck) {
+	c.ackMu.Lock()
+	defer c.ackMu.Unlock()
+	c.accepted = append(c.accepted, intent.ID)
+}
+
+func (c *bidAckCallbacks) OnBidRejected(intent *Intent, bid *Bid, ack *BidAck) {
+	c.ackMu.Lock()
+	defer c.ackMu.Unlock()
+	c.rejected = append(c.rejected, intent.ID)
+}
+
+func newBatchingSDK(t *testing.T, size int, interval time.Duration) (*SDK, *fakeMatcherService, *bidAckCallbacks) {
+	t.Helper()
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.BidBatchSize = size
+		cfg.BidBatchFlushInterval = interval
+	})
+	matcher := &fakeMatcherService{}
+	sdk.matcherClient = &MatcherClient{client: matcher, logger: sdk.logger}
+	callbacks := &bidAckCallbacks{}
+	sdk.RegisterCallbacks(callbacks)
+	return sdk, matcher, callbacks
+}
+
+func TestBidBatchFlushesWhenFullAndOnShutdown(t *testing.T) {
+	sdk, matcher, callbacks := newBatchingSDK(t, 2, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sdk.matcherWG.Add(1)
+	go sdk.bidBatchLoop(ctx)
+
+	sdk.enqueueBid(ctx, &Intent{ID: "intent-1"}, &Bid{Price: 100})
+	sdk.enqueueBid(ctx, &Intent{ID: "intent-2"}, &Bid{Price: 50})
+	sdk.enqueueBid(ctx, &Intent{ID: "intent-3"}, &Bid{Price: 200})
+	deadline := time.Now().Add(time.Second)
+	for len(matcher.batchSizes()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	sdk.matcherWG.Wait()
+
+	if sizes := matcher.batchSizes(); !reflect.DeepEqual(sizes, []int{2, 1}) {
+		t.Fatalf("expected a full batch and a shutdown flush, got batch sizes %v", sizes)
+	}
+	if !reflect.DeepEqual(callbacks.accepted, []string{"intent-1", "intent-3"}) || !reflect.DeepEqual(callbacks.rejected, []string{"intent-2"}) {
+		t.Fatalf("acks not mapped back to intents: accepted=%v rejected=%v", callbacks.accepted, callbacks.rejected)
+	}
+	if snapshot := sdk.metrics.Snapshot(); snapshot.TotalBids != 3 || snapshot.SuccessfulBids != 2 {
+		t.Fatalf("expected per-bid metrics, got %+v", snapshot)
+	}
+}
+
+func TestBidBatchFlushesOnInterval(t *testing.T) {
+	sdk, matcher, _ := newBatchingSDK(t, 10, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sdk.matcherWG.Add(1)
+	go sdk.bidBatchLoop(ctx)
+
+	sdk.enqueueBid(ctx, &Intent{ID: "intent-1"}, &Bid{Price: 100})
+	deadline := time.Now().Add(time.Second)
+	for len(matcher.batchSizes()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if sizes := matcher.batchSizes(); !reflect.DeepEqual(sizes, []int{1}) {
+		t.Fatalf("expected a partial batch to flush on the interval, got batch sizes %v", sizes)
+	}
+}
+
+func TestBidBatchingRequiresFlushInterval(t *testing.T) {
+	_, err := New(&Config{AgentID: "agent-1", MatcherAddr: "matcher:8090", Capabilities: []string{"compute"}, BidBatchSize: 10})
+	if err == nil {
+		t.Fatal("expected bid batching without a flush interval to be rejected")
+	}
+}
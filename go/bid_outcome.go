@@ -0,0 +1,99 @@
+package agentsdk
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Intent update types the matcher sends to resolve the bidding on an intent. They are delivered
+// on the intent stream and are never passed to the bidding strategy.
+const (
+	IntentUpdateBidWon  = "bid_won"
+	IntentUpdateBidLost = "bid_lost"
+)
+
+// openBidRetention bounds how long an accepted bid waits for an outcome before it is forgotten
+const openBidRetention = time.Hour
+
+// openBid is an accepted bid awaiting the matcher's decision
+type openBid struct {
+	bidID    string
+	price    uint64
+	placedAt time.Time
+}
+
+// openBidTracker records the intents the agent holds accepted bids on, so bid outcomes are only
+// reported for the agent's own bids
+type openBidTracker struct {
+	mu   sync.Mutex
+	bids map[string]openBid // keyed by intent ID
+}
+
+func newOpenBidTracker() *openBidTracker {
+	return &openBidTracker{bids: make(map[string]openBid)}
+}
+
+// record remembers an accepted bid on intentID, replacing any earlier bid on the same intent
+func (t *openBidTracker) record(intentID string, bid openBid) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for id, open := range t.bids {
+		if bid.placedAt.Sub(open.placedAt) >= openBidRetention {
+			delete(t.bids, id)
+		}
+	}
+	t.bids[intentID] = bid
+}
+
+// resolve removes and returns the open bid on intentID, if any
+func (t *openBidTracker) resolve(intentID string) (openBid, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	bid, ok := t.bids[intentID]
+	if ok {
+		delete(t.bids, intentID)
+	}
+	return bid, ok
+}
+
+// bidOutcome reports whether updateType resolves the bidding on an intent and, if so, whether it was won
+func bidOutcome(updateType string) (won, resolved bool) {
+	switch {
+	case strings.EqualFold(updateType, IntentUpdateBidWon):
+		return true, true
+	case strings.EqualFold(updateType, IntentUpdateBidLost):
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// trackBid records an accepted bid so its outcome can be reported when the matcher resolves the intent
+func (sdk *SDK) trackBid(intentID string, ack *BidAck, price uint64) {
+	if !ack.Accepted {
+		return
+	}
+	sdk.openBids.record(intentID, openBid{bidID: ack.BidID, price: price, placedAt: time.Now()})
+}
+
+// handleBidOutcome fires OnBidWon or OnBidLost for an outcome update on an intent the agent bid on.
+// Outcomes for intents the agent did not bid on are ignored.
+func (sdk *SDK) handleBidOutcome(intentID string, won bool) {
+	open, ok := sdk.openBids.resolve(intentID)
+	if !ok {
+		sdk.logger.Debug("Ignoring bid outcome for intent without an open bid", "intent_id", intentID)
+		return
+	}
+
+	if won {
+		sdk.metrics.RecordEarnings(open.price)
+		sdk.logger.Info("Bid won", "intent_id", intentID, "bid_id", open.bidID, "price", open.price)
+		sdk.fireCallback("OnBidWon", intentID)
+	} else {
+		sdk.logger.Info("Bid lost", "intent_id", intentID, "bid_id", open.bidID)
+		sdk.fireCallback("OnBidLost", intentID)
+	}
+}
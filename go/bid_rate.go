@@ -0,0 +1,51 @@
+package agentsdk
+
+import (
+	"sync"
+	"time"
+)
+
+// bidLimiter is a token bucket limiting how fast strategy bids are submitted. It refills at rate
+// tokens per second up to burst; a nil limiter allows every bid.
+type bidLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newBidLimiter returns a limiter for perSecond bids with bursts of up to burst, or nil when
+// perSecond is not positive
+func newBidLimiter(perSecond, burst int) *bidLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = perSecond
+	}
+	return &bidLimiter{rate: float64(perSecond), burst: float64(burst), tokens: float64(burst)}
+}
+
+// allow takes a token if one is available at now
+func (l *bidLimiter) allow(now time.Time) bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
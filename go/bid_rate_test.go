@@ -0,0 +1,64 @@
+package agentsdk
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "subnet/proto/subnet"
+)
+
+func TestBidLimiterRefillsUpToBurst(t *testing.T) {
+	limiter := newBidLimiter(2, 3)
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		if !limiter.allow(now) {
+			t.Fatalf("expected bid %d of the burst to be allowed", i+1)
+		}
+	}
+	if limiter.allow(now) {
+		t.Fatal("expected the bid after the burst to be limited")
+	}
+	if !limiter.allow(now.Add(500 * time.Millisecond)) {
+		t.Fatal("expected a token after half a second at 2 bids per second")
+	}
+	if limiter.allow(now.Add(600 * time.Millisecond)) {
+		t.Fatal("expected the refilled token to be spent")
+	}
+
+	// A long pause refills no more than the burst
+	later := now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		limiter.allow(later)
+	}
+	if limiter.allow(later) {
+		t.Fatal("expected tokens to be capped at the burst")
+	}
+
+	if !newBidLimiter(0, 0).allow(now) {
+		t.Fatal("expected a disabled limiter to allow every bid")
+	}
+}
+
+func TestBidRateLimitDropsExcessStrategyBids(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.BidRatePerSecond = 1
+		cfg.BidRateBurst = 2
+	})
+	matcher := &fakeMatcherService{bidAck: &pb.BidSubmissionAck{Accepted: true}}
+	sdk.matcherClient = &MatcherClient{client: matcher, logger: sdk.logger}
+	sdk.RegisterBiddingStrategy(fixedPriceStrategy(100))
+	sdk.running.Store(true)
+
+	for i := 0; i < 5; i++ {
+		sdk.handleIntentUpdate(context.Background(), &pb.MatcherIntentUpdate{IntentId: fmt.Sprintf("intent-%d", i), UpdateType: "new"})
+	}
+
+	if len(matcher.bids) != 2 {
+		t.Fatalf("expected the burst of 2 bids to be submitted, got %d", len(matcher.bids))
+	}
+	if snap := sdk.metrics.Snapshot(); snap.BidsRateLimited != 3 || snap.TotalBids != 2 {
+		t.Fatalf("expected 3 rate-limited bids, got %+v", snap)
+	}
+}
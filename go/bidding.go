@@ -0,0 +1,77 @@
+package agentsdk
+
+import "time"
+
+// Built-in bidding strategy names accepted by WithBiddingStrategy
+const (
+	BiddingStrategyFixed   = "fixed"
+	BiddingStrategyDynamic = "dynamic"
+)
+
+// defaultDynamicBidRamp is how old an intent must be for DynamicBidStrategy to bid its maximum price
+const defaultDynamicBidRamp = 10 * time.Second
+
+// FixedBidStrategy bids Price on every intent
+type FixedBidStrategy struct {
+	Price    uint64
+	Currency string
+}
+
+// ShouldBid bids on every intent
+func (s *FixedBidStrategy) ShouldBid(intent *Intent) bool { return true }
+
+// CalculateBid returns a bid at the fixed price
+func (s *FixedBidStrategy) CalculateBid(intent *Intent) *Bid {
+	return &Bid{Price: s.Price, Currency: s.Currency}
+}
+
+// DynamicBidStrategy bids MinPrice on a new intent and raises the price linearly with the intent's
+// age, reaching MaxPrice once the intent is Ramp old (default 10s). Intents without a creation
+// time get MinPrice.
+type DynamicBidStrategy struct {
+	MinPrice uint64
+	MaxPrice uint64
+	Ramp     time.Duration
+	Currency string
+}
+
+// ShouldBid bids on every intent
+func (s *DynamicBidStrategy) ShouldBid(intent *Intent) bool { return true }
+
+// CalculateBid prices the bid by the intent's age
+func (s *DynamicBidStrategy) CalculateBid(intent *Intent) *Bid {
+	var age time.Duration
+	if !intent.CreatedAt.IsZero() {
+		age = time.Since(intent.CreatedAt)
+	}
+	return &Bid{Price: s.priceAt(age), Currency: s.Currency}
+}
+
+// priceAt interpolates between MinPrice and MaxPrice for an intent of the given age
+func (s *DynamicBidStrategy) priceAt(age time.Duration) uint64 {
+	ramp := s.Ramp
+	if ramp <= 0 {
+		ramp = defaultDynamicBidRamp
+	}
+	switch {
+	case s.MaxPrice <= s.MinPrice || age <= 0:
+		return s.MinPrice
+	case age >= ramp:
+		return s.MaxPrice
+	}
+	spread := float64(s.MaxPrice - s.MinPrice)
+	return s.MinPrice + uint64(spread*float64(age)/float64(ramp))
+}
+
+// newBuiltinBiddingStrategy returns the strategy named by config.BiddingStrategy, or nil when the
+// name is empty or not built in
+func newBuiltinBiddingStrategy(config *Config) BiddingStrategy {
+	switch config.BiddingStrategy {
+	case BiddingStrategyFixed:
+		return &FixedBidStrategy{Price: config.MinBidPrice}
+	case BiddingStrategyDynamic:
+		return &DynamicBidStrategy{MinPrice: config.MinBidPrice, MaxPrice: config.MaxBidPrice}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+package agentsdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDynamicBidStrategyInterpolatesByAge(t *testing.T) {
+	strategy := &DynamicBidStrategy{MinPrice: 100, MaxPrice: 1100}
+	for age, want := range map[time.Duration]uint64{
+		0:                100,
+		5 * time.Second:  600,
+		10 * time.Second: 1100,
+		time.Hour:        1100,
+	} {
+		if got := strategy.priceAt(age); got != want {
+			t.Errorf("price at age %v = %d, want %d", age, got, want)
+		}
+	}
+
+	if bid := strategy.CalculateBid(&Intent{ID: "intent-1"}); bid.Price != 100 {
+		t.Fatalf("expected an intent without a creation time to get the minimum price, got %d", bid.Price)
+	}
+}
+
+func TestConfiguredBiddingStrategyUsedUntilOneIsRegistered(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.BiddingStrategy = BiddingStrategyFixed
+		cfg.MinBidPrice = 250
+	})
+	fixed, ok := sdk.currentBiddingStrategy().(*FixedBidStrategy)
+	if !ok || fixed.Price != 250 {
+		t.Fatalf("expected a fixed strategy bidding the minimum price, got %#v", sdk.currentBiddingStrategy())
+	}
+
+	sdk.RegisterBiddingStrategy(decliningStrategy{})
+	if _, ok := sdk.currentBiddingStrategy().(decliningStrategy); !ok {
+		t.Fatalf("expected the registered strategy to take precedence, got %#v", sdk.currentBiddingStrategy())
+	}
+
+	if sdk := newTestSDK(t, nil); sdk.currentBiddingStrategy() != nil {
+		t.Fatalf("expected no strategy when none is configured, got %#v", sdk.currentBiddingStrategy())
+	}
+}
+
+func TestDefaultConfigsDoNotEnableBuiltinBidding(t *testing.T) {
+	defaults := DefaultConfig()
+	defaults.Identity = &IdentityConfig{SubnetID: "subnet-1", AgentID: "agent-1"}
+	defaults.AgentID = "agent-1"
+	defaults.MatcherAddr = "matcher:8090"
+	defaults.Capabilities = []string{"compute"}
+
+	built, err := NewConfigBuilder().
+		WithSubnetID("subnet-1").
+		WithAgentID("agent-1").
+		WithMatcherAddr("matcher:8090").
+		WithCapabilities("compute").
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+
+	for name, cfg := range map[string]*Config{"DefaultConfig": defaults, "Build": built} {
+		sdk, err := New(cfg)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if cfg.BiddingStrategy != "" || sdk.currentBiddingStrategy() != nil {
+			t.Fatalf("%s: expected agents without a strategy not to bid, got %q %#v", name, cfg.BiddingStrategy, sdk.currentBiddingStrategy())
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package agentsdk
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RegisterCapabilities registers the configured capabilities, stake amount and owner with the
+// registry so the agent is allowed to bid, and returns the permissions the registry granted. The
+// request is signed with the agent key; see RegisterCapabilitiesRequest.Marshal for the payload.
+func (sdk *SDK) RegisterCapabilities(ctx context.Context) (*RegisterCapabilitiesResponse, error) {
+	if sdk.config.RegistryAddr == "" {
+		return nil, errors.New("registry_addr not configured")
+	}
+	if sdk.privateKey == nil {
+		return nil, fmt.Errorf("register capabilities: %w", ErrNoPrivateKey)
+	}
+
+	request := &RegisterCapabilitiesRequest{
+		AgentId:      sdk.registryAgentID(),
+		Capabilities: append([]string{}, sdk.config.Capabilities...),
+		StakeAmount:  sdk.config.StakeAmount,
+		Owner:        sdk.config.Owner,
+		Timestamp:    time.Now().Unix(),
+	}
+	payload, err := request.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("marshal capabilities request: %w", err)
+	}
+	if request.Signature, err = signMessage(sdk.privateKey, payload, sdk.config.SignatureScheme, sdk.config.EVMRecoveryID); err != nil {
+		return nil, fmt.Errorf("sign capabilities request: %w", err)
+	}
+
+	return sdk.postCapabilities(ctx, request)
+}
+
+// postCapabilities sends a signed capabilities request to the registry
+func (sdk *SDK) postCapabilities(ctx context.Context, request *RegisterCapabilitiesRequest) (*RegisterCapabilitiesResponse, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"agent_id":     request.AgentId,
+		"capabilities": request.Capabilities,
+		"stake_amount": request.StakeAmount,
+		"owner":        request.Owner,
+		"timestamp":    request.Timestamp,
+		"signature":    hex.EncodeToString(request.Signature),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := sdk.newRegistryRequest(ctx, http.MethodPost, "/agents/"+request.AgentId+"/capabilities", body)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	resp, err := sdk.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("register capabilities: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var reply struct {
+		Success            bool     `json:"success"`
+		Message            string   `json:"message"`
+		GrantedPermissions []string `json:"granted_permissions"`
+	}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&reply)
+	if resp.StatusCode >= 300 {
+		if reply.Message != "" {
+			return nil, fmt.Errorf("register capabilities: registry returned %s: %s", resp.Status, reply.Message)
+		}
+		return nil, fmt.Errorf("register capabilities: registry returned %s", resp.Status)
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("register capabilities: decode response: %w", decodeErr)
+	}
+
+	response := &RegisterCapabilitiesResponse{
+		Success:            reply.Success,
+		Message:            reply.Message,
+		GrantedPermissions: reply.GrantedPermissions,
+	}
+	if !response.Success {
+		return response, fmt.Errorf("register capabilities: registry declined: %s", response.Message)
+	}
+	return response, nil
+}
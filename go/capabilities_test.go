@@ -0,0 +1,122 @@
+package agentsdk
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestRegisterCapabilitiesReturnsGrantedPermissions(t *testing.T) {
+	var body map[string]interface{}
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/agents/agent-1/capabilities" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Write([]byte(`{"success":true,"granted_permissions":["bid","report"]}`))
+	}))
+	defer registry.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.RegistryAddr = registry.URL
+		cfg.AgentEndpoint = "http://agent:9000"
+		cfg.PrivateKey = testPrivateKey
+		cfg.StakeAmount = 1000
+		cfg.Owner = "0xowner"
+	})
+	resp, err := sdk.RegisterCapabilities(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(resp.GrantedPermissions, []string{"bid", "report"}) {
+		t.Fatalf("unexpected granted permissions %v", resp.GrantedPermissions)
+	}
+	if body["owner"] != "0xowner" || body["stake_amount"] != float64(1000) || body["signature"] == "" {
+		t.Fatalf("unexpected request body %v", body)
+	}
+}
+
+func TestRegisterCapabilitiesSurfacesRegistryRejection(t *testing.T) {
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusPaymentRequired)
+		w.Write([]byte(`{"success":false,"message":"stake below minimum"}`))
+	}))
+	defer registry.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.RegistryAddr = registry.URL
+		cfg.AgentEndpoint = "http://agent:9000"
+		cfg.PrivateKey = testPrivateKey
+	})
+	if _, err := sdk.RegisterCapabilities(context.Background()); err == nil || !strings.Contains(err.Error(), "stake below minimum") {
+		t.Fatalf("expected the registry's message in the error, got %v", err)
+	}
+}
+
+func TestRegisterCapabilitiesSignatureRecoversAgentAddress(t *testing.T) {
+	var body struct {
+		AgentID      string   `json:"agent_id"`
+		Capabilities []string `json:"capabilities"`
+		StakeAmount  uint64   `json:"stake_amount"`
+		Owner        string   `json:"owner"`
+		Timestamp    int64    `json:"timestamp"`
+		Signature    string   `json:"signature"`
+	}
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer registry.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.RegistryAddr = registry.URL
+		cfg.AgentEndpoint = "http://agent:9000"
+		cfg.PrivateKey = testPrivateKey
+		cfg.StakeAmount = 1000
+		cfg.Owner = "0xowner"
+	})
+	if _, err := sdk.RegisterCapabilities(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	request := &RegisterCapabilitiesRequest{
+		AgentId:      body.AgentID,
+		Capabilities: body.Capabilities,
+		StakeAmount:  body.StakeAmount,
+		Owner:        body.Owner,
+		Timestamp:    body.Timestamp,
+	}
+	payload, err := request.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	want := `{"agent_id":"agent-1","capabilities":["compute"],"owner":"0xowner","stake_amount":1000,"timestamp":` +
+		strconv.FormatInt(body.Timestamp, 10) + `}`
+	if string(payload) != want {
+		t.Fatalf("unexpected canonical payload %s", payload)
+	}
+
+	signature, err := hex.DecodeString(body.Signature)
+	if err != nil {
+		t.Fatalf("unexpected signature encoding: %v", err)
+	}
+	pub, err := crypto.SigToPub(crypto.Keccak256(payload), signature)
+	if err != nil {
+		t.Fatalf("unexpected recovery error: %v", err)
+	}
+	if got := crypto.PubkeyToAddress(*pub).Hex(); got != sdk.GetAddress() {
+		t.Fatalf("recovered %s, expected %s", got, sdk.GetAddress())
+	}
+}
@@ -2,7 +2,11 @@ package agentsdk
 
 import (
 	"fmt"
+	"io"
+	"net/http"
 	"time"
+
+	"google.golang.org/grpc/metadata"
 )
 
 // ConfigBuilder provides a fluent interface for building SDK configuration
@@ -51,6 +55,28 @@ func (b *ConfigBuilder) WithPrivateKey(privateKey string) *ConfigBuilder {
 	return b
 }
 
+// WithSignedAgentInfo binds the agent's capabilities and the SDK version into every gRPC request
+// signature, so validators can reject requests whose declared capabilities were altered. The
+// server must build the canonical payload with the same fields.
+func (b *ConfigBuilder) WithSignedAgentInfo(enabled bool) *ConfigBuilder {
+	b.config.SignAgentInfo = enabled
+	return b
+}
+
+// WithSignatureScheme selects how gRPC requests, execution reports and Sign are signed:
+// SignatureSchemeRawKeccak (default) or SignatureSchemeEIP191 for personal_sign style signatures.
+func (b *ConfigBuilder) WithSignatureScheme(scheme string) *ConfigBuilder {
+	b.config.SignatureScheme = scheme
+	return b
+}
+
+// WithEVMRecoveryID makes request, report and Sign signatures end in a recovery id of 27/28, as
+// EVM ecrecover expects, instead of go-ethereum's 0/1. EIP-191 signatures always use 27/28.
+func (b *ConfigBuilder) WithEVMRecoveryID(enabled bool) *ConfigBuilder {
+	b.config.EVMRecoveryID = enabled
+	return b
+}
+
 // WithChainAddress sets the on-chain address used for metadata enrichment.
 func (b *ConfigBuilder) WithChainAddress(addr string) *ConfigBuilder {
 	b.config.ChainAddress = addr
@@ -63,12 +89,25 @@ func (b *ConfigBuilder) WithMatcherAddr(addr string) *ConfigBuilder {
 	return b
 }
 
+// WithValidatorAddrs adds validator addresses for redundancy. Streamed task reports are submitted
+// over gRPC to each address in order until one accepts; ValidatorAddr, if set, is tried first.
+func (b *ConfigBuilder) WithValidatorAddrs(addrs ...string) *ConfigBuilder {
+	b.config.ValidatorAddrs = append(b.config.ValidatorAddrs, addrs...)
+	return b
+}
+
 // WithValidatorAddr sets the validator address
 func (b *ConfigBuilder) WithValidatorAddr(addr string) *ConfigBuilder {
 	b.config.ValidatorAddr = addr
 	return b
 }
 
+// WithValidatorEndpointResolver sets a custom validator discovery function used instead of the registry
+func (b *ConfigBuilder) WithValidatorEndpointResolver(resolver ValidatorEndpointResolver) *ConfigBuilder {
+	b.config.ValidatorEndpointResolver = resolver
+	return b
+}
+
 // WithRegistryAddr sets the registry service address
 func (b *ConfigBuilder) WithRegistryAddr(addr string) *ConfigBuilder {
 	b.config.RegistryAddr = addr
@@ -81,13 +120,26 @@ func (b *ConfigBuilder) WithAgentEndpoint(endpoint string) *ConfigBuilder {
 	return b
 }
 
-// WithCapabilities sets the agent capabilities
+// WithOutgoingMetadata sets custom gRPC metadata sent on every matcher and validator RPC
+func (b *ConfigBuilder) WithOutgoingMetadata(md map[string]string) *ConfigBuilder {
+	b.config.OutgoingMetadata = md
+	return b
+}
+
+// WithOutgoingMetadataProvider sets a function producing gRPC metadata evaluated on every RPC
+func (b *ConfigBuilder) WithOutgoingMetadataProvider(provider func() metadata.MD) *ConfigBuilder {
+	b.config.OutgoingMetadataProvider = provider
+	return b
+}
+
+// WithCapabilities sets the agent capabilities. Validation trims them, drops blanks and
+// silently collapses duplicates into a sorted list.
 func (b *ConfigBuilder) WithCapabilities(capabilities ...string) *ConfigBuilder {
 	b.config.Capabilities = capabilities
 	return b
 }
 
-// AddCapability adds a single capability
+// AddCapability adds a single capability; adding one twice has no effect
 func (b *ConfigBuilder) AddCapability(capability string) *ConfigBuilder {
 	b.config.Capabilities = append(b.config.Capabilities, capability)
 	return b
@@ -113,7 +165,112 @@ func (b *ConfigBuilder) WithBidTimeout(timeout time.Duration) *ConfigBuilder {
 	return b
 }
 
-// WithMaxConcurrentTasks sets the maximum concurrent tasks
+// WithStreamReceiveTimeout bounds each matcher stream Recv; a timeout tears down and reconnects the stream.
+// Set it above the longest expected gap between tasks or intents, as idle streams also trigger it.
+func (b *ConfigBuilder) WithStreamReceiveTimeout(timeout time.Duration) *ConfigBuilder {
+	b.config.StreamReceiveTimeout = timeout
+	return b
+}
+
+// WithBidResponseTimeout bounds how long the SDK waits for the matcher to acknowledge a bid.
+// Defaults to the bid timeout.
+func (b *ConfigBuilder) WithBidResponseTimeout(timeout time.Duration) *ConfigBuilder {
+	b.config.BidResponseTimeout = timeout
+	return b
+}
+
+// WithMatcherSubscriptionRetry sets how many consecutive times a rejected matcher stream subscription
+// (e.g. an authentication failure) is attempted before the stream gives up (default 3; 1 fails fast).
+// Transient errors on established streams always reconnect with the reconnect backoff.
+func (b *ConfigBuilder) WithMatcherSubscriptionRetry(maxAttempts int) *ConfigBuilder {
+	b.config.MatcherSubscriptionAttempts = maxAttempts
+	return b
+}
+
+// WithReconnectBackoff sets the matcher stream reconnect backoff.
+// Delays start at initial, double up to max with ±20% jitter, and reset after a stream stays up for a minute.
+func (b *ConfigBuilder) WithReconnectBackoff(initial, max time.Duration) *ConfigBuilder {
+	b.config.ReconnectInitialBackoff = initial
+	b.config.ReconnectMaxBackoff = max
+	return b
+}
+
+// WithShutdownTimeout bounds how long Stop waits for in-flight tasks to finish and report (default 30s)
+func (b *ConfigBuilder) WithShutdownTimeout(timeout time.Duration) *ConfigBuilder {
+	b.config.ShutdownTimeout = timeout
+	return b
+}
+
+// WithReportOverflowPolicy sets what happens when the async report queue is full:
+// ReportOverflowBlock (default) waits for space, ReportOverflowDropOldest and ReportOverflowDropNewest
+// discard a report, count it in Metrics.ReportsDropped and fire OnReportDropped.
+func (b *ConfigBuilder) WithReportOverflowPolicy(policy string) *ConfigBuilder {
+	b.config.ReportOverflowPolicy = policy
+	return b
+}
+
+// WithReportRetries retries failed report submissions up to maxRetries times per validator,
+// starting at backoff and doubling between attempts. Retries are disabled by default.
+func (b *ConfigBuilder) WithReportRetries(maxRetries int, backoff time.Duration) *ConfigBuilder {
+	b.config.ReportMaxRetries = maxRetries
+	b.config.ReportRetryBackoff = backoff
+	return b
+}
+
+// WithReportRetryJitter spreads report retry delays by ±fraction (default 0.2; negative disables)
+func (b *ConfigBuilder) WithReportRetryJitter(fraction float64) *ConfigBuilder {
+	b.config.ReportRetryJitter = fraction
+	return b
+}
+
+// WithLogger routes all SDK logging through logger instead of the standard log package.
+// LogLevel still applies: messages below it are dropped before reaching logger.
+func (b *ConfigBuilder) WithLogger(logger Logger) *ConfigBuilder {
+	b.config.Logger = logger
+	return b
+}
+
+// WithValidatorSetCacheTTL sets how long the validator set is cached (default 30s)
+func (b *ConfigBuilder) WithValidatorSetCacheTTL(ttl time.Duration) *ConfigBuilder {
+	b.config.ValidatorSetCacheTTL = ttl
+	return b
+}
+
+// WithValidatorCacheTTL sets how long validators discovered for report fan-out are cached (default 15s).
+// The cache is dropped early when a report to one of the cached validators fails.
+func (b *ConfigBuilder) WithValidatorCacheTTL(ttl time.Duration) *ConfigBuilder {
+	b.config.ValidatorCacheTTL = ttl
+	return b
+}
+
+// WithTaskStreamBuffer sets how many tasks are received from the matcher ahead of dispatch (default 0).
+// The task stream stops reading once MaxConcurrentTasks tasks run and TaskQueueDepth more wait for a
+// slot; beyond this buffer gRPC flow control then holds further tasks at the matcher.
+func (b *ConfigBuilder) WithTaskStreamBuffer(size int) *ConfigBuilder {
+	b.config.TaskStreamBuffer = size
+	return b
+}
+
+// WithMaxConcurrentTasksForType limits concurrent tasks of one type on top of MaxConcurrentTasks.
+// A task is accepted only when both a global slot and a slot for its type are free.
+func (b *ConfigBuilder) WithMaxConcurrentTasksForType(taskType string, n int) *ConfigBuilder {
+	if b.config.MaxConcurrentTasksPerType == nil {
+		b.config.MaxConcurrentTasksPerType = make(map[string]int)
+	}
+	b.config.MaxConcurrentTasksPerType[taskType] = n
+	return b
+}
+
+// WithTaskQueueDepth limits how many tasks may wait for an execution slot while all
+// MaxConcurrentTasks are busy (default 100). Waiting tasks get slots soonest deadline first;
+// tasks arriving with the queue full are rejected as "task queue full".
+func (b *ConfigBuilder) WithTaskQueueDepth(depth int) *ConfigBuilder {
+	b.config.TaskQueueDepth = depth
+	return b
+}
+
+// WithMaxConcurrentTasks sets the maximum concurrent tasks.
+// Streamed tasks wait up to BidTimeout for a free slot and are rejected as "at capacity" otherwise.
 func (b *ConfigBuilder) WithMaxConcurrentTasks(max int) *ConfigBuilder {
 	b.config.MaxConcurrentTasks = max
 	return b
@@ -145,7 +302,193 @@ func (b *ConfigBuilder) WithRegistryHeartbeatInterval(interval time.Duration) *C
 	return b
 }
 
-// WithTLS enables TLS with the provided certificates
+// WithRegistryToken authenticates every registry request with "Authorization: Bearer <token>"
+func (b *ConfigBuilder) WithRegistryToken(token string) *ConfigBuilder {
+	b.config.RegistryToken = token
+	return b
+}
+
+// WithRegistryTokenProvider fetches the registry bearer token for every request, e.g. to rotate
+// short-lived tokens. It takes precedence over WithRegistryToken.
+func (b *ConfigBuilder) WithRegistryTokenProvider(provider RegistryTokenProvider) *ConfigBuilder {
+	b.config.RegistryTokenProvider = provider
+	return b
+}
+
+// WithStrictRegistryAuth makes configuration validation fail when an https registry has no token
+func (b *ConfigBuilder) WithStrictRegistryAuth(strict bool) *ConfigBuilder {
+	b.config.StrictRegistryAuth = strict
+	return b
+}
+
+// WithRegistryOptional lets Start succeed when the registry is unreachable: registration is retried
+// in the background and heartbeats begin once it succeeds, while the matcher streams run regardless
+func (b *ConfigBuilder) WithRegistryOptional(optional bool) *ConfigBuilder {
+	b.config.RegistryOptional = optional
+	return b
+}
+
+// WithHTTPClient sets the HTTP client used for registry and validator calls, e.g. to configure a
+// proxy or custom TLS. It takes precedence over WithHTTPTimeout.
+func (b *ConfigBuilder) WithHTTPClient(client *http.Client) *ConfigBuilder {
+	b.config.HTTPClient = client
+	return b
+}
+
+// WithHTTPTimeout sets the timeout for each registry and validator HTTP call (default 10s)
+func (b *ConfigBuilder) WithHTTPTimeout(timeout time.Duration) *ConfigBuilder {
+	b.config.HTTPTimeout = timeout
+	return b
+}
+
+// WithReportMaxPayloadSize sets the largest encoded execution report, in bytes, the SDK will send
+func (b *ConfigBuilder) WithReportMaxPayloadSize(bytes int) *ConfigBuilder {
+	b.config.ReportMaxPayloadSize = bytes
+	return b
+}
+
+// WithReportPath sets the path execution reports are posted to on validator HTTP endpoints,
+// replacing the default "/api/v1/execution-report" for validators behind custom gateway routes
+func (b *ConfigBuilder) WithReportPath(path string) *ConfigBuilder {
+	b.config.ReportPath = path
+	return b
+}
+
+// WithReportScheme sets the scheme ("http" or "https", default "http") for validator endpoints that
+// do not name one. Endpoints with an explicit scheme keep it.
+func (b *ConfigBuilder) WithReportScheme(scheme string) *ConfigBuilder {
+	b.config.ReportScheme = scheme
+	return b
+}
+
+// WithReportCompletionCallback sets a callback fired when a task's execution report submission finishes
+func (b *ConfigBuilder) WithReportCompletionCallback(callback ReportCompletionCallback) *ConfigBuilder {
+	b.config.ReportCompletionCallback = callback
+	return b
+}
+
+// WithReportFinalizer sets a finalizer fired once a streamed task's report has been accepted by
+// threshold validators (default 1). Reports are submitted over gRPC to configured validators in order
+// until threshold receipts are collected.
+func (b *ConfigBuilder) WithReportFinalizer(threshold int, finalizer ReportFinalizer) *ConfigBuilder {
+	b.config.ReportFinalizeThreshold = threshold
+	b.config.ReportFinalizer = finalizer
+	return b
+}
+
+// WithTracer records a span per streamed task (execution through report submission) and
+// propagates its trace context to validators. See the API reference for an OpenTelemetry adapter.
+func (b *ConfigBuilder) WithTracer(tracer Tracer) *ConfigBuilder {
+	b.config.Tracer = tracer
+	return b
+}
+
+// WithBidDryRun evaluates the bidding strategy against live intents without submitting its bids.
+// Each bid it would place fires OnBidSubmitted with Bid.DryRun set and is counted in SimulatedBids
+// instead of TotalBids. Bids passed to SubmitBid are still submitted.
+func (b *ConfigBuilder) WithBidDryRun(enabled bool) *ConfigBuilder {
+	b.config.BidDryRun = enabled
+	return b
+}
+
+// WithBidRateLimit caps strategy bid submissions at perSecond, allowing bursts of up to burst bids
+// (default perSecond). Bids over the limit are dropped and counted in BidsRateLimited. Bids passed to
+// SubmitBid are not limited.
+func (b *ConfigBuilder) WithBidRateLimit(perSecond int, burst int) *ConfigBuilder {
+	b.config.BidRatePerSecond = perSecond
+	b.config.BidRateBurst = burst
+	return b
+}
+
+// WithBidBatching queues strategy bids and submits them with SubmitBidBatch, flushing once maxBatch
+// bids are pending or every flushInterval. Bids are submitted one by one by default.
+func (b *ConfigBuilder) WithBidBatching(maxBatch int, flushInterval time.Duration) *ConfigBuilder {
+	b.config.BidBatchSize = maxBatch
+	b.config.BidBatchFlushInterval = flushInterval
+	return b
+}
+
+// WithReportBatching buffers execution reports for streamed tasks and submits them with
+// SubmitExecutionReportBatch, flushing once maxBatch reports are pending or every flushInterval.
+// Reports are submitted one by one by default.
+func (b *ConfigBuilder) WithReportBatching(maxBatch int, flushInterval time.Duration) *ConfigBuilder {
+	b.config.ReportBatchSize = maxBatch
+	b.config.ReportBatchFlushInterval = flushInterval
+	return b
+}
+
+// WithHeartbeatFailureThreshold sets how many consecutive heartbeat failures fire OnError and
+// trigger a re-registration with the registry (default 3; negative disables)
+func (b *ConfigBuilder) WithHeartbeatFailureThreshold(n int) *ConfigBuilder {
+	b.config.HeartbeatFailureThreshold = n
+	return b
+}
+
+// WithHeartbeatReregisterStatuses sets the heartbeat response statuses meaning the registry no
+// longer knows the agent, which trigger an immediate re-registration (default 404)
+func (b *ConfigBuilder) WithHeartbeatReregisterStatuses(codes ...int) *ConfigBuilder {
+	b.config.HeartbeatReregisterStatuses = codes
+	return b
+}
+
+// WithHeartbeatMetadata attaches custom key/values to every registry heartbeat, alongside the
+// agent's status, in-flight task count and capabilities
+func (b *ConfigBuilder) WithHeartbeatMetadata(md map[string]string) *ConfigBuilder {
+	b.config.HeartbeatMetadata = md
+	return b
+}
+
+// WithHealthAddr serves Kubernetes-style probes on addr (e.g. ":8080") while the SDK runs:
+// /healthz reports the process is alive and /readyz reports whether the SDK can take tasks.
+// /metrics.json serves the metrics snapshot and agent status as JSON.
+func (b *ConfigBuilder) WithHealthAddr(addr string) *ConfigBuilder {
+	b.config.HealthAddr = addr
+	return b
+}
+
+// WithTaskDedupWindow sets how long processed task IDs are remembered so tasks re-delivered by the
+// matcher (e.g. after a reconnect) are skipped instead of executed twice (default 10m; negative disables)
+func (b *ConfigBuilder) WithTaskDedupWindow(window time.Duration) *ConfigBuilder {
+	b.config.TaskDedupWindow = window
+	return b
+}
+
+// WithValidatorWeights sends each execution report to a weighted validator with the given
+// probability, e.g. 0.1 for a canary that should see ~10% of reports. Keys are validator IDs or
+// endpoints; unlisted validators have weight 1.0 (always included).
+func (b *ConfigBuilder) WithValidatorWeights(weights map[string]float64) *ConfigBuilder {
+	b.config.ValidatorWeights = weights
+	return b
+}
+
+// WithReportMetadataAllowlist limits execution report metadata to the given keys; other keys
+// supplied by the caller are dropped. An empty allowlist (the default) allows all keys.
+func (b *ConfigBuilder) WithReportMetadataAllowlist(keys ...string) *ConfigBuilder {
+	b.config.ReportMetadataAllowlist = keys
+	return b
+}
+
+// WithReportMetadataDenylist drops the given keys from execution report metadata
+func (b *ConfigBuilder) WithReportMetadataDenylist(keys ...string) *ConfigBuilder {
+	b.config.ReportMetadataDenylist = keys
+	return b
+}
+
+// WithResultHash includes a hash ("keccak256" or "sha256") of the result data in every execution report
+func (b *ConfigBuilder) WithResultHash(algorithm string) *ConfigBuilder {
+	b.config.ResultHashAlgorithm = algorithm
+	return b
+}
+
+// WithResultCompression gzips result data larger than threshold bytes before it is reported; see
+// ResultEncodingGzip for how validators are told. 0 disables compression.
+func (b *ConfigBuilder) WithResultCompression(threshold int) *ConfigBuilder {
+	b.config.ResultCompressionThreshold = threshold
+	return b
+}
+
+// WithTLS enables TLS for gRPC connections; certFile/keyFile, when set, are presented as the
+// client certificate
 func (b *ConfigBuilder) WithTLS(certFile, keyFile string) *ConfigBuilder {
 	b.config.UseTLS = true
 	b.config.CertFile = certFile
@@ -153,7 +496,32 @@ func (b *ConfigBuilder) WithTLS(certFile, keyFile string) *ConfigBuilder {
 	return b
 }
 
-// WithLogLevel sets the logging level
+// WithMTLS enables mutual TLS: certFile/keyFile are presented as the client certificate and caFile
+// verifies the servers (system roots when empty)
+func (b *ConfigBuilder) WithMTLS(certFile, keyFile, caFile string) *ConfigBuilder {
+	b.config.UseTLS = true
+	b.config.CertFile = certFile
+	b.config.KeyFile = keyFile
+	b.config.CAFile = caFile
+	return b
+}
+
+// WithRootCAs verifies gRPC (when TLS is enabled) and https registry/validator servers against the
+// PEM certificates in caFile instead of the system roots
+func (b *ConfigBuilder) WithRootCAs(caFile string) *ConfigBuilder {
+	b.config.CAFile = caFile
+	return b
+}
+
+// WithInsecureSkipVerify disables TLS certificate verification for gRPC and HTTP connections so
+// the SDK can talk to self-signed servers. For local testing only; it has no effect unless TLS is
+// enabled and the SDK logs a warning on Start.
+func (b *ConfigBuilder) WithInsecureSkipVerify(skip bool) *ConfigBuilder {
+	b.config.InsecureSkipVerify = skip
+	return b
+}
+
+// WithLogLevel sets the logging level ("debug", "info", "warn" or "error") of the default logger
 func (b *ConfigBuilder) WithLogLevel(level string) *ConfigBuilder {
 	b.config.LogLevel = level
 	return b
@@ -165,6 +533,22 @@ func (b *ConfigBuilder) WithDataDir(dir string) *ConfigBuilder {
 	return b
 }
 
+// WithTaskResultPersistence persists successful task results to DataDir until their report is acknowledged.
+// Unacknowledged results are re-submitted on the next Start; results older than retention are discarded.
+func (b *ConfigBuilder) WithTaskResultPersistence(retention time.Duration) *ConfigBuilder {
+	b.config.PersistTaskResults = true
+	b.config.TaskResultRetention = retention
+	return b
+}
+
+// WithRandSource sets the entropy source for nonces, bid IDs, and report IDs.
+// The reader must be safe for concurrent use. Intended for tests needing reproducible output;
+// production agents should keep the crypto/rand default.
+func (b *ConfigBuilder) WithRandSource(source io.Reader) *ConfigBuilder {
+	b.config.RandSource = source
+	return b
+}
+
 // Build validates and returns the configuration
 func (b *ConfigBuilder) Build() (*Config, error) {
 	// Apply defaults
@@ -189,6 +573,10 @@ func (b *ConfigBuilder) MustBuild() *Config {
 
 // DefaultConfig returns a minimal default configuration
 // Users must still set: SubnetID, AgentID, PrivateKey, MatcherAddr, and Capabilities
+//
+// BiddingStrategy is left unset. It used to default to "fixed" when that name selected nothing;
+// it now selects the built-in FixedBidStrategy, so keeping the default would make every agent
+// without a registered strategy bid MinBidPrice on every intent.
 func DefaultConfig() *Config {
 	return &Config{
 		Identity: &IdentityConfig{},
@@ -199,7 +587,6 @@ func DefaultConfig() *Config {
 		MaxConcurrentTasks:        5,
 		TaskTimeout:               30 * time.Second,
 		BidTimeout:                5 * time.Second,
-		BiddingStrategy:           "fixed",
 		MinBidPrice:               100,
 		MaxBidPrice:               1000,
 		RegistryHeartbeatInterval: 30 * time.Second,
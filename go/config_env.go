@@ -0,0 +1,155 @@
+package agentsdk
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envPrefix prefixes every environment variable read by LoadConfigFromEnv
+const envPrefix = "PINAI_"
+
+// LoadConfigFromEnv builds a Config from PINAI_* environment variables, applies defaults and
+// validates it. Each config file key maps to PINAI_<KEY> (e.g. PINAI_MATCHER_ADDR,
+// PINAI_MAX_CONCURRENT_TASKS); PINAI_SUBNET_ID sets identity.subnet_id together with
+// PINAI_AGENT_ID. Lists are comma-separated, maps are comma-separated key=value pairs and
+// durations use Go syntax ("60s"). Errors name the offending variable.
+func LoadConfigFromEnv() (*Config, error) {
+	var fc fileConfig
+	if err := fc.loadEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	if subnetID, ok := lookupEnv(os.LookupEnv, envPrefix+"SUBNET_ID"); ok {
+		fc.Identity = &fileIdentity{SubnetID: fileString(subnetID), AgentID: fc.AgentID}
+	}
+
+	config, err := fc.toConfig(".")
+	if err != nil {
+		return nil, fmt.Errorf("%sPRIVATE_KEY_FILE: %w", envPrefix, err)
+	}
+
+	switch {
+	case fc.AgentID == "":
+		return nil, fmt.Errorf("%sAGENT_ID is required", envPrefix)
+	case config.MatcherAddr == "":
+		return nil, fmt.Errorf("%sMATCHER_ADDR is required", envPrefix)
+	case len(config.Capabilities) == 0:
+		return nil, fmt.Errorf("%sCAPABILITIES is required", envPrefix)
+	}
+
+	config.applyDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration from %s* environment: %w", envPrefix, err)
+	}
+	return config, nil
+}
+
+// loadEnv sets each top-level fileConfig field from PINAI_<JSON KEY>; nested sections are skipped
+func (fc *fileConfig) loadEnv(lookup func(string) (string, bool)) error {
+	v := reflect.ValueOf(fc).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type.Kind() == reflect.Pointer {
+			continue
+		}
+		name := envPrefix + strings.ToUpper(field.Tag.Get("json"))
+		value, ok := lookupEnv(lookup, name)
+		if !ok {
+			continue
+		}
+		if err := setEnvField(v.Field(i), value); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func lookupEnv(lookup func(string) (string, bool), name string) (string, bool) {
+	value, ok := lookup(name)
+	value = strings.TrimSpace(value)
+	return value, ok && value != ""
+}
+
+// setEnvField parses an environment value into a fileConfig field according to its type
+func setEnvField(field reflect.Value, value string) error {
+	if field.Type() == reflect.TypeOf(fileDuration(0)) {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q (use Go syntax such as \"60s\")", value)
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		field.SetInt(int64(n))
+	case reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", value)
+		}
+		field.SetUint(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		items := reflect.MakeSlice(field.Type(), 0, 0)
+		for _, item := range splitEnvList(value) {
+			elem := reflect.New(field.Type().Elem()).Elem()
+			if err := setEnvField(elem, item); err != nil {
+				return fmt.Errorf("item %q: %w", item, err)
+			}
+			items = reflect.Append(items, elem)
+		}
+		field.Set(items)
+	case reflect.Map:
+		entries := reflect.MakeMap(field.Type())
+		for _, pair := range splitEnvList(value) {
+			key, raw, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("invalid entry %q (expected key=value)", pair)
+			}
+			entry := reflect.New(field.Type().Elem()).Elem()
+			if err := setEnvField(entry, strings.TrimSpace(raw)); err != nil {
+				return fmt.Errorf("entry %q: %w", key, err)
+			}
+			entries.SetMapIndex(reflect.ValueOf(strings.TrimSpace(key)), entry)
+		}
+		field.Set(entries)
+	default:
+		return errors.New("unsupported field type")
+	}
+	return nil
+}
+
+// splitEnvList splits a comma-separated value, dropping empty items
+func splitEnvList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
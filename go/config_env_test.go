@@ -0,0 +1,58 @@
+package agentsdk
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfigFromEnv(t *testing.T) {
+	t.Setenv("PINAI_SUBNET_ID", "0x1")
+	t.Setenv("PINAI_AGENT_ID", "agent-1")
+	t.Setenv("PINAI_PRIVATE_KEY", testPrivateKey)
+	t.Setenv("PINAI_MATCHER_ADDR", "localhost:8090")
+	t.Setenv("PINAI_CAPABILITIES", "compute, ml")
+	t.Setenv("PINAI_MAX_CONCURRENT_TASKS", "8")
+	t.Setenv("PINAI_MAX_CONCURRENT_TASKS_PER_TYPE", "ml=2")
+	t.Setenv("PINAI_TASK_TIMEOUT", "90s")
+	t.Setenv("PINAI_USE_TLS", "false")
+	t.Setenv("PINAI_REPORT_RETRY_JITTER", "0.1")
+	t.Setenv("PINAI_HEARTBEAT_REREGISTER_STATUSES", "404, 410")
+
+	cfg, err := LoadConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Identity == nil || cfg.Identity.SubnetID != "0x1" || cfg.Identity.AgentID != "agent-1" {
+		t.Fatalf("unexpected identity %+v", cfg.Identity)
+	}
+	if !reflect.DeepEqual(cfg.Capabilities, []string{"compute", "ml"}) {
+		t.Fatalf("unexpected capabilities %v", cfg.Capabilities)
+	}
+	if cfg.MaxConcurrentTasks != 8 || cfg.MaxConcurrentTasksPerType["ml"] != 2 || cfg.TaskTimeout != 90*time.Second {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if cfg.ReportRetryJitter != 0.1 || cfg.BidTimeout != 5*time.Second {
+		t.Fatalf("expected parsed jitter and default bid timeout, got %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.HeartbeatReregisterStatuses, []int{404, 410}) {
+		t.Fatalf("unexpected heartbeat re-register statuses %v", cfg.HeartbeatReregisterStatuses)
+	}
+}
+
+func TestLoadConfigFromEnvNamesInvalidVariable(t *testing.T) {
+	t.Setenv("PINAI_AGENT_ID", "agent-1")
+	t.Setenv("PINAI_CAPABILITIES", "compute")
+
+	if _, err := LoadConfigFromEnv(); err == nil || !strings.Contains(err.Error(), "PINAI_MATCHER_ADDR is required") {
+		t.Fatalf("expected missing matcher address error, got %v", err)
+	}
+
+	t.Setenv("PINAI_MATCHER_ADDR", "localhost:8090")
+	t.Setenv("PINAI_BID_TIMEOUT", "5")
+	if _, err := LoadConfigFromEnv(); err == nil || !strings.Contains(err.Error(), "PINAI_BID_TIMEOUT: invalid duration") {
+		t.Fatalf("expected invalid duration error, got %v", err)
+	}
+}
@@ -0,0 +1,305 @@
+package agentsdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfigFromFile reads a YAML (.yaml, .yml) or JSON (.json) config file, applies defaults
+// and validates the result. Keys use the snake_case names shown in the docs, durations are
+// strings such as "60s", and private_key_file (resolved relative to the config file) can be
+// used instead of an inline private_key. Unknown keys are rejected to catch typos.
+func LoadConfigFromFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+
+	var raw []byte
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		// YAML is re-encoded as JSON so both formats share fileConfig's decoding and key checks
+		var tree any
+		if err := yaml.Unmarshal(data, &tree); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		if raw, err = json.Marshal(tree); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+	case ".json":
+		raw = data
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q (use .yaml, .yml or .json)", ext)
+	}
+
+	var fc fileConfig
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&fc); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	config, err := fc.toConfig(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", path, err)
+	}
+
+	config.applyDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return config, nil
+}
+
+// fileConfig is the on-disk representation of the serializable Config fields
+type fileConfig struct {
+	Identity       *fileIdentity `json:"identity"`
+	AgentID        fileString    `json:"agent_id"`
+	PrivateKey     fileString    `json:"private_key"`
+	PrivateKeyFile string        `json:"private_key_file"`
+	ChainAddress   fileString    `json:"chain_address"`
+	MatcherAddr    string        `json:"matcher_addr"`
+	ValidatorAddr  string        `json:"validator_addr"`
+	ValidatorAddrs []string      `json:"validator_addrs"`
+	RegistryAddr   string        `json:"registry_addr"`
+	AgentEndpoint  string        `json:"agent_endpoint"`
+	Capabilities   []fileString  `json:"capabilities"`
+	Timeouts       *fileTimeouts `json:"timeouts"`
+
+	MaxConcurrentTasks        int            `json:"max_concurrent_tasks"`
+	MaxConcurrentTasksPerType map[string]int `json:"max_concurrent_tasks_per_type"`
+	TaskQueueDepth            int            `json:"task_queue_depth"`
+	TaskTimeout               fileDuration   `json:"task_timeout"`
+	BidTimeout                fileDuration   `json:"bid_timeout"`
+	BidResponseTimeout        fileDuration   `json:"bid_response_timeout"`
+	BidBatchSize              int            `json:"bid_batch_size"`
+	BidBatchFlushInterval     fileDuration   `json:"bid_batch_flush_interval"`
+	BidDryRun                 bool           `json:"bid_dry_run"`
+	BidRatePerSecond          int            `json:"bid_rate_per_second"`
+	BidRateBurst              int            `json:"bid_rate_burst"`
+	BiddingStrategy           string         `json:"bidding_strategy"`
+	MinBidPrice               uint64         `json:"min_bid_price"`
+	MaxBidPrice               uint64         `json:"max_bid_price"`
+	Owner                     fileString     `json:"owner"`
+	StakeAmount               uint64         `json:"stake_amount"`
+
+	UseTLS             bool   `json:"use_tls"`
+	CertFile           string `json:"cert_file"`
+	KeyFile            string `json:"key_file"`
+	CAFile             string `json:"ca_file"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+	SignAgentInfo      bool   `json:"sign_agent_info"`
+	SignatureScheme    string `json:"signature_scheme"`
+	EVMRecoveryID      bool   `json:"evm_recovery_id"`
+
+	LogLevel   string `json:"log_level"`
+	DataDir    string `json:"data_dir"`
+	HealthAddr string `json:"health_addr"`
+
+	RegistryToken               string            `json:"registry_token"`
+	StrictRegistryAuth          bool              `json:"strict_registry_auth"`
+	RegistryOptional            bool              `json:"registry_optional"`
+	RegistryHeartbeatInterval   fileDuration      `json:"registry_heartbeat_interval"`
+	HeartbeatFailureThreshold   int               `json:"heartbeat_failure_threshold"`
+	HeartbeatMetadata           map[string]string `json:"heartbeat_metadata"`
+	HeartbeatReregisterStatuses []int             `json:"heartbeat_reregister_statuses"`
+	OutgoingMetadata            map[string]string `json:"outgoing_metadata"`
+	StreamReceiveTimeout        fileDuration      `json:"stream_receive_timeout"`
+	TaskStreamBuffer            int               `json:"task_stream_buffer"`
+	ReconnectInitialBackoff     fileDuration      `json:"reconnect_initial_backoff"`
+	ReconnectMaxBackoff         fileDuration      `json:"reconnect_max_backoff"`
+	MatcherSubscriptionAttempts int               `json:"matcher_subscription_attempts"`
+	ShutdownTimeout             fileDuration      `json:"shutdown_timeout"`
+	HTTPTimeout                 fileDuration      `json:"http_timeout"`
+	ValidatorSetCacheTTL        fileDuration      `json:"validator_set_cache_ttl"`
+	ValidatorCacheTTL           fileDuration      `json:"validator_cache_ttl"`
+
+	ReportMaxPayloadSize     int                `json:"report_max_payload_size"`
+	ReportPath               string             `json:"report_path"`
+	ReportScheme             string             `json:"report_scheme"`
+	ReportOverflowPolicy     string             `json:"report_overflow_policy"`
+	ReportMaxRetries         int                `json:"report_max_retries"`
+	ReportRetryBackoff       fileDuration       `json:"report_retry_backoff"`
+	ReportRetryJitter        float64            `json:"report_retry_jitter"`
+	ReportFinalizeThreshold  int                `json:"report_finalize_threshold"`
+	ReportBatchSize          int                `json:"report_batch_size"`
+	ReportBatchFlushInterval fileDuration       `json:"report_batch_flush_interval"`
+	ReportMetadataAllowlist  []string           `json:"report_metadata_allowlist"`
+	ReportMetadataDenylist   []string           `json:"report_metadata_denylist"`
+	ValidatorWeights         map[string]float64 `json:"validator_weights"`
+	TaskDedupWindow          fileDuration       `json:"task_dedup_window"`
+	ResultHashAlgorithm      string             `json:"result_hash_algorithm"`
+	ResultCompression        int                `json:"result_compression_threshold"`
+	PersistTaskResults       bool               `json:"persist_task_results"`
+	TaskResultRetention      fileDuration       `json:"task_result_retention"`
+}
+
+type fileIdentity struct {
+	SubnetID    fileString `json:"subnet_id"`
+	ValidatorID fileString `json:"validator_id"`
+	MatcherID   fileString `json:"matcher_id"`
+	AgentID     fileString `json:"agent_id"`
+}
+
+type fileTimeouts struct {
+	TaskTimeout fileDuration `json:"task_timeout"`
+	BidTimeout  fileDuration `json:"bid_timeout"`
+}
+
+// fileString also accepts bare numbers, so numeric IDs need not be quoted
+type fileString string
+
+func (s *fileString) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] != '"' && data[0] != 'n' {
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return err
+		}
+		*s = fileString(n)
+		return nil
+	}
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = fileString(v)
+	return nil
+}
+
+// fileDuration is a Go duration string such as "60s" or "1m30s"
+type fileDuration time.Duration
+
+func (d *fileDuration) UnmarshalJSON(data []byte) error {
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("duration must be a string such as \"60s\", got %s", data)
+	}
+	if v == "" {
+		*d = 0
+		return nil
+	}
+	parsed, err := time.ParseDuration(v)
+	if err != nil {
+		return err
+	}
+	*d = fileDuration(parsed)
+	return nil
+}
+
+func (fc *fileConfig) toConfig(baseDir string) (*Config, error) {
+	config := &Config{
+		AgentID:                     string(fc.AgentID),
+		PrivateKey:                  string(fc.PrivateKey),
+		ChainAddress:                string(fc.ChainAddress),
+		MatcherAddr:                 fc.MatcherAddr,
+		ValidatorAddr:               fc.ValidatorAddr,
+		ValidatorAddrs:              fc.ValidatorAddrs,
+		RegistryAddr:                fc.RegistryAddr,
+		AgentEndpoint:               fc.AgentEndpoint,
+		MaxConcurrentTasks:          fc.MaxConcurrentTasks,
+		MaxConcurrentTasksPerType:   fc.MaxConcurrentTasksPerType,
+		TaskQueueDepth:              fc.TaskQueueDepth,
+		TaskTimeout:                 time.Duration(fc.TaskTimeout),
+		BidTimeout:                  time.Duration(fc.BidTimeout),
+		BidResponseTimeout:          time.Duration(fc.BidResponseTimeout),
+		BidBatchSize:                fc.BidBatchSize,
+		BidBatchFlushInterval:       time.Duration(fc.BidBatchFlushInterval),
+		BidDryRun:                   fc.BidDryRun,
+		BidRatePerSecond:            fc.BidRatePerSecond,
+		BidRateBurst:                fc.BidRateBurst,
+		BiddingStrategy:             fc.BiddingStrategy,
+		MinBidPrice:                 fc.MinBidPrice,
+		MaxBidPrice:                 fc.MaxBidPrice,
+		Owner:                       string(fc.Owner),
+		StakeAmount:                 fc.StakeAmount,
+		UseTLS:                      fc.UseTLS,
+		SignAgentInfo:               fc.SignAgentInfo,
+		SignatureScheme:             fc.SignatureScheme,
+		EVMRecoveryID:               fc.EVMRecoveryID,
+		CertFile:                    fc.CertFile,
+		KeyFile:                     fc.KeyFile,
+		CAFile:                      fc.CAFile,
+		InsecureSkipVerify:          fc.InsecureSkipVerify,
+		LogLevel:                    fc.LogLevel,
+		DataDir:                     fc.DataDir,
+		HealthAddr:                  fc.HealthAddr,
+		RegistryToken:               fc.RegistryToken,
+		StrictRegistryAuth:          fc.StrictRegistryAuth,
+		RegistryOptional:            fc.RegistryOptional,
+		RegistryHeartbeatInterval:   time.Duration(fc.RegistryHeartbeatInterval),
+		HeartbeatFailureThreshold:   fc.HeartbeatFailureThreshold,
+		HeartbeatMetadata:           fc.HeartbeatMetadata,
+		HeartbeatReregisterStatuses: fc.HeartbeatReregisterStatuses,
+		OutgoingMetadata:            fc.OutgoingMetadata,
+		StreamReceiveTimeout:        time.Duration(fc.StreamReceiveTimeout),
+		TaskStreamBuffer:            fc.TaskStreamBuffer,
+		ReconnectInitialBackoff:     time.Duration(fc.ReconnectInitialBackoff),
+		ReconnectMaxBackoff:         time.Duration(fc.ReconnectMaxBackoff),
+		MatcherSubscriptionAttempts: fc.MatcherSubscriptionAttempts,
+		ShutdownTimeout:             time.Duration(fc.ShutdownTimeout),
+		HTTPTimeout:                 time.Duration(fc.HTTPTimeout),
+		ValidatorSetCacheTTL:        time.Duration(fc.ValidatorSetCacheTTL),
+		ValidatorCacheTTL:           time.Duration(fc.ValidatorCacheTTL),
+		ReportMaxPayloadSize:        fc.ReportMaxPayloadSize,
+		ReportPath:                  fc.ReportPath,
+		ReportScheme:                fc.ReportScheme,
+		ReportOverflowPolicy:        fc.ReportOverflowPolicy,
+		ReportMaxRetries:            fc.ReportMaxRetries,
+		ReportRetryBackoff:          time.Duration(fc.ReportRetryBackoff),
+		ReportRetryJitter:           fc.ReportRetryJitter,
+		ReportFinalizeThreshold:     fc.ReportFinalizeThreshold,
+		ReportBatchSize:             fc.ReportBatchSize,
+		ReportBatchFlushInterval:    time.Duration(fc.ReportBatchFlushInterval),
+		ReportMetadataAllowlist:     fc.ReportMetadataAllowlist,
+		ReportMetadataDenylist:      fc.ReportMetadataDenylist,
+		ValidatorWeights:            fc.ValidatorWeights,
+		TaskDedupWindow:             time.Duration(fc.TaskDedupWindow),
+		ResultHashAlgorithm:         fc.ResultHashAlgorithm,
+		ResultCompressionThreshold:  fc.ResultCompression,
+		PersistTaskResults:          fc.PersistTaskResults,
+		TaskResultRetention:         time.Duration(fc.TaskResultRetention),
+	}
+
+	for _, capability := range fc.Capabilities {
+		config.Capabilities = append(config.Capabilities, string(capability))
+	}
+	if fc.Identity != nil {
+		config.Identity = &IdentityConfig{
+			SubnetID:    string(fc.Identity.SubnetID),
+			ValidatorID: string(fc.Identity.ValidatorID),
+			MatcherID:   string(fc.Identity.MatcherID),
+			AgentID:     string(fc.Identity.AgentID),
+		}
+	}
+	if fc.Timeouts != nil {
+		config.Timeouts = &TimeoutConfig{
+			TaskTimeout: time.Duration(fc.Timeouts.TaskTimeout),
+			BidTimeout:  time.Duration(fc.Timeouts.BidTimeout),
+		}
+	}
+
+	if fc.PrivateKeyFile != "" {
+		if config.PrivateKey != "" {
+			return nil, errors.New("private_key and private_key_file are mutually exclusive")
+		}
+		keyPath := fc.PrivateKeyFile
+		if !filepath.IsAbs(keyPath) {
+			keyPath = filepath.Join(baseDir, keyPath)
+		}
+		key, err := os.ReadFile(keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("read private_key_file: %w", err)
+		}
+		config.PrivateKey = strings.TrimPrefix(strings.TrimSpace(string(key)), "0x")
+	}
+
+	return config, nil
+}
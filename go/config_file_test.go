@@ -0,0 +1,110 @@
+package agentsdk
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfigFromFileYAML(t *testing.T) {
+	path := writeConfigFile(t, "agent.yaml", `
+# Agent configuration
+identity:
+  subnet_id: "0x1"
+  agent_id: 42          # numeric IDs need not be quoted
+matcher_addr: localhost:8090
+private_key_file: agent.key
+capabilities: [compute, "ml:inference"]
+validator_addrs:
+  - validator-1:9090
+  - validator-2:9090
+timeouts:
+  task_timeout: 60s
+  bid_timeout: 2s
+max_concurrent_tasks_per_type:
+  ml: 2
+report_retry_jitter: 0.1
+use_tls: false
+`)
+	if err := os.WriteFile(filepath.Join(filepath.Dir(path), "agent.key"), []byte("0x"+testPrivateKey+"\n"), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+
+	cfg, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Identity.SubnetID != "0x1" || cfg.Identity.AgentID != "42" {
+		t.Fatalf("unexpected identity %+v", cfg.Identity)
+	}
+	if cfg.PrivateKey != testPrivateKey {
+		t.Fatalf("expected private key from file, got %q", cfg.PrivateKey)
+	}
+	if !reflect.DeepEqual(cfg.Capabilities, []string{"compute", "ml:inference"}) {
+		t.Fatalf("unexpected capabilities %v", cfg.Capabilities)
+	}
+	if len(cfg.ValidatorAddrs) != 2 || cfg.MaxConcurrentTasksPerType["ml"] != 2 || cfg.ReportRetryJitter != 0.1 {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if cfg.TaskTimeout != 60*time.Second || cfg.BidTimeout != 2*time.Second {
+		t.Fatalf("expected timeouts section to apply, got task=%v bid=%v", cfg.TaskTimeout, cfg.BidTimeout)
+	}
+	if cfg.MaxConcurrentTasks != 5 || cfg.ShutdownTimeout != 30*time.Second {
+		t.Fatalf("expected defaults to be applied, got %+v", cfg)
+	}
+}
+
+func TestLoadConfigFromFileJSON(t *testing.T) {
+	path := writeConfigFile(t, "agent.json", `{
+		"agent_id": "agent-1",
+		"matcher_addr": "localhost:8090",
+		"capabilities": ["compute"],
+		"task_timeout": "1m30s"
+	}`)
+
+	cfg, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.AgentID != "agent-1" || cfg.TaskTimeout != 90*time.Second {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+}
+
+func TestLoadConfigFromFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{"unknown key", "agent.yaml", "agent_id: a\nmatcher_adr: x\n", `unknown field "matcher_adr"`},
+		{"bad duration", "agent.yaml", "agent_id: a\ntask_timeout: 60\n", "duration must be a string"},
+		{"invalid config", "agent.json", `{"agent_id": "a", "matcher_addr": "m"}`, "at least one capability"},
+		{"unknown nested key", "agent.yml", "timeouts:\n  task_timout: 60s\n", `unknown field "task_timout"`},
+		{"bad indentation", "agent.yaml", "identity:\n  subnet_id: s\n    agent_id: a\n", "parse"},
+		{"malformed json", "agent.json", `{"agent_id": "a",`, "parse"},
+		{"unsupported extension", "agent.toml", "", "unsupported config file extension"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfigFromFile(writeConfigFile(t, tt.file, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package agentsdk
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// StreamStatus is the connection status of a matcher stream
+type StreamStatus string
+
+const (
+	StreamDisconnected StreamStatus = "disconnected"
+	StreamConnected    StreamStatus = "connected"
+	StreamReconnecting StreamStatus = "reconnecting" // waiting out the backoff before resubscribing
+)
+
+// StreamState describes one matcher stream
+type StreamState struct {
+	Status          StreamStatus `json:"status"`
+	LastConnectedAt time.Time    `json:"last_connected_at"` // zero until the stream first connects
+	// ReconnectAttempts counts reconnects since the stream last delivered a message or stayed
+	// up long enough to reset the backoff; a growing value points at a wedged stream
+	ReconnectAttempts int `json:"reconnect_attempts"`
+}
+
+// ConnectionState is a snapshot of the SDK's matcher stream connections
+type ConnectionState struct {
+	// MatcherStreamConnected is true while the task stream is connected, which /readyz requires
+	MatcherStreamConnected bool        `json:"matcher_stream_connected"`
+	Task                   StreamState `json:"task"`
+	Intent                 StreamState `json:"intent"` // stays disconnected unless a bidding strategy is registered
+}
+
+// streamState is the live state behind a StreamState, updated by the stream loops
+type streamState struct {
+	status            atomic.Value // StreamStatus
+	lastConnectedAt   atomic.Int64 // unix nanos
+	reconnectAttempts atomic.Int64
+}
+
+func (s *streamState) connected() {
+	s.status.Store(StreamConnected)
+	s.lastConnectedAt.Store(time.Now().UnixNano())
+}
+
+func (s *streamState) reconnecting(connectedFor time.Duration) {
+	s.status.Store(StreamReconnecting)
+	// Match the backoff: a stream that stayed up long enough starts counting again
+	if connectedFor >= reconnectHealthyResetAfter {
+		s.reconnectAttempts.Store(1)
+		return
+	}
+	s.reconnectAttempts.Add(1)
+}
+
+// delivered records that the stream is healthy enough to deliver messages
+func (s *streamState) delivered() {
+	s.reconnectAttempts.Store(0)
+}
+
+func (s *streamState) disconnected() {
+	s.status.Store(StreamDisconnected)
+}
+
+func (s *streamState) isConnected() bool {
+	return s.snapshot().Status == StreamConnected
+}
+
+func (s *streamState) snapshot() StreamState {
+	state := StreamState{Status: StreamDisconnected, ReconnectAttempts: int(s.reconnectAttempts.Load())}
+	if status, ok := s.status.Load().(StreamStatus); ok {
+		state.Status = status
+	}
+	if last := s.lastConnectedAt.Load(); last != 0 {
+		state.LastConnectedAt = time.Unix(0, last)
+	}
+	return state
+}
+
+// streamStateFor returns the state tracked for the named matcher stream
+func (sdk *SDK) streamStateFor(stream string) *streamState {
+	if stream == "intent" {
+		return &sdk.intentStream
+	}
+	return &sdk.taskStream
+}
+
+// ConnectionState reports whether the matcher streams are connected or reconnecting
+func (sdk *SDK) ConnectionState() ConnectionState {
+	task := sdk.taskStream.snapshot()
+	return ConnectionState{
+		MatcherStreamConnected: task.Status == StreamConnected,
+		Task:                   task,
+		Intent:                 sdk.intentStream.snapshot(),
+	}
+}
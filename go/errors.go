@@ -0,0 +1,15 @@
+package agentsdk
+
+import "errors"
+
+// Sentinel errors returned (possibly wrapped) by the SDK; test for them with errors.Is
+var (
+	// ErrNotRunning is returned by operations that need a started SDK
+	ErrNotRunning = errors.New("SDK not running")
+	// ErrNoHandler is returned when a task is executed before a handler is registered
+	ErrNoHandler = errors.New("no handler registered")
+	// ErrNoPrivateKey is returned when signing is requested without a configured private key
+	ErrNoPrivateKey = errors.New("no private key configured")
+	// ErrNoValidators is returned when no validator is configured or discovered to receive a report
+	ErrNoValidators = errors.New("no validator endpoints available")
+)
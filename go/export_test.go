@@ -0,0 +1,78 @@
+package agentsdk
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	pb "subnet/proto/subnet"
+
+	"google.golang.org/grpc"
+)
+
+// benchMatcherService acknowledges task responses without recording them
+type benchMatcherService struct {
+	pb.MatcherServiceClient
+}
+
+func (benchMatcherService) RespondToTask(ctx context.Context, in *pb.RespondToTaskRequest, opts ...grpc.CallOption) (*pb.RespondToTaskResponse, error) {
+	return &pb.RespondToTaskResponse{}, nil
+}
+
+// benchValidatorService accepts every execution report
+type benchValidatorService struct {
+	pb.ValidatorServiceClient
+}
+
+func (benchValidatorService) SubmitExecutionReport(ctx context.Context, in *pb.ExecutionReport, opts ...grpc.CallOption) (*pb.Receipt, error) {
+	return &pb.Receipt{ReportId: in.ReportId, Status: "accepted"}, nil
+}
+
+// NewPipelineForTest returns a running SDK whose matcher and validator are in-memory fakes,
+// so external test packages can drive the streamed task and report pipeline.
+func NewPipelineForTest(tb testing.TB, cfg *Config, handler Handler) *SDK {
+	tb.Helper()
+	if cfg.AgentID == "" {
+		cfg.AgentID = "agent-1"
+	}
+	if cfg.MatcherAddr == "" {
+		cfg.MatcherAddr = "matcher:8090"
+	}
+	if len(cfg.Capabilities) == 0 {
+		cfg.Capabilities = []string{"compute"}
+	}
+	if cfg.LogLevel == "" {
+		cfg.LogLevel = LogLevelError
+	}
+
+	sdk, err := New(cfg)
+	if err != nil {
+		tb.Fatalf("unexpected error creating sdk: %v", err)
+	}
+	sdk.RegisterHandler(handler)
+	sdk.matcherClient = &MatcherClient{client: benchMatcherService{}, logger: sdk.logger}
+	sdk.validatorClient = &ValidatorClient{client: benchValidatorService{}}
+	sdk.validators = []validatorTarget{{addr: "validator:9090", client: sdk.validatorClient}}
+	sdk.running.Store(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sdk.taskCancel = cancel
+	sdk.startReportWorkers(ctx)
+	tb.Cleanup(func() { sdk.drainTasks(sdk.config.ShutdownTimeout) })
+	return sdk
+}
+
+// DispatchTestTask feeds a task into the pipeline as if it arrived on the matcher stream
+func (sdk *SDK) DispatchTestTask(n int, taskType string) {
+	sdk.reserveStreamSlot(context.Background())
+	sdk.dispatchTask(context.Background(), &pb.ExecutionTask{
+		TaskId:     fmt.Sprintf("task-%d", n),
+		IntentId:   fmt.Sprintf("intent-%d", n),
+		IntentType: taskType,
+	})
+}
+
+// WaitForTestTasks blocks until dispatched tasks have executed and their reports are submitted
+func (sdk *SDK) WaitForTestTasks() {
+	sdk.taskWG.Wait()
+}
@@ -8,6 +8,7 @@ require (
 	github.com/ethereum/go-ethereum v1.16.4
 	google.golang.org/grpc v1.75.0
 	google.golang.org/protobuf v1.36.8
+	gopkg.in/yaml.v3 v3.0.1
 	subnet v0.0.0-00010101000000-000000000000
 )
 
@@ -7,13 +7,17 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
@@ -25,6 +29,11 @@ const (
 	TimestampKey = "x-timestamp"
 	NonceKey     = "x-nonce"
 	ChainIDKey   = "x-chain-id"
+
+	// Sent only when the matching SigningConfig field is set, so verifiers know which optional
+	// fields the canonical payload includes
+	CapabilitiesKey = "x-capabilities"
+	SDKVersionKey   = "x-sdk-version"
 )
 
 // SigningConfig holds configuration for metadata signing
@@ -32,6 +41,28 @@ type SigningConfig struct {
 	PrivateKey *ecdsa.PrivateKey
 	Address    string
 	ChainID    string
+	// Rand is the entropy source for request nonces; defaults to crypto/rand.Reader
+	Rand io.Reader
+	// Capabilities, when non-empty, are bound into every request signature as "capabilities"
+	Capabilities []string
+	// SDKVersion, when non-empty, is bound into every request signature as "sdk_version"
+	SDKVersion string
+	// Scheme selects how the canonical payload is hashed and signed; defaults to SignatureSchemeRawKeccak
+	Scheme string
+	// EVMRecoveryID makes signatures end in a recovery id of 27/28 instead of 0/1
+	EVMRecoveryID bool
+}
+
+// signedFields returns the optional top-level fields added to the canonical signing payload
+func (c *SigningConfig) signedFields() map[string]interface{} {
+	fields := make(map[string]interface{}, 2)
+	if len(c.Capabilities) > 0 {
+		fields["capabilities"] = c.Capabilities
+	}
+	if c.SDKVersion != "" {
+		fields["sdk_version"] = c.SDKVersion
+	}
+	return fields
 }
 
 // SigningInterceptor implements gRPC client interceptor for signing requests
@@ -68,45 +99,162 @@ func (si *SigningInterceptor) StreamInterceptor() grpc.StreamClientInterceptor {
 
 // addMetadata adds signing metadata to context
 func (si *SigningInterceptor) addMetadata(ctx context.Context, method string, req interface{}) (context.Context, error) {
+	signed, err := si.config.signedMetadata(method, req)
+	if err != nil {
+		return ctx, err
+	}
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	for key, values := range signed {
+		md[key] = values
+	}
+
+	return metadata.NewOutgoingContext(ctx, md), nil
+}
+
+// signedMetadata signs a call to method with req, returning the signature metadata. It is shared
+// by the interceptor and SDK.SignCanonical so both produce the same signatures.
+func (c *SigningConfig) signedMetadata(method string, req interface{}) (metadata.MD, error) {
 	timestamp := time.Now().Unix()
-	nonce := generateNonce()
+	nonce := generateNonce(c.Rand)
 
-	canonical, err := canonicalJSON(si.config.ChainID, method, timestamp, nonce, req)
+	canonical, err := canonicalJSON(c.ChainID, method, timestamp, nonce, req, c.signedFields())
 	if err != nil {
-		return ctx, fmt.Errorf("failed to create canonical JSON: %w", err)
+		return nil, &CanonicalEncodingError{Method: method, Err: err}
 	}
 
-	signature, err := signMessage(si.config.PrivateKey, canonical)
+	signature, err := signMessage(c.PrivateKey, canonical, c.Scheme, c.EVMRecoveryID)
 	if err != nil {
-		return ctx, fmt.Errorf("failed to sign message: %w", err)
+		return nil, fmt.Errorf("failed to sign message: %w", err)
 	}
 
-	md := metadata.Pairs(
-		SignatureKey, hex.EncodeToString(signature),
-		SignerIDKey, si.config.Address,
-		TimestampKey, fmt.Sprintf("%d", timestamp),
-		NonceKey, nonce,
-		ChainIDKey, si.config.ChainID,
-	)
+	md := metadata.MD{}
+	md.Set(SignatureKey, hex.EncodeToString(signature))
+	md.Set(SignerIDKey, c.Address)
+	md.Set(TimestampKey, fmt.Sprintf("%d", timestamp))
+	md.Set(NonceKey, nonce)
+	md.Set(ChainIDKey, c.ChainID)
+	if len(c.Capabilities) > 0 {
+		md.Set(CapabilitiesKey, c.Capabilities...)
+	}
+	if c.SDKVersion != "" {
+		md.Set(SDKVersionKey, c.SDKVersion)
+	}
+	return md, nil
+}
 
-	return metadata.NewOutgoingContext(ctx, md), nil
+// CanonicalEncodingError reports that a request could not be encoded into the canonical signing payload.
+// The RPC is not sent; the error carries an InvalidArgument gRPC status so callers can tell it apart
+// from transport failures and decide whether to fix or drop the offending request.
+type CanonicalEncodingError struct {
+	Method string
+	Err    error
+}
+
+func (e *CanonicalEncodingError) Error() string {
+	return fmt.Sprintf("cannot sign %s: request is not canonically encodable: %v", e.Method, e.Err)
+}
+
+func (e *CanonicalEncodingError) Unwrap() error { return e.Err }
+
+// GRPCStatus lets status.FromError and status.Code classify the failure
+func (e *CanonicalEncodingError) GRPCStatus() *status.Status {
+	return status.New(codes.InvalidArgument, e.Error())
+}
+
+// MetadataInterceptor merges custom metadata (e.g. tenant or region routing headers) into outgoing requests
+type MetadataInterceptor struct {
+	static   metadata.MD
+	provider func() metadata.MD
+}
+
+// NewMetadataInterceptor creates an interceptor sending static metadata plus the provider's values on every RPC
+func NewMetadataInterceptor(static map[string]string, provider func() metadata.MD) *MetadataInterceptor {
+	return &MetadataInterceptor{
+		static:   metadata.New(static),
+		provider: provider,
+	}
+}
+
+// UnaryInterceptor returns a grpc.UnaryClientInterceptor
+func (mi *MetadataInterceptor) UnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(mi.addMetadata(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamInterceptor returns a grpc.StreamClientInterceptor
+func (mi *MetadataInterceptor) StreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(mi.addMetadata(ctx), desc, cc, method, opts...)
+	}
+}
+
+// addMetadata merges custom metadata into the outgoing context, never overriding signing keys
+func (mi *MetadataInterceptor) addMetadata(ctx context.Context) context.Context {
+	custom := mi.static
+	if mi.provider != nil {
+		if dynamic := mi.provider(); len(dynamic) > 0 {
+			custom = metadata.Join(custom, dynamic)
+		}
+	}
+	if len(custom) == 0 {
+		return ctx
+	}
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	for key, values := range custom {
+		if isSigningMetadataKey(key) {
+			continue
+		}
+		md.Set(key, values...)
+	}
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// isSigningMetadataKey reports whether key is reserved for request signing, including the optional
+// agent info keys that tell verifiers which fields the signature covers
+func isSigningMetadataKey(key string) bool {
+	switch strings.ToLower(key) {
+	case SignatureKey, SignerIDKey, TimestampKey, NonceKey, ChainIDKey, CapabilitiesKey, SDKVersionKey:
+		return true
+	default:
+		return false
+	}
 }
 
 // generateNonce creates a random nonce
-func generateNonce() string {
-	b := make([]byte, 16)
-	rand.Read(b)
-	return hex.EncodeToString(b)
+func generateNonce(source io.Reader) string {
+	return hex.EncodeToString(randomBytes(source, 16))
 }
 
-// canonicalJSON creates deterministic JSON for signing
-func canonicalJSON(chainID, method string, timestamp int64, nonce string, req interface{}) ([]byte, error) {
+// randomBytes reads n bytes from source, falling back to crypto/rand when source is nil
+func randomBytes(source io.Reader, n int) []byte {
+	if source == nil {
+		source = rand.Reader
+	}
+	b := make([]byte, n)
+	io.ReadFull(source, b)
+	return b
+}
+
+// canonicalJSON creates deterministic JSON for signing. The payload is one object with keys in
+// byte order and no whitespace: "capabilities" (optional, JSON array in configured order),
+// "chain_id", "method", "nonce", "request" (omitted for streams), "sdk_version" (optional) and
+// "timestamp" (integer seconds). The optional keys come from fields and are absent when unset.
+func canonicalJSON(chainID, method string, timestamp int64, nonce string, req interface{}, fields map[string]interface{}) ([]byte, error) {
 	payload := map[string]interface{}{
 		"chain_id":  chainID,
 		"method":    method,
 		"timestamp": timestamp,
 		"nonce":     nonce,
 	}
+	for key, value := range fields {
+		payload[key] = value
+	}
 
 	// Convert request to JSON
 	var requestBody interface{}
@@ -135,23 +283,79 @@ func canonicalJSON(chainID, method string, timestamp int64, nonce string, req in
 	return json.Marshal(payload)
 }
 
-// signMessage signs data using Keccak256
-func signMessage(privateKey *ecdsa.PrivateKey, data []byte) ([]byte, error) {
+// Supported signature schemes. Both produce 65-byte secp256k1 signatures (r, s, v).
+const (
+	// SignatureSchemeRawKeccak signs keccak256(data) with a recovery id v of 0 or 1
+	SignatureSchemeRawKeccak = "raw_keccak"
+	// SignatureSchemeEIP191 signs the personal_sign hash
+	// keccak256("\x19Ethereum Signed Message:\n" + len(data) + data) with v of 27 or 28
+	SignatureSchemeEIP191 = "eip191"
+)
+
+// evmRecoveryIDOffset is added to the 0/1 recovery id for EVM-style ecrecover consumers
+const evmRecoveryIDOffset = 27
+
+func validateSignatureScheme(scheme string) error {
+	switch scheme {
+	case "", SignatureSchemeRawKeccak, SignatureSchemeEIP191:
+		return nil
+	default:
+		return fmt.Errorf("signature_scheme must be %q or %q", SignatureSchemeRawKeccak, SignatureSchemeEIP191)
+	}
+}
+
+// signatureHash returns the digest signed for data under scheme
+func signatureHash(scheme string, data []byte) []byte {
+	if scheme == SignatureSchemeEIP191 {
+		return crypto.Keccak256([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), data)))
+	}
+	return crypto.Keccak256(data)
+}
+
+// signMessage signs data under scheme. The recovery id is 27/28 when evmRecoveryID is set or the
+// scheme is EIP-191, and 0/1 otherwise.
+func signMessage(privateKey *ecdsa.PrivateKey, data []byte, scheme string, evmRecoveryID bool) ([]byte, error) {
 	if privateKey == nil {
-		return nil, fmt.Errorf("no private key configured")
+		return nil, ErrNoPrivateKey
 	}
 
-	hash := crypto.Keccak256Hash(data)
-	signature, err := crypto.Sign(hash.Bytes(), privateKey)
+	signature, err := crypto.Sign(signatureHash(scheme, data), privateKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign: %w", err)
 	}
+	if evmRecoveryID || scheme == SignatureSchemeEIP191 {
+		signature[crypto.RecoveryIDOffset] += evmRecoveryIDOffset
+	}
 
 	return signature, nil
 }
 
-// DialOption creates gRPC dial options with optional signing
-func DialOption(target string, signingConfig *SigningConfig, secure bool) (*grpc.ClientConn, error) {
+// NormalizeRecoveryID returns a copy of a 65-byte signature whose recovery id (the last byte) is
+// 27/28 when evm is set and 0/1 otherwise. Either form is accepted as input.
+func NormalizeRecoveryID(signature []byte, evm bool) ([]byte, error) {
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("signature must be %d bytes, got %d", crypto.SignatureLength, len(signature))
+	}
+	v := signature[crypto.RecoveryIDOffset]
+	switch v {
+	case 0, 1:
+	case evmRecoveryIDOffset, evmRecoveryIDOffset + 1:
+		v -= evmRecoveryIDOffset
+	default:
+		return nil, fmt.Errorf("invalid signature recovery id %d", v)
+	}
+	if evm {
+		v += evmRecoveryIDOffset
+	}
+
+	normalized := append([]byte{}, signature...)
+	normalized[crypto.RecoveryIDOffset] = v
+	return normalized, nil
+}
+
+// DialOption creates gRPC dial options with optional signing.
+// Additional dial options (e.g. extra interceptors) are appended after the built-in ones.
+func DialOption(target string, signingConfig *SigningConfig, secure bool, extra ...grpc.DialOption) (*grpc.ClientConn, error) {
 	opts := []grpc.DialOption{}
 
 	if signingConfig != nil {
@@ -169,7 +373,9 @@ func DialOption(target string, signingConfig *SigningConfig, secure bool) (*grpc
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
 
+	opts = append(opts, extra...)
+
 	// Use non-blocking dial to avoid hanging on connection
 	// Connection will be established in background
 	return grpc.Dial(target, opts...)
-}
\ No newline at end of file
+}
@@ -0,0 +1,179 @@
+package agentsdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	pb "subnet/proto/subnet"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMetadataInterceptorPreservesSigningKeys(t *testing.T) {
+	interceptor := NewMetadataInterceptor(
+		map[string]string{"x-tenant": "acme", SignatureKey: "forged"},
+		func() metadata.MD {
+			return metadata.Pairs("x-region", "eu-west", CapabilitiesKey, "forged", SDKVersionKey, "forged")
+		},
+	)
+
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs(SignatureKey, "real", CapabilitiesKey, "compute"))
+	md, _ := metadata.FromOutgoingContext(interceptor.addMetadata(ctx))
+
+	if got := md.Get(SignatureKey); len(got) != 1 || got[0] != "real" {
+		t.Fatalf("expected signature to be preserved, got %v", got)
+	}
+	if got := md.Get(CapabilitiesKey); len(got) != 1 || got[0] != "compute" {
+		t.Fatalf("expected signed capabilities to be preserved, got %v", got)
+	}
+	if got := md.Get(SDKVersionKey); len(got) != 0 {
+		t.Fatalf("custom metadata must not claim an unsigned SDK version, got %v", got)
+	}
+	if got := md.Get("x-tenant"); len(got) != 1 || got[0] != "acme" {
+		t.Fatalf("expected static metadata, got %v", got)
+	}
+	if got := md.Get("x-region"); len(got) != 1 || got[0] != "eu-west" {
+		t.Fatalf("expected provider metadata, got %v", got)
+	}
+}
+
+func TestGeneratorsUseInjectedRandSource(t *testing.T) {
+	source := bytes.NewReader(bytes.Repeat([]byte{0xab}, 64))
+
+	if nonce := generateNonce(source); nonce != strings.Repeat("ab", 16) {
+		t.Fatalf("unexpected nonce %s", nonce)
+	}
+	if reportID := generateReportID(source); reportID != "report-"+strings.Repeat("ab", 16) {
+		t.Fatalf("unexpected report id %s", reportID)
+	}
+	if bidID := generateBidID(source); bidID != "0x"+strings.Repeat("ab", 32) {
+		t.Fatalf("unexpected bid id %s", bidID)
+	}
+}
+
+func TestSigningInterceptorClassifiesUnencodableRequests(t *testing.T) {
+	key, err := crypto.HexToECDSA(testPrivateKey)
+	if err != nil {
+		t.Fatalf("load key: %v", err)
+	}
+	interceptor := NewSigningInterceptor(&SigningConfig{PrivateKey: key, Address: "0xabc", ChainID: "subnet-1"})
+
+	invoked := false
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		invoked = true
+		return nil
+	}
+
+	// protojson rejects invalid UTF-8 in string fields
+	req := &pb.StreamTasksRequest{AgentId: "agent-\xff"}
+	err = interceptor.UnaryInterceptor()(context.Background(), "/subnet.v1.MatcherService/StreamTasks", req, nil, nil, invoker)
+
+	var encodingErr *CanonicalEncodingError
+	if !errors.As(err, &encodingErr) || encodingErr.Method != "/subnet.v1.MatcherService/StreamTasks" {
+		t.Fatalf("expected CanonicalEncodingError, got %v", err)
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument status, got %v", status.Code(err))
+	}
+	if invoked {
+		t.Fatal("unencodable request must not be sent unsigned")
+	}
+
+	if err := interceptor.UnaryInterceptor()(context.Background(), "/subnet.v1.MatcherService/StreamTasks", &pb.StreamTasksRequest{AgentId: "agent-1"}, nil, nil, invoker); err != nil || !invoked {
+		t.Fatalf("expected valid request to be signed and sent, err=%v invoked=%v", err, invoked)
+	}
+}
+
+func TestCanonicalJSONBindsOptionalAgentInfo(t *testing.T) {
+	req := &pb.StreamTasksRequest{AgentId: "agent-1"}
+
+	plain, err := canonicalJSON("subnet-1", "/m", 1700000000, "n1", req, (&SigningConfig{}).signedFields())
+	if err != nil {
+		t.Fatalf("canonicalJSON: %v", err)
+	}
+	if want := `{"chain_id":"subnet-1","method":"/m","nonce":"n1","request":{"agentId":"agent-1"},"timestamp":1700000000}`; string(plain) != want {
+		t.Fatalf("canonical payload changed without optional fields:\n got %s\nwant %s", plain, want)
+	}
+
+	config := &SigningConfig{Capabilities: []string{"compute", "ml"}, SDKVersion: "0.1.0"}
+	withInfo, err := canonicalJSON("subnet-1", "/m", 1700000000, "n1", nil, config.signedFields())
+	if err != nil {
+		t.Fatalf("canonicalJSON: %v", err)
+	}
+	if want := `{"capabilities":["compute","ml"],"chain_id":"subnet-1","method":"/m","nonce":"n1","sdk_version":"0.1.0","timestamp":1700000000}`; string(withInfo) != want {
+		t.Fatalf("unexpected canonical payload:\n got %s\nwant %s", withInfo, want)
+	}
+
+	key, err := crypto.HexToECDSA(testPrivateKey)
+	if err != nil {
+		t.Fatalf("load key: %v", err)
+	}
+	config.PrivateKey = key
+	ctx, err := NewSigningInterceptor(config).addMetadata(context.Background(), "/m", req)
+	if err != nil {
+		t.Fatalf("addMetadata: %v", err)
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if caps := md.Get(CapabilitiesKey); !reflect.DeepEqual(caps, config.Capabilities) || !reflect.DeepEqual(md.Get(SDKVersionKey), []string{"0.1.0"}) {
+		t.Fatalf("expected the signed agent info in metadata, got %v", md)
+	}
+}
+
+func TestSignCanonicalMatchesInterceptorSignatures(t *testing.T) {
+	if _, err := newTestSDK(t, nil).SignCanonical("/m", nil); !errors.Is(err, ErrNoPrivateKey) {
+		t.Fatalf("expected ErrNoPrivateKey without a key, got %v", err)
+	}
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.Identity = &IdentityConfig{SubnetID: "subnet-1", AgentID: "agent-1"}
+		cfg.PrivateKey = testPrivateKey
+	})
+	sdk.RegisterHandler(namedHandler("handler"))
+	// Start builds the gRPC clients' signing config under the SDK lock
+	if err := sdk.Start(); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	defer sdk.Stop()
+
+	req := &pb.StreamTasksRequest{AgentId: "agent-1"}
+	headers, err := sdk.SignCanonical("/subnet.v1.MatcherService/StreamTasks", req)
+	if err != nil {
+		t.Fatalf("SignCanonical: %v", err)
+	}
+	if headers[SignerIDKey] != sdk.GetAddress() || headers[ChainIDKey] != "subnet-1" || headers[NonceKey] == "" {
+		t.Fatalf("unexpected signature headers %v", headers)
+	}
+	if _, ok := headers[CapabilitiesKey]; ok {
+		t.Fatalf("expected no agent info headers by default, got %v", headers)
+	}
+
+	timestamp, err := strconv.ParseInt(headers[TimestampKey], 10, 64)
+	if err != nil {
+		t.Fatalf("parse timestamp: %v", err)
+	}
+	canonical, err := canonicalJSON("subnet-1", "/subnet.v1.MatcherService/StreamTasks", timestamp, headers[NonceKey], req, nil)
+	if err != nil {
+		t.Fatalf("canonicalJSON: %v", err)
+	}
+	signature, err := hex.DecodeString(headers[SignatureKey])
+	if err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	pub, err := crypto.SigToPub(crypto.Keccak256(canonical), signature)
+	if err != nil {
+		t.Fatalf("unexpected recovery error: %v", err)
+	}
+	if got := crypto.PubkeyToAddress(*pub).Hex(); got != sdk.GetAddress() {
+		t.Fatalf("recovered %s, expected %s", got, sdk.GetAddress())
+	}
+}
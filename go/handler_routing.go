@@ -0,0 +1,45 @@
+package agentsdk
+
+import "strings"
+
+// RegisterHandlerForType sets the handler for tasks of taskType. It also serves dotted subtypes, so
+// a handler for "ml" receives "ml.inference" tasks unless a more specific type is registered.
+// Tasks matching no registered type go to the handler set by RegisterHandler.
+func (sdk *SDK) RegisterHandlerForType(taskType string, handler Handler) {
+	sdk.handlersMu.Lock()
+	defer sdk.handlersMu.Unlock()
+	if sdk.typeHandlers == nil {
+		sdk.typeHandlers = make(map[string]Handler)
+	}
+	sdk.typeHandlers[taskType] = handler
+}
+
+// handlerFor returns the handler for taskType, or nil when none applies
+func (sdk *SDK) handlerFor(taskType string) Handler {
+	sdk.handlersMu.RLock()
+	defer sdk.handlersMu.RUnlock()
+	return sdk.routeHandler(taskType)
+}
+
+// routeHandler resolves taskType to the most specific registered handler, falling back to the
+// default handler. The caller must hold sdk.handlersMu.
+func (sdk *SDK) routeHandler(taskType string) Handler {
+	for prefix := taskType; prefix != ""; {
+		if handler, ok := sdk.typeHandlers[prefix]; ok {
+			return handler
+		}
+		i := strings.LastIndexByte(prefix, '.')
+		if i < 0 {
+			break
+		}
+		prefix = prefix[:i]
+	}
+	return sdk.handler
+}
+
+// hasHandler reports whether any handler is registered
+func (sdk *SDK) hasHandler() bool {
+	sdk.handlersMu.RLock()
+	defer sdk.handlersMu.RUnlock()
+	return sdk.handler != nil || len(sdk.typeHandlers) > 0
+}
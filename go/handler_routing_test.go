@@ -0,0 +1,72 @@
+package agentsdk
+
+import (
+	"context"
+	"testing"
+
+	pb "subnet/proto/subnet"
+)
+
+// namedHandler returns its name as the result data
+func namedHandler(name string) Handler {
+	return HandlerFunc(func(ctx context.Context, task *Task) (*Result, error) {
+		return &Result{Data: []byte(name), Success: true}, nil
+	})
+}
+
+func TestRegisterHandlerForTypeRoutesByType(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	sdk.RegisterHandler(namedHandler("default"))
+	sdk.RegisterHandlerForType("ml", namedHandler("ml"))
+	sdk.RegisterHandlerForType("ml.training", namedHandler("training"))
+	sdk.running.Store(true)
+
+	cases := map[string]string{
+		"ml":                "ml",
+		"ml.inference":      "ml",
+		"ml.training":       "training",
+		"ml.training.large": "training",
+		"mlx":               "default",
+		"compute":           "default",
+		"":                  "default",
+	}
+	for taskType, want := range cases {
+		result, err := sdk.ExecuteTask(context.Background(), &Task{ID: "task-1", Type: taskType})
+		if err != nil {
+			t.Fatalf("type %q: unexpected error: %v", taskType, err)
+		}
+		if got := string(result.Data); got != want {
+			t.Fatalf("type %q: expected the %s handler, got %s", taskType, want, got)
+		}
+	}
+}
+
+func TestHandleExecutionTaskRejectsUnroutedType(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	matcher := &fakeMatcherService{}
+	sdk.matcherClient = &MatcherClient{client: matcher, logger: sdk.logger}
+	callbacks := &recordingCallbacks{}
+	sdk.RegisterHandlerForType("ml", namedHandler("ml"))
+	sdk.RegisterCallbacks(callbacks)
+	sdk.running.Store(true)
+
+	sdk.handleExecutionTask(context.Background(), &pb.ExecutionTask{TaskId: "task-compute", IntentType: "compute"})
+
+	if len(callbacks.rejected) != 1 || callbacks.rejected[0] != "no handler for type" {
+		t.Fatalf("expected the task to be rejected, got %v", callbacks.rejected)
+	}
+	if r := matcher.responses[0]; r.Accepted || r.Reason != "no handler for type" {
+		t.Fatalf("unexpected task response %+v", r)
+	}
+}
+
+func TestStartAcceptsTypedHandlerWithoutDefault(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	sdk.RegisterHandlerForType("ml", namedHandler("ml"))
+	if err := sdk.Start(); err != nil {
+		t.Fatalf("expected a typed handler alone to be enough to start, got %v", err)
+	}
+	if err := sdk.Stop(); err != nil {
+		t.Fatalf("unexpected stop error: %v", err)
+	}
+}
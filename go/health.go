@@ -0,0 +1,155 @@
+package agentsdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// healthShutdownTimeout bounds how long Stop waits for in-flight probe requests
+const healthShutdownTimeout = 5 * time.Second
+
+// healthStatus is the JSON body served by /healthz and /readyz
+type healthStatus struct {
+	Status        string            `json:"status"`
+	UptimeSeconds float64           `json:"uptime_seconds"`
+	Checks        map[string]string `json:"checks,omitempty"`
+}
+
+// metricsReport is the JSON body served by /metrics.json
+type metricsReport struct {
+	AgentID        string                     `json:"agent_id"`
+	SubnetID       string                     `json:"subnet_id"`
+	Running        bool                       `json:"running"`
+	UptimeSeconds  float64                    `json:"uptime_seconds"`
+	Connection     ConnectionState            `json:"connection"`
+	Metrics        MetricsSnapshot            `json:"metrics"`
+	MetricsByType  map[string]TaskTypeMetrics `json:"metrics_by_type"`
+	ReportAttempts map[string]map[int]int64   `json:"report_attempts"`
+	PendingReports []PendingReport            `json:"pending_reports"`
+}
+
+// startHealthServer serves /healthz, /readyz and /metrics.json on HealthAddr; it is a no-op when no address is configured
+func (sdk *SDK) startHealthServer() error {
+	if sdk.config.HealthAddr == "" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", sdk.config.HealthAddr)
+	if err != nil {
+		return fmt.Errorf("listen on health address %s: %w", sdk.config.HealthAddr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealthStatus(w, http.StatusOK, healthStatus{Status: "ok", UptimeSeconds: sdk.Uptime().Seconds()})
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		checks, ready := sdk.readiness()
+		if !ready {
+			writeHealthStatus(w, http.StatusServiceUnavailable, healthStatus{Status: "unavailable", UptimeSeconds: sdk.Uptime().Seconds(), Checks: checks})
+			return
+		}
+		writeHealthStatus(w, http.StatusOK, healthStatus{Status: "ready", UptimeSeconds: sdk.Uptime().Seconds(), Checks: checks})
+	})
+	mux.HandleFunc("/metrics.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sdk.metricsReport())
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	sdk.healthServer = server
+	sdk.healthAddr = listener.Addr().String()
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			sdk.logger.Error("Health server stopped", "error", err)
+		}
+	}()
+	sdk.logger.Info("Health server listening", "addr", sdk.healthAddr)
+	return nil
+}
+
+// stopHealthServer shuts the health server down, waiting briefly for in-flight probes
+func (sdk *SDK) stopHealthServer() {
+	if sdk.healthServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), healthShutdownTimeout)
+	defer cancel()
+	if err := sdk.healthServer.Shutdown(ctx); err != nil {
+		sdk.logger.Warn("Health server shutdown failed", "error", err)
+	}
+	sdk.healthServer = nil
+}
+
+// readiness checks each subsystem an agent needs to take tasks. The registry heartbeat counts as
+// recent when it succeeded within three heartbeat intervals; an optional registry is reported but
+// does not affect readiness.
+func (sdk *SDK) readiness() (map[string]string, bool) {
+	checks := make(map[string]string, 3)
+	ready := true
+	fail := func(name, reason string) {
+		checks[name] = reason
+		ready = false
+	}
+
+	if sdk.running.Load() {
+		checks["sdk"] = "ok"
+	} else {
+		fail("sdk", "not running")
+	}
+
+	switch task := sdk.taskStream.snapshot(); task.Status {
+	case StreamConnected:
+		checks["matcher_stream"] = "ok"
+	case StreamReconnecting:
+		fail("matcher_stream", fmt.Sprintf("reconnecting (attempt %d)", task.ReconnectAttempts))
+	default:
+		fail("matcher_stream", "not connected")
+	}
+
+	if sdk.config.RegistryAddr != "" {
+		failRegistry := fail
+		if sdk.config.RegistryOptional {
+			failRegistry = func(name, reason string) { checks[name] = reason + " (optional)" }
+		}
+		maxAge := 3 * sdk.config.RegistryHeartbeatInterval
+		last := sdk.lastHeartbeat.Load()
+		switch {
+		case last == 0:
+			failRegistry("registry_heartbeat", "no successful heartbeat")
+		case time.Since(time.Unix(0, last)) > maxAge:
+			failRegistry("registry_heartbeat", fmt.Sprintf("last successful heartbeat %s ago", time.Since(time.Unix(0, last)).Round(time.Second)))
+		default:
+			checks["registry_heartbeat"] = "ok"
+		}
+	}
+
+	return checks, ready
+}
+
+// metricsReport snapshots the agent's metrics and status. It does not take sdk.mu, which Start holds
+// while the health server is already serving; the identity it reads is fixed at New.
+func (sdk *SDK) metricsReport() metricsReport {
+	return metricsReport{
+		AgentID:        sdk.registryAgentID(),
+		SubnetID:       sdk.subnetID(),
+		Running:        sdk.running.Load(),
+		Connection:     sdk.ConnectionState(),
+		Metrics:        sdk.metrics.Snapshot(),
+		MetricsByType:  sdk.metrics.MetricsByType(),
+		ReportAttempts: sdk.metrics.ReportAttemptHistogram(),
+		UptimeSeconds:  sdk.Uptime().Seconds(),
+		PendingReports: sdk.PendingReports(),
+	}
+}
+
+func writeHealthStatus(w http.ResponseWriter, code int, status healthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,110 @@
+package agentsdk
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func getHealth(t *testing.T, url string) (int, healthStatus) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("probe %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	var status healthStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatalf("decode %s: %v", url, err)
+	}
+	return resp.StatusCode, status
+}
+
+func TestHealthServerReportsUnhealthySubsystems(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.HealthAddr = "127.0.0.1:0"
+		cfg.RegistryAddr = "http://registry:8080"
+		cfg.AgentEndpoint = "http://agent:9000"
+		cfg.RegistryHeartbeatInterval = time.Minute
+	})
+	if err := sdk.startHealthServer(); err != nil {
+		t.Fatalf("start health server: %v", err)
+	}
+	defer sdk.stopHealthServer()
+	base := "http://" + sdk.healthAddr
+
+	if code, _ := getHealth(t, base+"/healthz"); code != http.StatusOK {
+		t.Fatalf("expected liveness to pass, got %d", code)
+	}
+
+	code, status := getHealth(t, base+"/readyz")
+	if code != http.StatusServiceUnavailable || status.Checks["sdk"] != "not running" ||
+		status.Checks["matcher_stream"] != "not connected" || status.Checks["registry_heartbeat"] != "no successful heartbeat" {
+		t.Fatalf("expected every subsystem to be reported unhealthy, got %d %+v", code, status)
+	}
+
+	sdk.mu.Lock()
+	sdk.running.Store(true)
+	sdk.mu.Unlock()
+	sdk.taskStream.connected()
+	sdk.lastHeartbeat.Store(time.Now().Add(-5 * time.Minute).UnixNano())
+	code, status = getHealth(t, base+"/readyz")
+	if code != http.StatusServiceUnavailable || status.Checks["sdk"] != "ok" || status.Checks["registry_heartbeat"] == "ok" {
+		t.Fatalf("expected only a stale heartbeat to fail readiness, got %d %+v", code, status)
+	}
+
+	sdk.lastHeartbeat.Store(time.Now().UnixNano())
+	if code, status = getHealth(t, base+"/readyz"); code != http.StatusOK || status.Status != "ready" {
+		t.Fatalf("expected readiness to pass, got %d %+v", code, status)
+	}
+}
+
+func TestHealthServerServesMetricsJSON(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.HealthAddr = "127.0.0.1:0"
+		cfg.Identity = &IdentityConfig{SubnetID: "subnet-1", AgentID: "agent-1"}
+	})
+	if err := sdk.startHealthServer(); err != nil {
+		t.Fatalf("start health server: %v", err)
+	}
+	defer sdk.stopHealthServer()
+
+	sdk.metrics.RecordTaskTypeOutcome("storage", true, time.Second)
+	sdk.metrics.RecordTaskSuccess()
+	sdk.metrics.RecordEarnings(42)
+	sdk.running.Store(true)
+	sdk.startedAt.Store(time.Now().Add(-time.Minute).UnixNano())
+	sdk.taskStream.connected()
+	sdk.pendingReports.track("report-1", "task-1")
+	sdk.pendingReports.attempt("report-1")
+	sdk.pendingReports.fail("report-1", errors.New("validator unavailable"))
+
+	resp, err := http.Get("http://" + sdk.healthAddr + "/metrics.json")
+	if err != nil {
+		t.Fatalf("get metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	var report metricsReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("decode metrics: %v", err)
+	}
+
+	if report.AgentID != "agent-1" || report.SubnetID != "subnet-1" || !report.Running || report.UptimeSeconds < 60 {
+		t.Fatalf("unexpected agent status %+v", report)
+	}
+	if !report.Connection.MatcherStreamConnected || report.Connection.Task.Status != StreamConnected {
+		t.Fatalf("expected the connected task stream, got %+v", report.Connection)
+	}
+	if report.Metrics.TasksCompleted != 1 || report.Metrics.TotalEarnings != 42 || report.MetricsByType["storage"].TasksCompleted != 1 {
+		t.Fatalf("unexpected metrics %+v %+v", report.Metrics, report.MetricsByType)
+	}
+	if len(report.PendingReports) != 1 {
+		t.Fatalf("expected the stuck report to be listed, got %+v", report.PendingReports)
+	}
+	if pending := report.PendingReports[0]; pending.ReportID != "report-1" || pending.TaskID != "task-1" ||
+		pending.Attempts != 1 || pending.LastError != "validator unavailable" || pending.Age <= 0 {
+		t.Fatalf("unexpected pending report %+v", pending)
+	}
+}
@@ -0,0 +1,117 @@
+package agentsdk
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Logger receives the SDK's internal log output.
+// kv holds alternating key/value pairs, e.g. logger.Info("task completed", "task_id", id).
+type Logger interface {
+	Debug(msg string, kv ...any)
+	Info(msg string, kv ...any)
+	Warn(msg string, kv ...any)
+	Error(msg string, kv ...any)
+}
+
+// Log levels accepted by Config.LogLevel
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+type logLevel int
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+// parseLogLevel maps a Config.LogLevel value to its severity; an empty level means info
+func parseLogLevel(level string) (logLevel, error) {
+	switch strings.ToLower(level) {
+	case LogLevelDebug:
+		return levelDebug, nil
+	case "", LogLevelInfo:
+		return levelInfo, nil
+	case LogLevelWarn, "warning":
+		return levelWarn, nil
+	case LogLevelError:
+		return levelError, nil
+	default:
+		return levelInfo, fmt.Errorf("unsupported log level %q", level)
+	}
+}
+
+// levelLogger drops messages below min before passing them to next
+type levelLogger struct {
+	next Logger
+	min  logLevel
+}
+
+// newLevelLogger applies the configured LogLevel to logger, defaulting to the standard logger
+func newLevelLogger(logger Logger, level string) Logger {
+	if logger == nil {
+		logger = &stdLogger{logger: log.Default()}
+	}
+	min, _ := parseLogLevel(level)
+	return &levelLogger{next: logger, min: min}
+}
+
+func (l *levelLogger) Debug(msg string, kv ...any) {
+	if l.min <= levelDebug {
+		l.next.Debug(msg, kv...)
+	}
+}
+
+func (l *levelLogger) Info(msg string, kv ...any) {
+	if l.min <= levelInfo {
+		l.next.Info(msg, kv...)
+	}
+}
+
+func (l *levelLogger) Warn(msg string, kv ...any) {
+	if l.min <= levelWarn {
+		l.next.Warn(msg, kv...)
+	}
+}
+
+func (l *levelLogger) Error(msg string, kv ...any) {
+	l.next.Error(msg, kv...)
+}
+
+// stdLogger writes key/value formatted lines to a standard library logger
+type stdLogger struct {
+	logger *log.Logger
+}
+
+// NewStdLogger returns a Logger backed by the standard log package that discards
+// messages below level ("debug", "info", "warn" or "error"; defaults to "info").
+func NewStdLogger(level string) Logger {
+	return newLevelLogger(&stdLogger{logger: log.Default()}, level)
+}
+
+func (l *stdLogger) Debug(msg string, kv ...any) { l.log("DEBUG", msg, kv) }
+func (l *stdLogger) Info(msg string, kv ...any)  { l.log("INFO", msg, kv) }
+func (l *stdLogger) Warn(msg string, kv ...any)  { l.log("WARN", msg, kv) }
+func (l *stdLogger) Error(msg string, kv ...any) { l.log("ERROR", msg, kv) }
+
+func (l *stdLogger) log(level, msg string, kv []any) {
+	var b strings.Builder
+	b.WriteString(level)
+	b.WriteString(" ")
+	b.WriteString(msg)
+	for i := 0; i < len(kv); i += 2 {
+		if i+1 < len(kv) {
+			fmt.Fprintf(&b, " %v=%v", kv[i], kv[i+1])
+		} else {
+			fmt.Fprintf(&b, " %v", kv[i])
+		}
+	}
+	l.logger.Print(b.String())
+}
@@ -0,0 +1,38 @@
+package agentsdk
+
+import (
+	"bytes"
+	"log"
+	"testing"
+)
+
+func TestLevelLoggerRespectsLogLevel(t *testing.T) {
+	for _, tc := range []struct {
+		level string
+		want  string
+	}{
+		{"debug", "DEBUG stream chatter\nINFO task completed task_id=task-1\nWARN bid rejected reason=late\nERROR report failed\n"},
+		{"INFO", "INFO task completed task_id=task-1\nWARN bid rejected reason=late\nERROR report failed\n"},
+		{"warn", "WARN bid rejected reason=late\nERROR report failed\n"},
+		{"error", "ERROR report failed\n"},
+	} {
+		var buf bytes.Buffer
+		logger := newLevelLogger(&stdLogger{logger: log.New(&buf, "", 0)}, tc.level)
+
+		logger.Debug("stream chatter")
+		logger.Info("task completed", "task_id", "task-1")
+		logger.Warn("bid rejected", "reason", "late")
+		logger.Error("report failed")
+
+		if got := buf.String(); got != tc.want {
+			t.Fatalf("level %s: got %q, want %q", tc.level, got, tc.want)
+		}
+	}
+}
+
+func TestNewRejectsUnknownLogLevel(t *testing.T) {
+	_, err := New(&Config{AgentID: "agent-1", MatcherAddr: "matcher:8090", Capabilities: []string{"compute"}, LogLevel: "verbose"})
+	if err == nil {
+		t.Fatal("expected unknown log level to be rejected")
+	}
+}
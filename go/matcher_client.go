@@ -4,22 +4,28 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"sync/atomic"
+	"time"
 
 	pb "subnet/proto/subnet"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // MatcherClient wraps the gRPC MatcherService client with simplified interface
 type MatcherClient struct {
-	conn   *grpc.ClientConn
-	client pb.MatcherServiceClient
+	conn        *grpc.ClientConn
+	client      pb.MatcherServiceClient
+	recvTimeout time.Duration
+	taskBuffer  int
+	logger      Logger
 }
 
 // NewMatcherClient creates a new matcher client
-func NewMatcherClient(target string, signingConfig *SigningConfig, secure bool) (*MatcherClient, error) {
-	conn, err := DialOption(target, signingConfig, secure)
+func NewMatcherClient(target string, signingConfig *SigningConfig, secure bool, opts ...grpc.DialOption) (*MatcherClient, error) {
+	conn, err := DialOption(target, signingConfig, secure, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial matcher: %w", err)
 	}
@@ -27,6 +33,7 @@ func NewMatcherClient(target string, signingConfig *SigningConfig, secure bool)
 	return &MatcherClient{
 		conn:   conn,
 		client: pb.NewMatcherServiceClient(conn),
+		logger: NewStdLogger(LogLevelInfo),
 	}, nil
 }
 
@@ -38,6 +45,26 @@ func (c *MatcherClient) Close() error {
 	return nil
 }
 
+// SetStreamReceiveTimeout bounds how long a single stream Recv may block before the stream is torn down.
+// A zero duration disables the timeout.
+func (c *MatcherClient) SetStreamReceiveTimeout(timeout time.Duration) {
+	c.recvTimeout = timeout
+}
+
+// SetTaskBufferSize sets how many received tasks StreamTasks buffers ahead of its consumer. With the
+// default of zero each task is handed over before the next Recv, so a consumer that stops reading
+// stops the stream and gRPC flow control throttles the matcher.
+func (c *MatcherClient) SetTaskBufferSize(size int) {
+	c.taskBuffer = size
+}
+
+// SetLogger routes the client's log output to logger
+func (c *MatcherClient) SetLogger(logger Logger) {
+	if logger != nil {
+		c.logger = logger
+	}
+}
+
 // SubmitBid submits a bid to the matcher
 func (c *MatcherClient) SubmitBid(ctx context.Context, req *pb.SubmitBidRequest) (*pb.SubmitBidResponse, error) {
 	return c.client.SubmitBid(ctx, req)
@@ -53,40 +80,48 @@ func (c *MatcherClient) StreamIntents(ctx context.Context, req *pb.StreamIntents
 	intentCh := make(chan *pb.MatcherIntentUpdate)
 	errCh := make(chan error, 1)
 
-	log.Printf("[MatcherClient DEBUG] StreamIntents called with SubnetID: %s", req.SubnetId)
+	c.logger.Debug("StreamIntents called", "subnet_id", req.SubnetId)
 
 	go func() {
 		defer close(intentCh)
 		defer close(errCh)
 
-		log.Printf("[MatcherClient DEBUG] Calling gRPC StreamIntents...")
-		stream, err := c.client.StreamIntents(ctx, req)
+		streamCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		c.logger.Debug("Opening intent stream")
+		stream, err := c.client.StreamIntents(streamCtx, req)
 		if err != nil {
-			log.Printf("[MatcherClient DEBUG] Failed to start intent stream: %v", err)
-			errCh <- fmt.Errorf("failed to start intent stream: %w", err)
+			c.logger.Warn("Failed to start intent stream", "error", err)
+			errCh <- classifyStreamError("intent", err)
 			return
 		}
-		log.Printf("[MatcherClient DEBUG] Intent stream started successfully, entering receive loop...")
+		c.logger.Debug("Intent stream started")
 
 		for {
-			log.Printf("[MatcherClient DEBUG] Waiting for intent update from stream.Recv()...")
-			update, err := stream.Recv()
+			c.logger.Debug("Waiting for intent update")
+			update, timedOut, err := recvWithTimeout(stream.Recv, cancel, c.recvTimeout)
+			if timedOut {
+				c.logger.Warn("Intent stream receive timed out", "timeout", c.recvTimeout)
+				errCh <- &StreamReceiveError{Stream: "intent", Err: fmt.Errorf("receive timed out after %v", c.recvTimeout)}
+				return
+			}
 			if err == io.EOF {
-				log.Printf("[MatcherClient DEBUG] Intent stream EOF")
+				c.logger.Debug("Intent stream EOF")
 				return
 			}
 			if err != nil {
-				log.Printf("[MatcherClient DEBUG] Intent stream Recv error: %v", err)
-				errCh <- fmt.Errorf("intent stream error: %w", err)
+				c.logger.Debug("Intent stream receive error", "error", err)
+				errCh <- classifyStreamError("intent", err)
 				return
 			}
 
-			log.Printf("[MatcherClient DEBUG] Received intent update from stream: %s", update.IntentId)
+			c.logger.Debug("Received intent update", "intent_id", update.IntentId)
 			select {
 			case intentCh <- update:
-				log.Printf("[MatcherClient DEBUG] Sent intent update to channel")
+				c.logger.Debug("Forwarded intent update", "intent_id", update.IntentId)
 			case <-ctx.Done():
-				log.Printf("[MatcherClient DEBUG] Context done while sending update")
+				c.logger.Debug("Context done while forwarding intent update")
 				errCh <- ctx.Err()
 				return
 			}
@@ -98,46 +133,53 @@ func (c *MatcherClient) StreamIntents(ctx context.Context, req *pb.StreamIntents
 
 // StreamTasks streams execution tasks for an agent
 func (c *MatcherClient) StreamTasks(ctx context.Context, req *pb.StreamTasksRequest) (<-chan *pb.ExecutionTask, <-chan error) {
-	taskCh := make(chan *pb.ExecutionTask)
+	taskCh := make(chan *pb.ExecutionTask, c.taskBuffer)
 	errCh := make(chan error, 1)
 
-	log.Printf("[MatcherClient DEBUG] StreamTasks called with AgentID: %s", req.AgentId)
+	c.logger.Debug("StreamTasks called", "agent_id", req.AgentId)
 
 	go func() {
 		defer close(taskCh)
 		defer close(errCh)
 
-		log.Printf("[MatcherClient DEBUG] Calling gRPC StreamTasks...")
-		stream, err := c.client.StreamTasks(ctx, req)
+		streamCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		c.logger.Debug("Opening task stream")
+		stream, err := c.client.StreamTasks(streamCtx, req)
 		if err != nil {
-			log.Printf("[MatcherClient DEBUG] Failed to start task stream: %v", err)
-			errCh <- fmt.Errorf("failed to start task stream: %w", err)
+			c.logger.Warn("Failed to start task stream", "error", err)
+			errCh <- classifyStreamError("task", err)
 			return
 		}
 
-		log.Printf("[MatcherClient DEBUG] Task stream started successfully, entering receive loop...")
+		c.logger.Debug("Task stream started")
 
 		for {
-			log.Printf("[MatcherClient DEBUG] Waiting for task from stream.Recv()...")
-			task, err := stream.Recv()
+			c.logger.Debug("Waiting for task")
+			task, timedOut, err := recvWithTimeout(stream.Recv, cancel, c.recvTimeout)
+			if timedOut {
+				c.logger.Warn("Task stream receive timed out", "timeout", c.recvTimeout)
+				errCh <- &StreamReceiveError{Stream: "task", Err: fmt.Errorf("receive timed out after %v", c.recvTimeout)}
+				return
+			}
 			if err == io.EOF {
-				log.Printf("[MatcherClient DEBUG] Task stream EOF received")
+				c.logger.Debug("Task stream EOF")
 				return
 			}
 			if err != nil {
-				log.Printf("[MatcherClient DEBUG] Task stream error: %v", err)
-				errCh <- fmt.Errorf("task stream error: %w", err)
+				c.logger.Debug("Task stream receive error", "error", err)
+				errCh <- classifyStreamError("task", err)
 				return
 			}
 
-			log.Printf("[MatcherClient DEBUG] Received task from stream: %s", task.TaskId)
-			log.Printf("[MatcherClient DEBUG] Sent task to channel")
+			c.logger.Debug("Received task", "task_id", task.TaskId)
 
 			select {
 			case taskCh <- task:
-				log.Printf("[MatcherClient DEBUG] Task sent to channel successfully")
+				c.logger.Debug("Forwarded task", "task_id", task.TaskId)
 			case <-ctx.Done():
-				log.Printf("[MatcherClient DEBUG] Context done while sending task")
+				c.logger.Debug("Context done while forwarding task")
 				errCh <- ctx.Err()
 				return
 			}
@@ -149,12 +191,72 @@ func (c *MatcherClient) StreamTasks(ctx context.Context, req *pb.StreamTasksRequ
 
 // RespondToTask sends task acceptance/rejection to matcher
 func (c *MatcherClient) RespondToTask(ctx context.Context, req *pb.RespondToTaskRequest) (*pb.RespondToTaskResponse, error) {
-	log.Printf("[MatcherClient DEBUG] RespondToTask called for task: %s, accepted: %t", req.Response.TaskId, req.Response.Accepted)
+	c.logger.Debug("RespondToTask called", "task_id", req.Response.TaskId, "accepted", req.Response.Accepted)
 	resp, err := c.client.RespondToTask(ctx, req)
 	if err != nil {
-		log.Printf("[MatcherClient DEBUG] RespondToTask failed: %v", err)
+		c.logger.Warn("RespondToTask failed", "error", err)
 		return nil, err
 	}
-	log.Printf("[MatcherClient DEBUG] RespondToTask succeeded")
+	c.logger.Debug("RespondToTask succeeded")
 	return resp, nil
 }
+
+// StreamSubscriptionError reports that a stream subscription could not be established or was
+// rejected by the matcher (e.g. authentication failure). Retrying is unlikely to help.
+type StreamSubscriptionError struct {
+	Stream string // "task" or "intent"
+	Err    error
+}
+
+func (e *StreamSubscriptionError) Error() string {
+	return fmt.Sprintf("%s stream subscription rejected: %v", e.Stream, e.Err)
+}
+
+func (e *StreamSubscriptionError) Unwrap() error { return e.Err }
+
+// StreamReceiveError reports a transient failure on an established stream; the SDK reconnects
+type StreamReceiveError struct {
+	Stream string // "task" or "intent"
+	Err    error
+}
+
+func (e *StreamReceiveError) Error() string {
+	return fmt.Sprintf("%s stream error: %v", e.Stream, e.Err)
+}
+
+func (e *StreamReceiveError) Unwrap() error { return e.Err }
+
+// classifyStreamError treats status codes that mean the matcher refused the subscription as
+// subscription errors, whether they surface when the stream is opened or on the first Recv. Every
+// other failure, including an unreachable or restarting matcher (Unavailable, DeadlineExceeded,
+// ResourceExhausted), is transient and reconnected with backoff.
+func classifyStreamError(stream string, err error) error {
+	switch status.Code(err) {
+	case codes.Unauthenticated, codes.PermissionDenied, codes.InvalidArgument:
+		return &StreamSubscriptionError{Stream: stream, Err: err}
+	default:
+		return &StreamReceiveError{Stream: stream, Err: err}
+	}
+}
+
+// recvWithTimeout calls recv, cancelling the stream context if it blocks longer than timeout.
+// The stream is unusable after a timeout and must be re-established by the caller.
+func recvWithTimeout[T any](recv func() (*T, error), cancel context.CancelFunc, timeout time.Duration) (*T, bool, error) {
+	if timeout <= 0 {
+		msg, err := recv()
+		return msg, false, err
+	}
+
+	var timedOut atomic.Bool
+	timer := time.AfterFunc(timeout, func() {
+		timedOut.Store(true)
+		cancel()
+	})
+	msg, err := recv()
+	timer.Stop()
+
+	if err != nil && timedOut.Load() {
+		return nil, true, err
+	}
+	return msg, false, err
+}
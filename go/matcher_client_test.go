@@ -0,0 +1,68 @@
+package agentsdk
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	pb "subnet/proto/subnet"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecvWithTimeoutCancelsBlockedRecv(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	recv := func() (*struct{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	_, timedOut, err := recvWithTimeout(recv, cancel, 10*time.Millisecond)
+	if !timedOut || err == nil {
+		t.Fatalf("expected blocked recv to time out, got timedOut=%v err=%v", timedOut, err)
+	}
+}
+
+func TestRecvWithTimeoutPassesThroughMessages(t *testing.T) {
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	msg := &struct{}{}
+	got, timedOut, err := recvWithTimeout(func() (*struct{}, error) { return msg, nil }, cancel, time.Second)
+	if timedOut || err != nil || got != msg {
+		t.Fatalf("unexpected result: msg=%v timedOut=%v err=%v", got, timedOut, err)
+	}
+}
+
+func TestStreamOpenFailuresAgainstUnreachableMatcherAreTransient(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	target := listener.Addr().String()
+	listener.Close()
+
+	client, err := NewMatcherClient(target, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected dial error: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, taskErrs := client.StreamTasks(ctx, &pb.StreamTasksRequest{AgentId: "agent-1"})
+	_, intentErrs := client.StreamIntents(ctx, &pb.StreamIntentsRequest{SubnetId: "subnet-1"})
+	for _, errs := range []<-chan error{taskErrs, intentErrs} {
+		err := <-errs
+		var subscriptionErr *StreamSubscriptionError
+		var receiveErr *StreamReceiveError
+		if errors.As(err, &subscriptionErr) || !errors.As(err, &receiveErr) || status.Code(err) != codes.Unavailable {
+			t.Fatalf("expected a transient Unavailable error, got %v", err)
+		}
+	}
+}
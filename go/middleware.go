@@ -0,0 +1,99 @@
+package agentsdk
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// HandlerFunc adapts an ordinary function to the Handler interface
+type HandlerFunc func(ctx context.Context, task *Task) (*Result, error)
+
+// Execute calls f(ctx, task)
+func (f HandlerFunc) Execute(ctx context.Context, task *Task) (*Result, error) {
+	return f(ctx, task)
+}
+
+// HandlerMiddleware wraps a Handler with cross-cutting behaviour such as logging, auth or metrics
+type HandlerMiddleware func(Handler) Handler
+
+// Use appends middleware applied around the registered handlers whenever a task executes. The first
+// middleware is the outermost, as with net/http middleware. TaskAcceptor is still checked on the
+// registered handler itself.
+func (sdk *SDK) Use(middleware ...HandlerMiddleware) {
+	sdk.handlersMu.Lock()
+	defer sdk.handlersMu.Unlock()
+	sdk.middleware = append(sdk.middleware, middleware...)
+}
+
+// wrappedHandler returns the handler for taskType wrapped in the middleware chain, or nil when
+// no handler applies
+func (sdk *SDK) wrappedHandler(taskType string) Handler {
+	sdk.handlersMu.RLock()
+	defer sdk.handlersMu.RUnlock()
+	handler := sdk.routeHandler(taskType)
+	if handler == nil {
+		return nil
+	}
+	return chainHandler(handler, sdk.middleware)
+}
+
+// chainHandler wraps handler so that middleware[0] runs first
+func chainHandler(handler Handler, middleware []HandlerMiddleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// PanicStackMetadataKey is the Result.Metadata key holding the stack trace of a recovered handler panic
+const PanicStackMetadataKey = "panic_stack"
+
+// RecoverMiddleware turns a handler panic into a failed Result, with the stack trace under
+// PanicStackMetadataKey, and an error
+func RecoverMiddleware() HandlerMiddleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, task *Task) (result *Result, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					result, err = panicResult(r)
+				}
+			}()
+			return next.Execute(ctx, task)
+		})
+	}
+}
+
+// HandlerPanicError is returned for a task whose handler panicked
+type HandlerPanicError struct {
+	Value any    // value passed to panic
+	Stack string // stack trace of the panicking goroutine
+}
+
+func (e *HandlerPanicError) Error() string {
+	return fmt.Sprintf("handler panicked: %v", e.Value)
+}
+
+// panicResult converts a recovered panic value into a failed result and a *HandlerPanicError
+func panicResult(recovered any) (*Result, error) {
+	err := &HandlerPanicError{Value: recovered, Stack: string(debug.Stack())}
+	return &Result{
+		Success:  false,
+		Error:    err.Error(),
+		Metadata: map[string]string{PanicStackMetadataKey: err.Stack},
+	}, err
+}
+
+// TimingMiddleware calls observe with each task's handler duration and error, e.g. to feed a
+// per-task-type latency histogram
+func TimingMiddleware(observe func(task *Task, duration time.Duration, err error)) HandlerMiddleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, task *Task) (*Result, error) {
+			start := time.Now()
+			result, err := next.Execute(ctx, task)
+			observe(task, time.Since(start), err)
+			return result, err
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package agentsdk
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUseAppliesMiddlewareOutermostFirst(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	var calls []string
+	trace := func(name string) HandlerMiddleware {
+		return func(next Handler) Handler {
+			return HandlerFunc(func(ctx context.Context, task *Task) (*Result, error) {
+				calls = append(calls, name+":before")
+				result, err := next.Execute(ctx, task)
+				calls = append(calls, name+":after")
+				return result, err
+			})
+		}
+	}
+	sdk.RegisterHandler(HandlerFunc(func(ctx context.Context, task *Task) (*Result, error) {
+		calls = append(calls, "handler")
+		return &Result{Success: true}, nil
+	}))
+	sdk.Use(trace("outer"), trace("inner"))
+	sdk.running.Store(true)
+
+	if _, err := sdk.ExecuteTask(context.Background(), &Task{ID: "task-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"outer:before", "inner:before", "handler", "inner:after", "outer:after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("expected %v, got %v", want, calls)
+	}
+}
+
+func TestRegisterHandlerFunc(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	sdk.RegisterHandlerFunc(func(ctx context.Context, task *Task) (*Result, error) {
+		return &Result{Data: task.Data, Success: true}, nil
+	})
+	sdk.running.Store(true)
+
+	result, err := sdk.ExecuteTask(context.Background(), &Task{ID: "task-1", Data: []byte("echo")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success || string(result.Data) != "echo" {
+		t.Fatalf("expected the function's result, got %+v", result)
+	}
+}
+
+func TestBuiltinMiddleware(t *testing.T) {
+	panicking := HandlerFunc(func(ctx context.Context, task *Task) (*Result, error) {
+		panic("boom")
+	})
+
+	var observed time.Duration
+	var observedErr error
+	handler := chainHandler(panicking, []HandlerMiddleware{
+		TimingMiddleware(func(task *Task, duration time.Duration, err error) {
+			observed, observedErr = duration, err
+		}),
+		RecoverMiddleware(),
+	})
+
+	result, err := handler.Execute(context.Background(), &Task{ID: "task-1"})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected the panic to surface as an error, got %v", err)
+	}
+	if result == nil || result.Success || !strings.Contains(result.Metadata[PanicStackMetadataKey], "middleware_test.go") {
+		t.Fatalf("expected a failed result with the panic stack, got %+v", result)
+	}
+	if observed <= 0 || !errors.Is(observedErr, err) {
+		t.Fatalf("expected the timing middleware to observe the failure, got %v %v", observed, observedErr)
+	}
+}
+
+func TestExecuteTaskRecoversHandlerPanics(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	callbacks := &recordingCallbacks{}
+	sdk.RegisterHandler(HandlerFunc(func(ctx context.Context, task *Task) (*Result, error) {
+		var m map[string]int
+		m["boom"]++ // nil map write panics
+		return &Result{Success: true}, nil
+	}))
+	sdk.RegisterCallbacks(callbacks)
+	sdk.running.Store(true)
+
+	result, err := sdk.ExecuteTask(context.Background(), &Task{ID: "task-1"})
+	var panicErr *HandlerPanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected a HandlerPanicError, got %v", err)
+	}
+	if result == nil || result.Success || result.Metadata[PanicStackMetadataKey] == "" {
+		t.Fatalf("expected a failed result carrying the stack trace, got %+v", result)
+	}
+	if snapshot := sdk.metrics.Snapshot(); snapshot.TasksFailed != 1 || snapshot.TasksCompleted != 0 {
+		t.Fatalf("expected the panic to count as a task failure, got %+v", snapshot)
+	}
+	if len(callbacks.errs) != 1 || !errors.As(callbacks.errs[0], &panicErr) {
+		t.Fatalf("expected OnError with the panic, got %v", callbacks.errs)
+	}
+}
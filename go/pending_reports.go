@@ -0,0 +1,99 @@
+package agentsdk
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// PendingReport describes an execution report the agent has not yet had acknowledged by a validator
+type PendingReport struct {
+	ReportID  string        `json:"report_id"`
+	TaskID    string        `json:"task_id"`
+	Attempts  int           `json:"attempts"`
+	LastError string        `json:"last_error,omitempty"`
+	Age       time.Duration `json:"age_ns"`
+}
+
+type pendingReportState struct {
+	taskID    string
+	attempts  int
+	lastError string
+	createdAt time.Time
+}
+
+// pendingReportTracker records reports between task completion and validator acknowledgement
+type pendingReportTracker struct {
+	mu      sync.Mutex
+	reports map[string]*pendingReportState
+}
+
+func newPendingReportTracker() *pendingReportTracker {
+	return &pendingReportTracker{reports: make(map[string]*pendingReportState)}
+}
+
+// track registers a report as outstanding, keeping existing state if it is already tracked
+func (t *pendingReportTracker) track(reportID, taskID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.reports[reportID]; ok {
+		return
+	}
+	t.reports[reportID] = &pendingReportState{taskID: taskID, createdAt: time.Now()}
+}
+
+// attempt counts a submission attempt for a tracked report
+func (t *pendingReportTracker) attempt(reportID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if state, ok := t.reports[reportID]; ok {
+		state.attempts++
+	}
+}
+
+// fail records the most recent submission error for a tracked report; a nil error leaves it as is
+func (t *pendingReportTracker) fail(reportID string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if state, ok := t.reports[reportID]; ok && err != nil {
+		state.lastError = err.Error()
+	}
+}
+
+// done stops tracking a report once it is acknowledged or abandoned
+func (t *pendingReportTracker) done(reportID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.reports, reportID)
+}
+
+// snapshot returns the outstanding reports, oldest first
+func (t *pendingReportTracker) snapshot() []PendingReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	reports := make([]PendingReport, 0, len(t.reports))
+	for reportID, state := range t.reports {
+		reports = append(reports, PendingReport{
+			ReportID:  reportID,
+			TaskID:    state.taskID,
+			Attempts:  state.attempts,
+			LastError: state.lastError,
+			Age:       now.Sub(state.createdAt),
+		})
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Age > reports[j].Age
+	})
+	return reports
+}
+
+// PendingReports returns the execution reports the agent still owes validators, oldest first.
+// Reports stay pending while queued for submission, while a submission is in flight, and after a
+// failed submission while their result is persisted for re-submission. Attempts counts every
+// submission attempt, retries and each validator included, and LastError is the error of the
+// latest failed one. The list is also served as pending_reports in /metrics.json.
+func (sdk *SDK) PendingReports() []PendingReport {
+	return sdk.pendingReports.snapshot()
+}
@@ -0,0 +1,40 @@
+package agentsdk_test
+
+import (
+	"testing"
+	"time"
+
+	agentsdk "github.com/PIN-AI/subnet-sdk/go"
+	"github.com/PIN-AI/subnet-sdk/go/testutil"
+)
+
+// BenchmarkTaskPipeline measures sustained streamed-task throughput through execution and
+// report submission against in-memory matcher and validator fakes.
+func BenchmarkTaskPipeline(b *testing.B) {
+	b.Run("noop", func(b *testing.B) {
+		benchmarkTaskPipeline(b, &agentsdk.Config{MaxConcurrentTasks: 64}, &testutil.NoopHandler{})
+	})
+	b.Run("latency_1ms", func(b *testing.B) {
+		benchmarkTaskPipeline(b, &agentsdk.Config{MaxConcurrentTasks: 64}, testutil.NewLatencyHandler(time.Millisecond))
+	})
+	// Tasks arrive faster than the handler drains them, so most are rejected at capacity
+	b.Run("overload", func(b *testing.B) {
+		benchmarkTaskPipeline(b, &agentsdk.Config{MaxConcurrentTasks: 4, BidTimeout: time.Millisecond},
+			testutil.NewRandomLatencyHandler(time.Millisecond, 5*time.Millisecond))
+	})
+}
+
+func benchmarkTaskPipeline(b *testing.B, cfg *agentsdk.Config, handler agentsdk.Handler) {
+	sdk := agentsdk.NewPipelineForTest(b, cfg, handler)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sdk.DispatchTestTask(i, "compute")
+	}
+	sdk.WaitForTestTasks()
+	b.StopTimer()
+
+	snapshot := sdk.GetMetrics().Snapshot()
+	b.ReportMetric(float64(snapshot.ReportsSubmitted)/float64(b.N), "reports/op")
+	b.ReportMetric(float64(int64(b.N)-snapshot.TasksCompleted-snapshot.TasksFailed)/float64(b.N), "rejected/op")
+}
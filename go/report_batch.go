@@ -0,0 +1,168 @@
+package agentsdk
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "subnet/proto/subnet"
+)
+
+// reportBatchingEnabled reports whether streamed task reports are submitted in batches
+func (c *Config) reportBatchingEnabled() bool {
+	return c.ReportBatchSize > 0
+}
+
+// batchedReport tracks one report of a batch across the validators it is submitted to
+type batchedReport struct {
+	job      reportJob
+	report   *pb.ExecutionReport
+	receipts []*ExecutionReceipt
+	errs     []error
+}
+
+// reportBatchLoop collects queued reports and submits them once ReportBatchSize reports are pending
+// or ReportBatchFlushInterval elapses. Once drain is closed, queued reports are flushed right away and
+// later ones as they arrive; reports still pending when ctx is cancelled are flushed before it returns.
+func (sdk *SDK) reportBatchLoop(ctx context.Context, drain <-chan struct{}) {
+	defer sdk.reportWG.Done()
+
+	ticker := time.NewTicker(sdk.config.ReportBatchFlushInterval)
+	defer ticker.Stop()
+
+	var (
+		batch    []reportJob
+		draining bool
+	)
+	flush := func() {
+		for len(batch) > 0 {
+			n := min(len(batch), sdk.config.ReportBatchSize)
+			sdk.flushReports(ctx, batch[:n])
+			batch = batch[n:]
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			batch = append(batch, sdk.drainReportQueue()...)
+			flush()
+			return
+		case <-drain:
+			drain = nil
+			draining = true
+			batch = append(batch, sdk.drainReportQueue()...)
+			flush()
+		case job := <-sdk.reportQueue:
+			batch = append(batch, job)
+			if draining || len(batch) >= sdk.config.ReportBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// drainReportQueue removes the reports currently waiting in the queue without blocking
+func (sdk *SDK) drainReportQueue() []reportJob {
+	var drained []reportJob
+	for {
+		select {
+		case job := <-sdk.reportQueue:
+			drained = append(drained, job)
+		default:
+			return drained
+		}
+	}
+}
+
+// flushReports submits a batch of reports with SubmitExecutionReportBatch, trying validators in order
+// until each report has ReportFinalizeThreshold receipts. The validator returns receipts in request
+// order; a missing receipt counts as a failed submission of that report. Trace context is not
+// propagated per report, since the batch shares one request.
+func (sdk *SDK) flushReports(ctx context.Context, jobs []reportJob) {
+	entries := make([]*batchedReport, 0, len(jobs))
+	for _, job := range jobs {
+		sdk.pendingReports.track(job.reportID, job.task.ID)
+
+		report, err := sdk.buildTaskReport(job.reportID, job.task, job.result)
+		if err != nil {
+			sdk.finishReport(job.reportID, job.task, job.result, nil, err)
+			sdk.completeReportJob(job, nil, err)
+			continue
+		}
+		entries = append(entries, &batchedReport{job: job, report: report})
+	}
+
+	for _, validator := range sdk.clients().validators {
+		var remaining []*batchedReport
+		for _, entry := range entries {
+			if len(entry.receipts) < sdk.config.ReportFinalizeThreshold {
+				remaining = append(remaining, entry)
+			}
+		}
+		if len(remaining) == 0 {
+			break
+		}
+
+		partialOK := true
+		req := &pb.ExecutionReportBatchRequest{
+			Reports:   make([]*pb.ExecutionReport, 0, len(remaining)),
+			BatchId:   fmt.Sprintf("batch-%s", hex.EncodeToString(randomBytes(sdk.config.RandSource, 16))),
+			PartialOk: &partialOK,
+		}
+		encodings := make([]string, 0, len(remaining))
+		reportIDs := make([]string, 0, len(remaining))
+		for _, entry := range remaining {
+			req.Reports = append(req.Reports, entry.report)
+			encodings = append(encodings, sdk.config.resultEncoding(entry.job.result.Data))
+			reportIDs = append(reportIDs, entry.job.reportID)
+		}
+
+		var resp *pb.ExecutionReportBatchResponse
+		err := sdk.submitWithRetry(withResultEncodings(ctx, encodings...), validator.addr, reportIDs, func(ctx context.Context) error {
+			var err error
+			resp, err = validator.client.SubmitExecutionReportBatch(ctx, req)
+			return err
+		})
+		if err != nil {
+			sdk.logger.Warn("Failed to submit execution report batch", "batch_id", req.BatchId, "reports", len(remaining),
+				"validator", validator.addr, "error", err)
+			for _, entry := range remaining {
+				sdk.metrics.RecordReportFailure()
+				entry.errs = append(entry.errs, fmt.Errorf("%s: %w", validator.addr, err))
+			}
+			continue
+		}
+
+		for i, entry := range remaining {
+			if i >= len(resp.Receipts) || resp.Receipts[i] == nil {
+				sdk.metrics.RecordReportFailure()
+				entry.errs = append(entry.errs, fmt.Errorf("%s: no receipt in batch response", validator.addr))
+				continue
+			}
+			sdk.metrics.RecordReportSuccess()
+			receipt := receiptFromProto(resp.Receipts[i], validator.addr)
+			entry.receipts = append(entry.receipts, receipt)
+			submitted := executionReportFromProto(entry.report)
+			submitted.ResultData = entry.job.result.Data
+			sdk.fireCallback("OnReportSubmitted", submitted, receipt)
+		}
+		sdk.logger.Debug("Execution report batch submitted", "batch_id", req.BatchId, "reports", len(remaining),
+			"validator", validator.addr, "success", resp.Success, "failed", resp.Failed)
+	}
+
+	for _, entry := range entries {
+		var err error
+		if len(entry.receipts) == 0 {
+			if len(entry.errs) == 0 {
+				entry.errs = append(entry.errs, errors.New("validator client not initialized"))
+			}
+			err = fmt.Errorf("submit execution report %s: %w", entry.job.reportID, errors.Join(entry.errs...))
+		}
+		sdk.finishReport(entry.job.reportID, entry.job.task, entry.job.result, entry.receipts, err)
+		sdk.completeReportJob(entry.job, entry.receipts, err)
+	}
+}
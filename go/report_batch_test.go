@@ -0,0 +1,90 @@
+package agentsdk
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	pb "subnet/proto/subnet"
+)
+
+// batchValidatorService records batch sizes and returns no receipt for reports listed in drop
+type batchValidatorService struct {
+	pb.ValidatorServiceClient
+	mu      sync.Mutex
+	err     error
+	drop    map[string]bool
+	batches []int
+}
+
+func (v *batchValidatorService) SubmitExecutionReportBatch(ctx context.Context, in *pb.ExecutionReportBatchRequest, opts ...grpc.CallOption) (*pb.ExecutionReportBatchResponse, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.batches = append(v.batches, len(in.Reports))
+	if v.err != nil {
+		return nil, v.err
+	}
+	resp := &pb.ExecutionReportBatchResponse{}
+	for _, report := range in.Reports {
+		if v.drop[report.ReportId] {
+			resp.Receipts = append(resp.Receipts, nil)
+			continue
+		}
+		resp.Receipts = append(resp.Receipts, &pb.Receipt{ReportId: report.ReportId, Status: "accepted"})
+	}
+	return resp, nil
+}
+
+func TestReportBatchingFailsOverAndFlushesOnDrain(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		completed = map[string]int{}
+		failed    []string
+	)
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ReportBatchSize = 2
+		cfg.ReportBatchFlushInterval = time.Hour
+		cfg.ReportCompletionCallback = func(task *Task, receipts []*ExecutionReceipt, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			completed[task.ID] = len(receipts)
+			if err != nil {
+				failed = append(failed, task.ID)
+			}
+		}
+	})
+	down := &batchValidatorService{err: errors.New("unavailable")}
+	up := &batchValidatorService{drop: map[string]bool{"report-2": true}}
+	sdk.validators = []validatorTarget{
+		{addr: "validator-1:9090", client: &ValidatorClient{client: down}},
+		{addr: "validator-2:9090", client: &ValidatorClient{client: up}},
+	}
+	sdk.validatorClient = sdk.validators[0].client
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sdk.taskCancel = cancel
+	sdk.startReportWorkers(ctx)
+
+	for _, id := range []string{"1", "2", "3"} {
+		sdk.enqueueReport(ctx, reportJob{reportID: "report-" + id, task: &Task{ID: "task-" + id, IntentID: "intent-" + id}, result: &Result{Success: true}})
+	}
+	sdk.drainTasks(time.Second)
+
+	if !reflect.DeepEqual(down.batches, []int{2, 1}) || !reflect.DeepEqual(up.batches, []int{2, 1}) {
+		t.Fatalf("expected a full batch and a drained partial batch per validator, got %v and %v", down.batches, up.batches)
+	}
+	if !reflect.DeepEqual(completed, map[string]int{"task-1": 1, "task-2": 0, "task-3": 1}) || !reflect.DeepEqual(failed, []string{"task-2"}) {
+		t.Fatalf("receipts not distributed per report: completed=%v failed=%v", completed, failed)
+	}
+	if snapshot := sdk.metrics.Snapshot(); snapshot.ReportsSubmitted != 2 || snapshot.ReportsFailed != 4 {
+		t.Fatalf("expected per-report metrics, got %+v", snapshot)
+	}
+	if pending := sdk.PendingReports(); len(pending) != 0 {
+		t.Fatalf("expected no pending reports after the flush, got %+v", pending)
+	}
+}
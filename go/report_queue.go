@@ -0,0 +1,136 @@
+package agentsdk
+
+import (
+	"context"
+	"fmt"
+)
+
+// Report queue overflow policies
+const (
+	ReportOverflowBlock      = "block"
+	ReportOverflowDropOldest = "drop_oldest"
+	ReportOverflowDropNewest = "drop_newest"
+)
+
+const (
+	defaultReportQueueSize    = 256
+	defaultReportQueueWorkers = 4
+)
+
+// reportJob is a completed task result waiting to be reported to validators
+type reportJob struct {
+	reportID      string
+	task          *Task
+	result        *Result
+	span          Span              // task span, ended once the report outcome is known
+	traceMetadata map[string]string // trace context propagated to validators
+}
+
+// ReportDropCallbacks is an optional extension of Callbacks notified when the report queue
+// overflows under a drop policy. Implement it alongside Callbacks.
+type ReportDropCallbacks interface {
+	// OnReportDropped is called when a report is discarded without being submitted
+	OnReportDropped(task *Task, reportID string)
+}
+
+func validateReportOverflowPolicy(policy string) error {
+	switch policy {
+	case "", ReportOverflowBlock, ReportOverflowDropOldest, ReportOverflowDropNewest:
+		return nil
+	default:
+		return fmt.Errorf("unsupported report overflow policy %q", policy)
+	}
+}
+
+// startReportWorkers launches the goroutines that submit queued reports until ctx is cancelled.
+// With report batching enabled a single worker submits them in batches instead.
+func (sdk *SDK) startReportWorkers(ctx context.Context) {
+	if sdk.config.reportBatchingEnabled() {
+		sdk.reportDrain = make(chan struct{})
+		sdk.reportWG.Add(1)
+		go sdk.reportBatchLoop(ctx, sdk.reportDrain)
+		return
+	}
+
+	for i := 0; i < defaultReportQueueWorkers; i++ {
+		sdk.reportWG.Add(1)
+		go func() {
+			defer sdk.reportWG.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-sdk.reportQueue:
+					receipts, err := sdk.reportTaskResult(withTraceMetadata(ctx, job.traceMetadata), job.reportID, job.task, job.result)
+					sdk.completeReportJob(job, receipts, err)
+				}
+			}
+		}()
+	}
+}
+
+// completeReportJob ends the task span with the report outcome and releases the job's hold on taskWG
+func (sdk *SDK) completeReportJob(job reportJob, receipts []*ExecutionReceipt, err error) {
+	job.endReportSpan(receipts, err)
+	sdk.taskWG.Done()
+}
+
+// endReportSpan ends the task span with the outcome of submitting its report
+func (job reportJob) endReportSpan(receipts []*ExecutionReceipt, err error) {
+	status := reportStatusSubmitted
+	if len(receipts) == 0 {
+		status = reportStatusFailed
+	}
+	job.endSpan(status, len(receipts), err)
+}
+
+// enqueueReport hands a report to the workers, applying the overflow policy when the queue is full.
+// Queued reports count towards taskWG so Stop drains them along with executing tasks, and show up
+// in PendingReports until they are submitted or discarded.
+func (sdk *SDK) enqueueReport(ctx context.Context, job reportJob) {
+	sdk.taskWG.Add(1)
+	sdk.pendingReports.track(job.reportID, job.task.ID)
+
+	select {
+	case sdk.reportQueue <- job:
+		return
+	default:
+	}
+
+	switch sdk.config.ReportOverflowPolicy {
+	case ReportOverflowDropNewest:
+		sdk.dropReport(job)
+	case ReportOverflowDropOldest:
+		for {
+			select {
+			case oldest := <-sdk.reportQueue:
+				sdk.dropReport(oldest)
+			default:
+			}
+			select {
+			case sdk.reportQueue <- job:
+				return
+			default:
+			}
+		}
+	default:
+		select {
+		case sdk.reportQueue <- job:
+		case <-ctx.Done():
+			sdk.pendingReports.done(job.reportID)
+			job.endSpan(reportStatusDropped, 0, ctx.Err())
+			sdk.taskWG.Done()
+		}
+	}
+}
+
+// dropReport discards a queued report. A persisted result stays on disk and is re-submitted on restart.
+func (sdk *SDK) dropReport(job reportJob) {
+	defer sdk.taskWG.Done()
+
+	sdk.logger.Warn("Report queue full, dropping report", "report_id", job.reportID, "task_id", job.task.ID)
+	sdk.pendingReports.done(job.reportID)
+	sdk.metrics.RecordReportDropped()
+	job.endSpan(reportStatusDropped, 0, nil)
+	sdk.fireCallback("OnReportDropped", job.task, job.reportID)
+}
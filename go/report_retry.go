@@ -0,0 +1,58 @@
+package agentsdk
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	defaultReportRetryBackoff = 500 * time.Millisecond
+	defaultReportRetryJitter  = 0.2
+)
+
+// submitWithRetry calls submit until it succeeds or ReportMaxRetries retries are used up, doubling
+// ReportRetryBackoff between attempts with ReportRetryJitter applied. Retries are counted separately
+// from the success and failure counters, which only see the final outcome. Every attempt and its
+// error are recorded against the pending reports in reportIDs. A ctx ending before the retries are
+// used up stops retrying without counting them as exhausted.
+func (sdk *SDK) submitWithRetry(ctx context.Context, endpoint string, reportIDs []string, submit func(context.Context) error) error {
+	delay := sdk.config.ReportRetryBackoff
+	attempts := 0
+	for {
+		attempts++
+		for _, reportID := range reportIDs {
+			sdk.pendingReports.attempt(reportID)
+		}
+		err := submit(ctx)
+		for _, reportID := range reportIDs {
+			sdk.pendingReports.fail(reportID, err)
+		}
+		if err == nil {
+			sdk.metrics.RecordReportAttempts(endpoint, attempts)
+			return nil
+		}
+
+		if attempts > sdk.config.ReportMaxRetries {
+			sdk.metrics.RecordReportAttempts(endpoint, attempts)
+			if sdk.config.ReportMaxRetries > 0 {
+				sdk.metrics.RecordReportRetryExhausted()
+			}
+			return err
+		}
+		if ctx.Err() != nil {
+			sdk.metrics.RecordReportAttempts(endpoint, attempts)
+			return err
+		}
+
+		sdk.metrics.RecordReportRetry()
+		timer := time.NewTimer(withJitter(delay, sdk.config.ReportRetryJitter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			sdk.metrics.RecordReportAttempts(endpoint, attempts)
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
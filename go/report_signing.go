@@ -0,0 +1,164 @@
+package agentsdk
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// reportSigningPayload builds the canonical JSON signed for execution reports.
+// Keys are sorted by encoding/json and the result data is bound by its Keccak256 hash,
+// matching the canonical scheme used for gRPC request signing.
+func reportSigningPayload(reportID, assignmentID, intentID, agentID string, status ExecutionReportStatus, resultData []byte, timestamp int64) ([]byte, error) {
+	payload := map[string]interface{}{
+		"report_id":     reportID,
+		"assignment_id": assignmentID,
+		"intent_id":     intentID,
+		"agent_id":      agentID,
+		"status":        string(status),
+		"result_hash":   hex.EncodeToString(crypto.Keccak256(resultData)),
+		"timestamp":     timestamp,
+	}
+	return json.Marshal(payload)
+}
+
+// signReport signs the canonical report payload with the agent key.
+// Without a private key it returns an empty signature and warns once.
+func (sdk *SDK) signReport(reportID, assignmentID, intentID, agentID string, status ExecutionReportStatus, resultData []byte, timestamp int64) ([]byte, error) {
+	if sdk.privateKey == nil {
+		sdk.unsignedReportWarning.Do(func() {
+			sdk.logger.Warn("No private key configured; execution reports will be submitted unsigned")
+		})
+		return []byte{}, nil
+	}
+
+	payload, err := reportSigningPayload(reportID, assignmentID, intentID, agentID, status, resultData, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("build report signing payload: %w", err)
+	}
+
+	return signMessage(sdk.privateKey, payload, sdk.config.SignatureScheme, sdk.config.EVMRecoveryID)
+}
+
+// reportFields are the normalized execution report fields covered by the report signature
+type reportFields struct {
+	reportID     string
+	assignmentID string
+	intentID     string
+	agentID      string
+	status       ExecutionReportStatus
+	timestamp    time.Time
+}
+
+// normalizeReport validates report and fills in the defaults applied before signing
+func (sdk *SDK) normalizeReport(report *ExecutionReport) (*reportFields, error) {
+	if report == nil {
+		return nil, errors.New("execution report is required")
+	}
+
+	fields := &reportFields{
+		reportID:     strings.TrimSpace(report.ReportID),
+		assignmentID: strings.TrimSpace(report.AssignmentID),
+		intentID:     strings.TrimSpace(report.IntentID),
+		agentID:      strings.TrimSpace(report.AgentID),
+		status:       report.Status,
+		timestamp:    report.Timestamp,
+	}
+	if fields.agentID == "" {
+		fields.agentID = sdk.GetAgentID()
+	}
+	if fields.status == "" {
+		fields.status = ExecutionReportStatusSuccess
+	}
+	if err := validateExecutionReport(fields.reportID, fields.assignmentID, fields.intentID, fields.agentID, fields.status); err != nil {
+		return nil, err
+	}
+
+	if fields.timestamp.IsZero() {
+		fields.timestamp = time.Now()
+	}
+	return fields, nil
+}
+
+// validateExecutionReport checks the fields validators require of every execution report, whether
+// it is submitted over HTTP or gRPC
+func validateExecutionReport(reportID, assignmentID, intentID, agentID string, status ExecutionReportStatus) error {
+	if reportID == "" {
+		return errors.New("report_id is required")
+	}
+	if assignmentID == "" {
+		return errors.New("assignment_id is required")
+	}
+	if intentID == "" {
+		return errors.New("intent_id is required")
+	}
+	if agentID == "" {
+		return errors.New("agent_id is required")
+	}
+	if !isValidExecutionStatus(status) {
+		return fmt.Errorf("invalid status: %s", status)
+	}
+	return nil
+}
+
+// RecoverAddress returns the checksummed address whose key produced signature over payload,
+// using the default SignatureSchemeRawKeccak scheme.
+func RecoverAddress(payload, signature []byte) (string, error) {
+	return RecoverAddressWithScheme(SignatureSchemeRawKeccak, payload, signature)
+}
+
+// RecoverAddressWithScheme is RecoverAddress for a signature made under scheme. The recovery id
+// may be 0/1 or 27/28.
+func RecoverAddressWithScheme(scheme string, payload, signature []byte) (string, error) {
+	signature, err := NormalizeRecoveryID(signature, false)
+	if err != nil {
+		return "", fmt.Errorf("recover signer: %w", err)
+	}
+	pub, err := crypto.SigToPub(signatureHash(scheme, payload), signature)
+	if err != nil {
+		return "", fmt.Errorf("recover signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub).Hex(), nil
+}
+
+// SelfVerifyReport signs report through the same path SubmitExecutionReport uses and checks that
+// the signature recovers the agent's address. Nothing is sent over the network and report is not
+// modified.
+func (sdk *SDK) SelfVerifyReport(report *ExecutionReport) error {
+	if sdk.privateKey == nil {
+		return errors.New("no private key configured; execution reports are submitted unsigned")
+	}
+
+	fields, err := sdk.normalizeReport(report)
+	if err != nil {
+		return err
+	}
+	resultData, err := sdk.config.encodeResultData(report.ResultData)
+	if err != nil {
+		return err
+	}
+
+	signature, err := sdk.signReport(fields.reportID, fields.assignmentID, fields.intentID, fields.agentID, fields.status, resultData, fields.timestamp.Unix())
+	if err != nil {
+		return fmt.Errorf("sign report: %w", err)
+	}
+	payload, err := reportSigningPayload(fields.reportID, fields.assignmentID, fields.intentID, fields.agentID, fields.status, resultData, fields.timestamp.Unix())
+	if err != nil {
+		return fmt.Errorf("build report signing payload: %w", err)
+	}
+
+	signer, err := RecoverAddressWithScheme(sdk.config.SignatureScheme, payload, signature)
+	if err != nil {
+		return err
+	}
+	if expected := sdk.GetAddress(); common.HexToAddress(signer) != common.HexToAddress(expected) {
+		return fmt.Errorf("report signature recovers %s, expected agent address %s", signer, expected)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package agentsdk
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testPrivateKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+func TestSignReportRecoversAgentAddress(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.PrivateKey = testPrivateKey
+	})
+
+	signature, err := sdk.signReport("report-1", "task-1", "intent-1", sdk.GetAddress(), ExecutionReportStatusSuccess, []byte("done"), 1700000000)
+	if err != nil {
+		t.Fatalf("unexpected signing error: %v", err)
+	}
+
+	payload, err := reportSigningPayload("report-1", "task-1", "intent-1", sdk.GetAddress(), ExecutionReportStatusSuccess, []byte("done"), 1700000000)
+	if err != nil {
+		t.Fatalf("unexpected payload error: %v", err)
+	}
+	pub, err := crypto.SigToPub(crypto.Keccak256(payload), signature)
+	if err != nil {
+		t.Fatalf("unexpected recovery error: %v", err)
+	}
+	if got := crypto.PubkeyToAddress(*pub).Hex(); got != sdk.GetAddress() {
+		t.Fatalf("recovered %s, expected %s", got, sdk.GetAddress())
+	}
+}
+
+func TestSignReportWithoutKeyReturnsEmptySignature(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+
+	signature, err := sdk.signReport("report-1", "task-1", "intent-1", "agent-1", ExecutionReportStatusSuccess, nil, 1700000000)
+	if err != nil || len(signature) != 0 {
+		t.Fatalf("expected empty signature without error, got %x, %v", signature, err)
+	}
+}
+
+func TestSelfVerifyReport(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.PrivateKey = testPrivateKey
+	})
+
+	report := &ExecutionReport{ReportID: "report-1", AssignmentID: "task-1", IntentID: "intent-1", ResultData: []byte("done")}
+	if err := sdk.SelfVerifyReport(report); err != nil {
+		t.Fatalf("expected report to verify, got %v", err)
+	}
+	if !report.Timestamp.IsZero() || report.AgentID != "" {
+		t.Fatalf("SelfVerifyReport should not modify the report, got %+v", report)
+	}
+
+	if err := sdk.SelfVerifyReport(&ExecutionReport{ReportID: "report-1"}); err == nil {
+		t.Fatal("expected an invalid report to fail verification")
+	}
+}
+
+func TestSelfVerifyReportRequiresPrivateKey(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+
+	report := &ExecutionReport{ReportID: "report-1", AssignmentID: "task-1", IntentID: "intent-1"}
+	if err := sdk.SelfVerifyReport(report); err == nil {
+		t.Fatal("expected an error without a private key")
+	}
+}
+
+// Vectors for "Some data" signed with testPrivateKey; the EIP-191 one matches personal_sign
+// output from web3.js and ethers
+func TestSignatureSchemeVectors(t *testing.T) {
+	tests := []struct {
+		scheme    string
+		hash      string
+		signature string
+	}{
+		{
+			scheme:    SignatureSchemeRawKeccak,
+			hash:      "43a26051362b8040b289abe93334a5e3662751aa691185ae9e9a2e1e0c169350",
+			signature: "93da7e2ddd6b2ff1f5af0c752f052ed0d7d5bff19257db547a69cd9a879b37d4334485e42b33815fd2cf8a245a5393b282214060844a9681495df2257140e75c00",
+		},
+		{
+			scheme:    SignatureSchemeEIP191,
+			hash:      "1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655",
+			signature: "b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c",
+		},
+	}
+
+	data := []byte("Some data")
+	for _, tt := range tests {
+		sdk := newTestSDK(t, func(cfg *Config) {
+			cfg.PrivateKey = testPrivateKey
+			cfg.SignatureScheme = tt.scheme
+		})
+		if hash := hex.EncodeToString(signatureHash(tt.scheme, data)); hash != tt.hash {
+			t.Fatalf("%s: hash %s, expected %s", tt.scheme, hash, tt.hash)
+		}
+		signature, err := sdk.Sign(data)
+		if err != nil {
+			t.Fatalf("%s: unexpected signing error: %v", tt.scheme, err)
+		}
+		if got := hex.EncodeToString(signature); got != tt.signature {
+			t.Fatalf("%s: signature %s, expected %s", tt.scheme, got, tt.signature)
+		}
+		signer, err := RecoverAddressWithScheme(tt.scheme, data, signature)
+		if err != nil || signer != "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23" {
+			t.Fatalf("%s: recovered %s, %v", tt.scheme, signer, err)
+		}
+
+		report := &ExecutionReport{ReportID: "report-1", AssignmentID: "task-1", IntentID: "intent-1", ResultData: data}
+		if err := sdk.SelfVerifyReport(report); err != nil {
+			t.Fatalf("%s: expected report to verify, got %v", tt.scheme, err)
+		}
+	}
+
+	if _, err := New(&Config{AgentID: "agent-1", MatcherAddr: "matcher:8090", Capabilities: []string{"compute"}, SignatureScheme: "eip712"}); err == nil {
+		t.Fatal("expected an unknown signature scheme to be rejected")
+	}
+}
+
+func TestEVMRecoveryIDSignaturesRecoverSigner(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.Identity = &IdentityConfig{SubnetID: "subnet-1", AgentID: "agent-1"}
+		cfg.PrivateKey = testPrivateKey
+		cfg.EVMRecoveryID = true
+	})
+
+	headers, err := sdk.SignCanonical("/m", nil)
+	if err != nil {
+		t.Fatalf("SignCanonical: %v", err)
+	}
+	requestSignature, _ := hex.DecodeString(headers[SignatureKey])
+	timestamp, _ := strconv.ParseInt(headers[TimestampKey], 10, 64)
+	canonical, err := canonicalJSON("subnet-1", "/m", timestamp, headers[NonceKey], nil, nil)
+	if err != nil {
+		t.Fatalf("canonicalJSON: %v", err)
+	}
+
+	reportSignature, err := sdk.signReport("report-1", "task-1", "intent-1", "agent-1", ExecutionReportStatusSuccess, []byte("done"), 1700000000)
+	if err != nil {
+		t.Fatalf("unexpected signing error: %v", err)
+	}
+	payload, err := reportSigningPayload("report-1", "task-1", "intent-1", "agent-1", ExecutionReportStatusSuccess, []byte("done"), 1700000000)
+	if err != nil {
+		t.Fatalf("unexpected payload error: %v", err)
+	}
+
+	for name, signed := range map[string]struct{ payload, signature []byte }{
+		"request": {canonical, requestSignature},
+		"report":  {payload, reportSignature},
+	} {
+		if v := signed.signature[64]; v != 27 && v != 28 {
+			t.Fatalf("%s: expected a 27/28 recovery id, got %d", name, v)
+		}
+		signer, err := RecoverAddress(signed.payload, signed.signature)
+		if err != nil || signer != sdk.GetAddress() {
+			t.Fatalf("%s: recovered %s, %v", name, signer, err)
+		}
+
+		raw, err := NormalizeRecoveryID(signed.signature, false)
+		if err != nil || raw[64] != signed.signature[64]-27 {
+			t.Fatalf("%s: expected a 0/1 recovery id, got %v, %v", name, raw, err)
+		}
+		pub, err := crypto.SigToPub(crypto.Keccak256(signed.payload), raw)
+		if err != nil || crypto.PubkeyToAddress(*pub).Hex() != sdk.GetAddress() {
+			t.Fatalf("%s: normalized signature does not recover the signer: %v", name, err)
+		}
+		if back, _ := NormalizeRecoveryID(raw, true); !bytes.Equal(back, signed.signature) {
+			t.Fatalf("%s: expected normalizing back to 27/28 to restore the signature", name)
+		}
+	}
+
+	if _, err := NormalizeRecoveryID(append(make([]byte, 64), 5), true); err == nil {
+		t.Fatal("expected an invalid recovery id to be rejected")
+	}
+}
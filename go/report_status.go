@@ -0,0 +1,107 @@
+package agentsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "subnet/proto/subnet"
+)
+
+// Verdicts a validator records for a verified report in ReportStatus.Verdict
+const (
+	ReportVerdictPass = "PASS"
+	ReportVerdictFail = "FAIL"
+	ReportVerdictSkip = "SKIP" // the validator was not sampled to verify the report
+)
+
+// ReportStatus is the settlement status of a submitted report, built from the validators'
+// verification records
+type ReportStatus struct {
+	ReportID    string
+	Verdict     string // verdict of the latest verification record; empty while unverified
+	ValidatorID string // validator that recorded Verdict
+	Confidence  float64
+	Reason      string
+	VerifiedAt  time.Time
+	Records     int // verification records found for the report
+}
+
+// Final reports whether a validator has recorded a verdict, after which the report's status no
+// longer changes
+func (s *ReportStatus) Final() bool {
+	return s.Verdict != ""
+}
+
+// reportStatusFromRecords folds a report's verification records into its status, taking the
+// verdict from the latest record that has one
+func reportStatusFromRecords(reportID string, records []*pb.VerificationRecord) *ReportStatus {
+	reportStatus := &ReportStatus{ReportID: reportID, Records: len(records)}
+	var latest *pb.VerificationRecord
+	for _, record := range records {
+		if record.GetVerdict() == pb.VerificationRecord_VERDICT_UNSPECIFIED {
+			continue
+		}
+		if latest == nil || record.GetTimestamp() > latest.GetTimestamp() {
+			latest = record
+		}
+	}
+	if latest == nil {
+		return reportStatus
+	}
+
+	reportStatus.Verdict = latest.GetVerdict().String()
+	reportStatus.ValidatorID = latest.GetValidatorId()
+	reportStatus.Confidence = latest.GetConfidence()
+	reportStatus.Reason = latest.GetReason()
+	if latest.GetTimestamp() > 0 {
+		reportStatus.VerifiedAt = time.Unix(latest.GetTimestamp(), 0)
+	}
+	return reportStatus
+}
+
+// WaitForReportFinality polls the validator every pollInterval until a verdict has been recorded
+// for reportID or ctx is done. A report the validator has not stored yet and an unavailable
+// validator are polled again; other lookup errors are returned. When ctx ends first, the last
+// status seen is returned with ctx's error.
+func (sdk *SDK) WaitForReportFinality(ctx context.Context, reportID string, pollInterval time.Duration) (*ReportStatus, error) {
+	validatorClient := sdk.clients().validator
+	if validatorClient == nil {
+		return nil, errors.New("validator client not initialized")
+	}
+	if reportID == "" {
+		return nil, errors.New("report_id is required")
+	}
+	if pollInterval <= 0 {
+		return nil, errors.New("poll interval must be positive")
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var last *ReportStatus
+	for {
+		records, err := validatorClient.GetReportStatus(ctx, reportID)
+		switch {
+		case err == nil:
+			last = reportStatusFromRecords(reportID, records)
+			if last.Final() {
+				return last, nil
+			}
+		case ctx.Err() != nil:
+		case status.Code(err) == codes.NotFound, status.Code(err) == codes.Unavailable:
+			sdk.logger.Debug("Report status not available yet", "report_id", reportID, "error", err)
+		default:
+			return nil, fmt.Errorf("failed to get report status: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, fmt.Errorf("wait for report %s finality: %w", reportID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package agentsdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "subnet/proto/subnet"
+)
+
+// verificationValidatorService reports the report as not stored on the first lookup, then returns
+// a pending record and, from the third lookup on, a PASS verdict. Records for other reports of the
+// same intent are always included.
+type verificationValidatorService struct {
+	pb.ValidatorServiceClient
+	mu      sync.Mutex
+	lookups int
+	queries []*pb.GetVerificationRecordsRequest
+}
+
+func (v *verificationValidatorService) GetExecutionReport(ctx context.Context, in *pb.GetExecutionReportRequest, opts ...grpc.CallOption) (*pb.ExecutionReport, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.lookups++
+	if v.lookups == 1 {
+		return nil, status.Error(codes.NotFound, "report not found")
+	}
+	return &pb.ExecutionReport{ReportId: in.ReportId, IntentId: "intent-1", AgentId: "agent-1"}, nil
+}
+
+func (v *verificationValidatorService) GetVerificationRecords(ctx context.Context, in *pb.GetVerificationRecordsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[pb.VerificationRecord], error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.queries = append(v.queries, in)
+	records := []*pb.VerificationRecord{
+		{ReportId: "report-other", ValidatorId: "v1", Verdict: pb.VerificationRecord_FAIL, Timestamp: 300},
+		{ReportId: "report-1", ValidatorId: "v1", Timestamp: 100},
+	}
+	if v.lookups >= 3 {
+		records = append(records, &pb.VerificationRecord{
+			ReportId: "report-1", ValidatorId: "v2", Verdict: pb.VerificationRecord_PASS, Confidence: 0.9, Timestamp: 200,
+		})
+	}
+	return &verificationRecordStream{records: records}, nil
+}
+
+type verificationRecordStream struct {
+	grpc.ClientStream
+	records []*pb.VerificationRecord
+}
+
+func (s *verificationRecordStream) Recv() (*pb.VerificationRecord, error) {
+	if len(s.records) == 0 {
+		return nil, io.EOF
+	}
+	record := s.records[0]
+	s.records = s.records[1:]
+	return record, nil
+}
+
+func TestGetReportStatusFiltersRecordsByReport(t *testing.T) {
+	validator := &verificationValidatorService{lookups: 1}
+	client := &ValidatorClient{client: validator}
+
+	records, err := client.GetReportStatus(context.Background(), "report-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 || records[0].ValidatorId != "v1" {
+		t.Fatalf("expected only report-1's record, got %v", records)
+	}
+	if q := validator.queries[0]; q.IntentId != "intent-1" || q.AgentId != "agent-1" {
+		t.Fatalf("expected records queried by the report's intent and agent, got %v", q)
+	}
+	if reportStatusFromRecords("report-1", records).Final() {
+		t.Fatalf("a record without a verdict should not be final")
+	}
+}
+
+func TestWaitForReportFinalityPollsUntilVerdict(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	validator := &verificationValidatorService{}
+	sdk.validatorClient = &ValidatorClient{client: validator}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	reportStatus, err := sdk.WaitForReportFinality(ctx, "report-1", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reportStatus.Final() || reportStatus.Verdict != ReportVerdictPass || reportStatus.ValidatorID != "v2" || reportStatus.Records != 2 {
+		t.Fatalf("expected v2's PASS verdict, got %+v", reportStatus)
+	}
+	if validator.lookups != 3 {
+		t.Fatalf("expected 3 lookups, got %d", validator.lookups)
+	}
+}
+
+func TestWaitForReportFinalityReturnsLastStatusWhenContextEnds(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	// Starting far below the lookup thresholds keeps the report stored but never verified
+	validator := &verificationValidatorService{lookups: -100}
+	sdk.validatorClient = &ValidatorClient{client: validator}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	reportStatus, err := sdk.WaitForReportFinality(ctx, "report-1", 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if reportStatus == nil || reportStatus.Final() || reportStatus.Records != 1 {
+		t.Fatalf("expected the pending status, got %+v", reportStatus)
+	}
+}
@@ -0,0 +1,73 @@
+package agentsdk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"slices"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Result data larger than ResultCompressionThreshold is sent gzip-compressed. HTTP reports flag it
+// in their metadata; gRPC reports in the call metadata, one value per report in a batch. Signatures
+// and result hashes cover the result data as transmitted, i.e. the compressed bytes, so validators
+// verify what they received before decompressing it.
+const (
+	ResultEncodingGzip     = "gzip"
+	ResultEncodingIdentity = "identity"
+	ResultEncodingMetadata = "result-encoding" // gRPC metadata
+
+	resultEncodingMetadataKey = "result_encoding" // HTTP report metadata
+)
+
+// shouldCompressResult reports whether result data is large enough to be sent compressed
+func (c *Config) shouldCompressResult(data []byte) bool {
+	return c.ResultCompressionThreshold > 0 && len(data) > c.ResultCompressionThreshold
+}
+
+// resultEncoding returns the encoding result data is sent with
+func (c *Config) resultEncoding(data []byte) string {
+	if c.shouldCompressResult(data) {
+		return ResultEncodingGzip
+	}
+	return ResultEncodingIdentity
+}
+
+// encodeResultData returns result data as it is transmitted, gzip-compressed when it exceeds the
+// threshold. Hashing and signing use its output.
+func (c *Config) encodeResultData(data []byte) ([]byte, error) {
+	if !c.shouldCompressResult(data) {
+		return data, nil
+	}
+	return gzipResultData(data)
+}
+
+// gzipResultData compresses result data for submission. The gzip header carries no timestamp, so
+// the same data always compresses to the same bytes.
+func gzipResultData(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("compress result data: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("compress result data: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// withResultEncodings flags compressed reports in the outgoing gRPC metadata, one encoding per
+// report in request order. Nothing is added when no report is compressed, so validators that do
+// not know the flag keep working for small results.
+func withResultEncodings(ctx context.Context, encodings ...string) context.Context {
+	if !slices.ContainsFunc(encodings, func(encoding string) bool { return encoding != ResultEncodingIdentity }) {
+		return ctx
+	}
+	pairs := make([]string, 0, 2*len(encodings))
+	for _, encoding := range encodings {
+		pairs = append(pairs, ResultEncodingMetadata, encoding)
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
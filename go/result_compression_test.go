@@ -0,0 +1,135 @@
+package agentsdk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pb "subnet/proto/subnet"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func gunzip(t *testing.T, data []byte) []byte {
+	t.Helper()
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("open gzip stream: %v", err)
+	}
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	return decompressed
+}
+
+func TestSubmitExecutionReportCompressesLargeResults(t *testing.T) {
+	var received []executionReportRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload executionReportRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode report: %v", err)
+		}
+		received = append(received, payload)
+		w.Write([]byte(`{"report_id":"report-1","status":"accepted"}`))
+	}))
+	defer server.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ValidatorAddr = server.Listener.Addr().String()
+		cfg.ResultCompressionThreshold = 64
+		cfg.ResultHashAlgorithm = ResultHashSHA256
+	})
+
+	large := bytes.Repeat([]byte("result "), 100)
+	small := []byte("done")
+	for _, data := range [][]byte{large, small} {
+		report := &ExecutionReport{ReportID: "report-1", AssignmentID: "task-1", IntentID: "intent-1", ResultData: data}
+		if _, err := sdk.SubmitExecutionReport(context.Background(), report); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(received[0].ResultData)
+	if err != nil {
+		t.Fatalf("decode result data: %v", err)
+	}
+	if received[0].Metadata[resultEncodingMetadataKey] != ResultEncodingGzip || len(compressed) >= len(large) {
+		t.Fatalf("expected a gzip-flagged, smaller payload, got %d bytes with metadata %v", len(compressed), received[0].Metadata)
+	}
+	if !bytes.Equal(gunzip(t, compressed), large) {
+		t.Fatal("expected the result data to survive the round trip")
+	}
+	if digest := sha256.Sum256(compressed); received[0].Metadata[resultHashMetadataKey] != hex.EncodeToString(digest[:]) {
+		t.Fatalf("expected the result hash to cover the transmitted bytes, got %s", received[0].Metadata[resultHashMetadataKey])
+	}
+
+	if _, ok := received[1].Metadata[resultEncodingMetadataKey]; ok || received[1].ResultData != base64.StdEncoding.EncodeToString(small) {
+		t.Fatalf("expected a result under the threshold to be sent as is, got %+v", received[1])
+	}
+}
+
+// encodingRecordingValidatorService records result data and encodings of submitted reports
+type encodingRecordingValidatorService struct {
+	pb.ValidatorServiceClient
+	report    *pb.ExecutionReport
+	data      []byte
+	encodings []string
+}
+
+func (v *encodingRecordingValidatorService) SubmitExecutionReport(ctx context.Context, in *pb.ExecutionReport, opts ...grpc.CallOption) (*pb.Receipt, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	v.report, v.data, v.encodings = in, in.ResultData, md.Get(ResultEncodingMetadata)
+	return &pb.Receipt{ReportId: in.ReportId, Status: "accepted"}, nil
+}
+
+func TestSubmitTaskReportCompressesLargeResults(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ResultCompressionThreshold = 64
+		cfg.ResultHashAlgorithm = ResultHashSHA256
+		cfg.PrivateKey = testPrivateKey
+	})
+	validator := &encodingRecordingValidatorService{}
+	sdk.validators = []validatorTarget{{addr: "validator-1:9090", client: &ValidatorClient{client: validator}}}
+	sdk.validatorClient = sdk.validators[0].client
+
+	large := bytes.Repeat([]byte("result "), 100)
+	task := &Task{ID: "task-1", IntentID: "intent-1"}
+	if _, err := sdk.submitTaskReport(context.Background(), "report-1", task, &Result{Success: true, Data: large}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(validator.encodings) != 1 || validator.encodings[0] != ResultEncodingGzip {
+		t.Fatalf("expected the report flagged as gzip, got %v", validator.encodings)
+	}
+	if !bytes.Equal(gunzip(t, validator.data), large) {
+		t.Fatal("expected the result data to survive the round trip")
+	}
+	if digest := sha256.Sum256(validator.data); !bytes.Equal(validator.report.Evidence.OutputsHash, digest[:]) {
+		t.Fatal("expected the outputs hash to cover the transmitted bytes")
+	}
+	report := validator.report
+	payload, err := reportSigningPayload(report.ReportId, report.AssignmentId, report.IntentId, report.AgentId,
+		convertProtoStatusToSDK(report.Status), validator.data, report.Timestamp)
+	if err != nil {
+		t.Fatalf("build signing payload: %v", err)
+	}
+	if signer, err := RecoverAddressWithScheme(sdk.config.SignatureScheme, payload, report.Signature); err != nil || signer != sdk.GetAddress() {
+		t.Fatalf("expected the signature over the transmitted bytes to recover the agent, got %s %v", signer, err)
+	}
+
+	if _, err := sdk.submitTaskReport(context.Background(), "report-2", task, &Result{Success: true, Data: []byte("done")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(validator.encodings) != 0 || string(validator.data) != "done" {
+		t.Fatalf("expected a small result sent as is without an encoding flag, got %q %v", validator.data, validator.encodings)
+	}
+}
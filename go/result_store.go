@@ -0,0 +1,120 @@
+package agentsdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	resultStoreDirName         = "results"
+	defaultTaskResultRetention = 24 * time.Hour
+)
+
+// persistedResult is the on-disk record of a task result awaiting validator acknowledgement
+type persistedResult struct {
+	ReportID    string    `json:"report_id"`
+	Task        *Task     `json:"task"`
+	Result      *Result   `json:"result"`
+	PersistedAt time.Time `json:"persisted_at"`
+}
+
+// resultStore keeps unacknowledged task results in DataDir so they survive restarts
+type resultStore struct {
+	dir       string
+	retention time.Duration
+}
+
+// newResultStore creates the result directory under dataDir
+func newResultStore(dataDir string, retention time.Duration) (*resultStore, error) {
+	dir := filepath.Join(dataDir, resultStoreDirName)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create result store: %w", err)
+	}
+	if retention <= 0 {
+		retention = defaultTaskResultRetention
+	}
+	return &resultStore{dir: dir, retention: retention}, nil
+}
+
+// Save writes the result atomically so a crash never leaves a truncated record behind
+func (s *resultStore) Save(reportID string, task *Task, result *Result) error {
+	record := persistedResult{
+		ReportID:    reportID,
+		Task:        task,
+		Result:      result,
+		PersistedAt: time.Now().UTC(),
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal result: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, reportID+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write result: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close result: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), s.path(reportID))
+}
+
+// Delete removes an acknowledged result
+func (s *resultStore) Delete(reportID string) error {
+	err := os.Remove(s.path(reportID))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Load returns all results still within the retention window, pruning expired and unreadable records
+func (s *resultStore) Load() ([]*persistedResult, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read result store: %w", err)
+	}
+
+	cutoff := time.Now().Add(-s.retention)
+	var records []*persistedResult
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(s.dir, entry.Name())
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", entry.Name(), err)
+		}
+
+		var record persistedResult
+		if err := json.Unmarshal(data, &record); err != nil || record.Task == nil || record.Result == nil {
+			os.Remove(path)
+			continue
+		}
+		if record.PersistedAt.Before(cutoff) {
+			os.Remove(path)
+			continue
+		}
+		records = append(records, &record)
+	}
+
+	return records, nil
+}
+
+func (s *resultStore) path(reportID string) string {
+	return filepath.Join(s.dir, reportID+".json")
+}
@@ -0,0 +1,55 @@
+package agentsdk
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestResultStoreRoundTrip(t *testing.T) {
+	store, err := newResultStore(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error creating store: %v", err)
+	}
+
+	task := &Task{ID: "task-1", IntentID: "intent-1"}
+	result := &Result{Data: []byte("done"), Success: true}
+	if err := store.Save("report-1", task, result); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+
+	records, err := store.Load()
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	if len(records) != 1 || records[0].ReportID != "report-1" || string(records[0].Result.Data) != "done" {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+
+	if err := store.Delete("report-1"); err != nil {
+		t.Fatalf("unexpected delete error: %v", err)
+	}
+	if records, _ := store.Load(); len(records) != 0 {
+		t.Fatalf("expected no records after delete, got %d", len(records))
+	}
+}
+
+func TestResultStorePrunesExpiredRecords(t *testing.T) {
+	store, err := newResultStore(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error creating store: %v", err)
+	}
+	if err := store.Save("report-1", &Task{ID: "task-1"}, &Result{Success: true}); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+
+	store.retention = time.Nanosecond
+	time.Sleep(time.Millisecond)
+
+	if records, _ := store.Load(); len(records) != 0 {
+		t.Fatalf("expected expired record to be skipped, got %d", len(records))
+	}
+	if _, err := os.Stat(store.path("report-1")); !os.IsNotExist(err) {
+		t.Fatalf("expected expired record to be removed from disk")
+	}
+}
@@ -4,22 +4,31 @@ import (
 	"bytes"
 	"context"
 	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"math/rand/v2"
+	"net"
 	"net/http"
 	"net/url"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
 	pb "subnet/proto/subnet"
 )
 
@@ -27,53 +36,210 @@ import (
 type SDK struct {
 	config          *Config
 	handler         Handler
+	typeHandlers    map[string]Handler // by task type; see RegisterHandlerForType
+	middleware      []HandlerMiddleware
 	biddingStrategy BiddingStrategy
 	callbacks       Callbacks
+	callbackExts    callbackExtensions
+	handlersMu      sync.RWMutex // guards the registrations above; separate from mu, which Start and Stop hold while firing callbacks
 	privateKey      *ecdsa.PrivateKey
 	address         string
+	configStrategy  BiddingStrategy // built-in strategy named by config.BiddingStrategy, used while none is registered
 	metrics         *Metrics
+	logger          Logger
+	tracer          Tracer
+	sampleFloat     func() float64 // uniform [0, 1) source for validator weighting
+	taskDedup       *taskDeduper
+	openBids        *openBidTracker
+	bidLimiter      *bidLimiter
 	mu              sync.RWMutex
-	running         bool
+	running         atomic.Bool // written under mu by Start and Stop; read without it by the task paths
 	httpClient      *http.Client
+	tlsConfig       *tls.Config // client TLS for gRPC; nil unless UseTLS
 	registryCancel  context.CancelFunc
 	registryWG      sync.WaitGroup
+	stopOnCancel    func() bool // detaches the StartContext parent context from Stop
 	matcherClient   *MatcherClient
-	validatorClient *ValidatorClient
+	validatorClient *ValidatorClient // primary validator, used for queries
+	validators      []validatorTarget
 	matcherCancel   context.CancelFunc
 	matcherWG       sync.WaitGroup
+	taskCancel      context.CancelFunc
+	taskWG          sync.WaitGroup
+	inFlightTasks   atomic.Int64
+	resultStore     *resultStore
+	pendingReports  *pendingReportTracker
+	taskQueue       *taskQueue
+	streamSlots     chan struct{} // streamed tasks being handled, bounded to throttle the task stream
+	typeSlots       map[string]chan struct{}
+	reportQueue     chan reportJob
+	reportDrain     chan struct{} // closed by Stop to flush partial report batches
+	bidQueue        chan pendingBid
+	reportWG        sync.WaitGroup
+	validatorCache  validatorCache
+	healthServer    *http.Server
+	healthAddr      string       // address the health server listens on
+	lastHeartbeat   atomic.Int64 // unix nanos of the last successful registry registration or heartbeat
+	startedAt       atomic.Int64 // unix nanos of the last successful Start; zero while stopped
+	registration    atomic.Pointer[RegistrationInfo]
+
+	// Matcher stream connection state, reported by ConnectionState
+	taskStream   streamState
+	intentStream streamState
+
+	unsignedReportWarning sync.Once
 }
 
-const defaultReportTimeout = 10 * time.Second
+// defaultHTTPTimeout bounds each registry and validator HTTP call unless HTTPTimeout is set
+const defaultHTTPTimeout = 10 * time.Second
+
+// defaultReportFanOutConcurrency bounds concurrent posts when SubmitExecutionReport fans out to validators
+const defaultReportFanOutConcurrency = 8
 const chainAddressMetadataKey = "chain_address"
 
+// Metadata keys carrying the SDK-computed hash of the submitted result data
+const (
+	resultHashMetadataKey          = "result_hash"
+	resultHashAlgorithmMetadataKey = "result_hash_algorithm"
+)
+
+// Supported result hash algorithms
+const (
+	ResultHashKeccak256 = "keccak256"
+	ResultHashSHA256    = "sha256"
+)
+
+// defaultHeartbeatFailureThreshold is how many consecutive heartbeat failures trigger re-registration
+const defaultHeartbeatFailureThreshold = 3
+
+// Backoff between registration attempts after the registry answers a heartbeat with a
+// re-register status, so a registry that is still starting up is not hammered
+const (
+	registryReregisterInitialBackoff = time.Second
+	registryReregisterMaxBackoff     = 30 * time.Second
+)
+
+// defaultMatcherSubscriptionAttempts bounds consecutive rejected matcher stream subscriptions
+const defaultMatcherSubscriptionAttempts = 3
+
+// validatorStatusActive is the registry status of validators accepting execution reports
+const validatorStatusActive = "active"
+
+// defaultActiveValidatorMaxAge is how recently a registry validator must have been seen to receive
+// execution reports
+const defaultActiveValidatorMaxAge = 2 * time.Minute
+
+// defaultReportMaxPayloadSize matches gRPC's default max receive message size
+const defaultReportMaxPayloadSize = 4 << 20
+
+// defaultReportPath is where validators accept execution reports over HTTP
+const defaultReportPath = "/api/v1/execution-report"
+
+// defaultReportScheme is used for validator endpoints discovered without a scheme
+const defaultReportScheme = "http"
+
 // Config holds SDK configuration
 type Config struct {
-	Identity                  *IdentityConfig
-	AgentID                   string
-	PrivateKey                string
-	ChainAddress              string
-	MatcherAddr               string
-	ValidatorAddr             string
-	Capabilities              []string
-	MaxConcurrentTasks        int
-	TaskTimeout               time.Duration
-	BidTimeout                time.Duration
-	BiddingStrategy           string
-	MinBidPrice               uint64
-	MaxBidPrice               uint64
-	Owner                     string
-	StakeAmount               uint64
-	UseTLS                    bool
-	CertFile                  string
-	KeyFile                   string
-	LogLevel                  string
-	DataDir                   string
-	Timeouts                  *TimeoutConfig
-	RegistryAddr              string
-	AgentEndpoint             string
-	RegistryHeartbeatInterval time.Duration
+	Identity                    *IdentityConfig
+	AgentID                     string
+	PrivateKey                  string
+	ChainAddress                string
+	MatcherAddr                 string
+	ValidatorAddr               string
+	ValidatorAddrs              []string
+	Capabilities                []string
+	MaxConcurrentTasks          int
+	TaskQueueDepth              int
+	TaskTimeout                 time.Duration
+	BidTimeout                  time.Duration
+	BiddingStrategy             string
+	MinBidPrice                 uint64
+	MaxBidPrice                 uint64
+	Owner                       string
+	StakeAmount                 uint64
+	UseTLS                      bool
+	CertFile                    string
+	KeyFile                     string
+	CAFile                      string
+	InsecureSkipVerify          bool
+	SignAgentInfo               bool
+	SignatureScheme             string
+	EVMRecoveryID               bool
+	LogLevel                    string
+	DataDir                     string
+	Timeouts                    *TimeoutConfig
+	RegistryAddr                string
+	RegistryToken               string
+	RegistryTokenProvider       RegistryTokenProvider
+	StrictRegistryAuth          bool
+	RegistryOptional            bool
+	AgentEndpoint               string
+	RegistryHeartbeatInterval   time.Duration
+	ReportMaxPayloadSize        int
+	ReportPath                  string
+	ReportScheme                string
+	ValidatorEndpointResolver   ValidatorEndpointResolver
+	ReportCompletionCallback    ReportCompletionCallback
+	OutgoingMetadata            map[string]string
+	OutgoingMetadataProvider    func() metadata.MD
+	ResultHashAlgorithm         string
+	ResultCompressionThreshold  int // gzip result data larger than this many bytes; 0 disables
+	StreamReceiveTimeout        time.Duration
+	TaskStreamBuffer            int
+	PersistTaskResults          bool
+	TaskResultRetention         time.Duration
+	RandSource                  io.Reader
+	BidResponseTimeout          time.Duration
+	ReconnectInitialBackoff     time.Duration
+	ReconnectMaxBackoff         time.Duration
+	ShutdownTimeout             time.Duration
+	ReportOverflowPolicy        string
+	ReportMaxRetries            int
+	ReportRetryBackoff          time.Duration
+	ReportRetryJitter           float64 // fraction retry delays are spread by; negative disables
+	Logger                      Logger
+	ValidatorSetCacheTTL        time.Duration
+	MaxConcurrentTasksPerType   map[string]int
+	ReportFinalizer             ReportFinalizer
+	ReportFinalizeThreshold     int
+	MatcherSubscriptionAttempts int
+	ReportMetadataAllowlist     []string
+	ReportMetadataDenylist      []string
+	Tracer                      Tracer
+	ValidatorWeights            map[string]float64
+	TaskDedupWindow             time.Duration
+	ValidatorCacheTTL           time.Duration
+	HealthAddr                  string
+	HeartbeatFailureThreshold   int
+	HeartbeatMetadata           map[string]string
+	HeartbeatReregisterStatuses []int
+	BidBatchSize                int
+	BidBatchFlushInterval       time.Duration
+	BidDryRun                   bool
+	BidRatePerSecond            int
+	BidRateBurst                int
+	ReportBatchSize             int
+	ReportBatchFlushInterval    time.Duration
+	HTTPClient                  *http.Client
+	HTTPTimeout                 time.Duration
 }
 
+// RegistryTokenProvider returns the bearer token for a registry request, e.g. to rotate
+// short-lived tokens. It is called for every request.
+type RegistryTokenProvider func(ctx context.Context) (string, error)
+
+// ReportCompletionCallback is invoked once the execution report for a streamed task has been submitted.
+// receipts holds one entry per validator that accepted the report, so len(receipts) is the accepted count.
+type ReportCompletionCallback func(task *Task, receipts []*ExecutionReceipt, err error)
+
+// ReportFinalizer is invoked once per streamed task when its execution report has been accepted by
+// ReportFinalizeThreshold validators, i.e. when the result is durable and can be settled locally.
+type ReportFinalizer func(task *Task, receipts []*ExecutionReceipt)
+
+// ValidatorEndpointResolver returns validator endpoints from a custom discovery source.
+// When configured it replaces registry discovery for execution report fan-out.
+type ValidatorEndpointResolver func(ctx context.Context) ([]ValidatorEndpoint, error)
+
 // ValidatorEndpoint contains validator discovery information
 type ValidatorEndpoint struct {
 	ID       string
@@ -82,6 +248,16 @@ type ValidatorEndpoint struct {
 	LastSeen time.Time
 }
 
+// RegistrationInfo is what the registry reported when it last accepted the agent's registration.
+// Fields the registry did not return are left empty.
+type RegistrationInfo struct {
+	AgentID      string    // ID the registry assigned; used for heartbeats and unregistering
+	Capabilities []string  // capabilities the registry granted
+	Permissions  []string  // permissions the registry granted
+	ExpiresAt    time.Time // when the registration lapses without heartbeats
+	RegisteredAt time.Time // when the SDK registered
+}
+
 // IdentityConfig holds identity information
 type IdentityConfig struct {
 	SubnetID    string
@@ -129,114 +305,309 @@ func New(config *Config) (*SDK, error) {
 		config.ChainAddress = address
 	}
 
+	var store *resultStore
+	if config.PersistTaskResults {
+		s, err := newResultStore(config.DataDir, config.TaskResultRetention)
+		if err != nil {
+			return nil, err
+		}
+		store = s
+	}
+
+	typeSlots := make(map[string]chan struct{}, len(config.MaxConcurrentTasksPerType))
+	for taskType, limit := range config.MaxConcurrentTasksPerType {
+		typeSlots[taskType] = make(chan struct{}, limit)
+	}
+
+	var tracer Tracer = noopTracer{}
+	if config.Tracer != nil {
+		tracer = config.Tracer
+	}
+
+	tlsConfig, err := newClientTLSConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS config: %w", err)
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: config.HTTPTimeout, Transport: newHTTPTransport(tlsConfig)}
+	}
+	if !config.UseTLS {
+		tlsConfig = nil
+	}
+
+	metrics := NewMetrics()
 	return &SDK{
-		config:     config,
-		privateKey: privateKey,
-		address:    address,
-		metrics:    NewMetrics(),
-		running:    false,
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		config:         config,
+		privateKey:     privateKey,
+		address:        address,
+		configStrategy: newBuiltinBiddingStrategy(config),
+		metrics:        metrics,
+		logger:         newLevelLogger(config.Logger, config.LogLevel),
+		tracer:         tracer,
+		sampleFloat:    rand.Float64,
+		taskDedup:      newTaskDeduper(config.TaskDedupWindow, defaultTaskDedupCapacity),
+		openBids:       newOpenBidTracker(),
+		bidLimiter:     newBidLimiter(config.BidRatePerSecond, config.BidRateBurst),
+		httpClient:     httpClient,
+		tlsConfig:      tlsConfig,
+		resultStore:    store,
+		pendingReports: newPendingReportTracker(),
+		taskQueue:      newTaskQueue(config.MaxConcurrentTasks, config.TaskQueueDepth, metrics),
+		streamSlots:    make(chan struct{}, config.MaxConcurrentTasks+config.TaskQueueDepth),
+		typeSlots:      typeSlots,
+		reportQueue:    make(chan reportJob, defaultReportQueueSize),
+		bidQueue:       make(chan pendingBid, config.BidBatchSize),
 	}, nil
 }
 
 // RegisterHandler sets the task handler
 func (sdk *SDK) RegisterHandler(handler Handler) {
-	sdk.mu.Lock()
-	defer sdk.mu.Unlock()
+	sdk.handlersMu.Lock()
+	defer sdk.handlersMu.Unlock()
 	sdk.handler = handler
 }
 
+// RegisterHandlerFunc sets fn as the task handler, like RegisterHandler(HandlerFunc(fn))
+func (sdk *SDK) RegisterHandlerFunc(fn func(ctx context.Context, task *Task) (*Result, error)) {
+	sdk.RegisterHandler(HandlerFunc(fn))
+}
+
 // RegisterBiddingStrategy sets the bidding strategy
 func (sdk *SDK) RegisterBiddingStrategy(strategy BiddingStrategy) {
-	sdk.mu.Lock()
-	defer sdk.mu.Unlock()
+	sdk.handlersMu.Lock()
+	defer sdk.handlersMu.Unlock()
 	sdk.biddingStrategy = strategy
 }
 
+// currentBiddingStrategy returns the registered bidding strategy, falling back to the built-in
+// strategy named in the config, or nil
+func (sdk *SDK) currentBiddingStrategy() BiddingStrategy {
+	sdk.handlersMu.RLock()
+	defer sdk.handlersMu.RUnlock()
+	if sdk.biddingStrategy != nil {
+		return sdk.biddingStrategy
+	}
+	return sdk.configStrategy
+}
+
 // RegisterCallbacks sets lifecycle callbacks
 func (sdk *SDK) RegisterCallbacks(callbacks Callbacks) {
-	sdk.mu.Lock()
-	defer sdk.mu.Unlock()
+	sdk.handlersMu.Lock()
+	defer sdk.handlersMu.Unlock()
 	sdk.callbacks = callbacks
+	sdk.callbackExts = resolveCallbackExtensions(callbacks)
+}
+
+// registeredCallbacks returns the registered callbacks and their optional extensions
+func (sdk *SDK) registeredCallbacks() (Callbacks, callbackExtensions) {
+	sdk.handlersMu.RLock()
+	defer sdk.handlersMu.RUnlock()
+	return sdk.callbacks, sdk.callbackExts
 }
 
-// Start starts the SDK
+// callbackExtensions holds the optional callback interfaces implemented by the registered Callbacks
+type callbackExtensions struct {
+	bidAck     BidAckCallbacks
+	heartbeat  HeartbeatCallbacks
+	ready      ReadyCallbacks
+	reportDrop ReportDropCallbacks
+	reconnect  ReconnectCallbacks
+	reportAck  ReportAckCallbacks
+}
+
+// resolveCallbackExtensions detects which optional callback interfaces callbacks implements
+func resolveCallbackExtensions(callbacks Callbacks) callbackExtensions {
+	var exts callbackExtensions
+	exts.bidAck, _ = callbacks.(BidAckCallbacks)
+	exts.heartbeat, _ = callbacks.(HeartbeatCallbacks)
+	exts.ready, _ = callbacks.(ReadyCallbacks)
+	exts.reportDrop, _ = callbacks.(ReportDropCallbacks)
+	exts.reconnect, _ = callbacks.(ReconnectCallbacks)
+	exts.reportAck, _ = callbacks.(ReportAckCallbacks)
+	return exts
+}
+
+// Start starts the SDK. It is StartContext with context.Background().
 func (sdk *SDK) Start() error {
-	log.Printf("[SDK DEBUG] Start() called")
+	return sdk.StartContext(context.Background())
+}
+
+// StartContext starts the SDK with its registry and matcher loops bound to ctx. Cancelling ctx
+// stops the SDK as Stop does, draining in-flight tasks; Stop may still be called directly.
+func (sdk *SDK) StartContext(ctx context.Context) error {
+	sdk.logger.Debug("Starting SDK")
 	sdk.mu.Lock()
 	defer sdk.mu.Unlock()
 
-	log.Printf("[SDK DEBUG] Acquired lock")
-
-	if sdk.running {
+	if sdk.running.Load() {
 		return errors.New("SDK already running")
 	}
 
-	if sdk.handler == nil {
-		return errors.New("no handler registered")
+	if !sdk.hasHandler() {
+		return ErrNoHandler
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if sdk.tlsConfig != nil && sdk.tlsConfig.InsecureSkipVerify {
+		sdk.logger.Warn("TLS certificate verification is DISABLED (InsecureSkipVerify); connections can be intercepted. Use for local testing only")
+	}
+
+	// Serve liveness probes while the rest of the SDK starts
+	if err := sdk.startHealthServer(); err != nil {
+		return err
 	}
 
-	log.Printf("[SDK DEBUG] Calling registerWithRegistry()...")
-	if err := sdk.registerWithRegistry(); err != nil {
+	if err := sdk.registerWithRegistry(ctx); err != nil {
+		sdk.stopHealthServer()
 		return fmt.Errorf("registry registration failed: %w", err)
 	}
-	log.Printf("[SDK DEBUG] registerWithRegistry() completed")
+	sdk.logger.Debug("Registered with registry")
 
 	// Initialize gRPC clients
-	log.Printf("[SDK DEBUG] Calling initGRPCClients()...")
 	if err := sdk.initGRPCClients(); err != nil {
+		sdk.stopHealthServer()
 		return fmt.Errorf("failed to initialize gRPC clients: %w", err)
 	}
-	log.Printf("[SDK DEBUG] initGRPCClients() completed")
+	sdk.logger.Debug("gRPC clients initialized")
 
 	// Start matcher streams
-	log.Printf("[SDK DEBUG] Calling startMatcherStreams()...")
-	if err := sdk.startMatcherStreams(); err != nil {
+	if err := sdk.startMatcherStreams(ctx); err != nil {
 		sdk.closeGRPCClients()
+		sdk.stopHealthServer()
 		return fmt.Errorf("failed to start matcher streams: %w", err)
 	}
-	log.Printf("[SDK DEBUG] startMatcherStreams() completed")
+	sdk.logger.Debug("Matcher streams started")
 
-	log.Printf("[SDK DEBUG] Setting sdk.running = true")
-	sdk.running = true
-	log.Printf("[SDK DEBUG] sdk.running set to true")
-
-	log.Printf("[SDK DEBUG] Calling fireCallback(OnStart)...")
+	sdk.running.Store(true)
+	sdk.startedAt.Store(time.Now().UnixNano())
+	sdk.stopOnCancel = context.AfterFunc(ctx, sdk.stopOnContextDone)
 	sdk.fireCallback("OnStart")
-	log.Printf("[SDK DEBUG] fireCallback(OnStart) completed")
 
-	// Get agent ID before logging (GetAgentID() acquires a read lock, which would deadlock)
-	var agentID string
-	if sdk.config.Identity != nil {
-		agentID = sdk.config.Identity.AgentID
-	} else {
-		agentID = sdk.config.AgentID
+	summary := sdk.startupSummary()
+	sdk.logger.Info("SDK started",
+		"agent_id", summary.AgentID,
+		"subnet_id", summary.SubnetID,
+		"signing_address", summary.SigningAddress,
+		"matcher", summary.MatcherAddr,
+		"validators", strings.Join(summary.ValidatorAddrs, ","),
+		"validator_discovery", summary.ValidatorDiscovery,
+		"registry", summary.RegistryAddr,
+		"capabilities", strings.Join(summary.Capabilities, ","),
+		"max_concurrent_tasks", summary.MaxConcurrentTasks,
+		"tls", summary.TLS,
+		"report_overflow", summary.ReportOverflow,
+		"report_max_retries", summary.ReportMaxRetries,
+		"tracing", summary.Tracing,
+	)
+	sdk.fireCallback("OnReady", summary)
+	return nil
+}
+
+// startupSummary collects the effective configuration; the caller holds sdk.mu, so it reads
+// config fields directly instead of going through the locking getters
+func (sdk *SDK) startupSummary() StartupSummary {
+	summary := StartupSummary{
+		AgentID:            sdk.config.AgentID,
+		SigningAddress:     sdk.address,
+		MatcherAddr:        sdk.config.MatcherAddr,
+		ValidatorAddrs:     sdk.config.validatorAddrs(),
+		ValidatorDiscovery: "static",
+		RegistryAddr:       sdk.config.RegistryAddr,
+		AgentEndpoint:      sdk.config.AgentEndpoint,
+		Capabilities:       append([]string(nil), sdk.config.Capabilities...),
+		MaxConcurrentTasks: sdk.config.MaxConcurrentTasks,
+		TLS:                sdk.config.UseTLS,
+		ReportOverflow:     sdk.config.ReportOverflowPolicy,
+		ReportMaxRetries:   sdk.config.ReportMaxRetries,
+		ResultHash:         sdk.config.ResultHashAlgorithm,
+		PersistResults:     sdk.config.PersistTaskResults,
+		StartedAt:          sdk.StartedAt(),
 	}
+	if sdk.privateKey == nil {
+		summary.SigningAddress = ""
+	}
+	if identity := sdk.config.Identity; identity != nil {
+		summary.AgentID = identity.AgentID
+		summary.SubnetID = identity.SubnetID
+	}
+	switch {
+	case sdk.config.ValidatorEndpointResolver != nil:
+		summary.ValidatorDiscovery = "resolver"
+	case sdk.config.RegistryAddr != "":
+		summary.ValidatorDiscovery = "registry"
+	}
+	if len(sdk.config.MaxConcurrentTasksPerType) > 0 {
+		summary.TaskTypeLimits = make(map[string]int, len(sdk.config.MaxConcurrentTasksPerType))
+		for taskType, limit := range sdk.config.MaxConcurrentTasksPerType {
+			summary.TaskTypeLimits[taskType] = limit
+		}
+	}
+	_, noop := sdk.tracer.(noopTracer)
+	summary.Tracing = !noop
+	return summary
+}
 
-	log.Printf("[SDK DEBUG] About to log final message and return")
-	log.Printf("SDK started with agent ID: %s", agentID)
-	log.Printf("[SDK DEBUG] Returning nil from Start()")
-	return nil
+// stopOnContextDone stops the SDK when the StartContext context is cancelled
+func (sdk *SDK) stopOnContextDone() {
+	sdk.logger.Info("Start context cancelled, stopping SDK")
+	if err := sdk.Stop(); err != nil {
+		sdk.logger.Debug("Stop after context cancellation", "error", err)
+	}
 }
 
 // Stop stops the SDK
 func (sdk *SDK) Stop() error {
 	sdk.mu.Lock()
-	defer sdk.mu.Unlock()
-
-	if !sdk.running {
-		return errors.New("SDK not running")
+	if !sdk.running.Load() {
+		sdk.mu.Unlock()
+		return ErrNotRunning
 	}
+	sdk.running.Store(false)
+	sdk.startedAt.Store(0)
+	if sdk.stopOnCancel != nil {
+		sdk.stopOnCancel()
+		sdk.stopOnCancel = nil
+	}
+	sdk.mu.Unlock()
 
-	sdk.running = false
+	// Stop accepting tasks, then let in-flight ones finish and report. The lock is
+	// released meanwhile because task execution and reporting read SDK state.
 	sdk.stopMatcherStreams()
+	sdk.drainTasks(sdk.config.ShutdownTimeout)
+
+	sdk.mu.Lock()
+	defer sdk.mu.Unlock()
+
 	sdk.closeGRPCClients()
 	sdk.stopRegistry()
+	sdk.stopHealthServer()
 	sdk.fireCallback("OnStop")
-	log.Printf("SDK stopped")
+	sdk.logger.Info("SDK stopped")
 	return nil
 }
 
+// StartedAt returns when the SDK last started, or the zero time while it is stopped. A Start after
+// Stop resets it.
+func (sdk *SDK) StartedAt() time.Time {
+	if started := sdk.startedAt.Load(); started != 0 {
+		return time.Unix(0, started)
+	}
+	return time.Time{}
+}
+
+// Uptime returns how long the SDK has been running since its last Start, or zero while it is stopped
+func (sdk *SDK) Uptime() time.Duration {
+	if started := sdk.startedAt.Load(); started != 0 {
+		return time.Since(time.Unix(0, started))
+	}
+	return 0
+}
+
 // GetAgentID returns the agent ID
 func (sdk *SDK) GetAgentID() string {
 	sdk.mu.RLock()
@@ -251,6 +622,11 @@ func (sdk *SDK) GetAgentID() string {
 func (sdk *SDK) GetSubnetID() string {
 	sdk.mu.RLock()
 	defer sdk.mu.RUnlock()
+	return sdk.subnetID()
+}
+
+// subnetID returns the configured subnet ID; callers hold sdk.mu
+func (sdk *SDK) subnetID() string {
 	if sdk.config.Identity != nil {
 		return sdk.config.Identity.SubnetID
 	}
@@ -291,6 +667,28 @@ func (sdk *SDK) GetConfig() *Config {
 		configCopy.Timeouts = &timeoutsCopy
 	}
 	configCopy.Capabilities = append([]string{}, sdk.config.Capabilities...)
+	configCopy.ValidatorAddrs = append([]string(nil), sdk.config.ValidatorAddrs...)
+	configCopy.ReportMetadataAllowlist = append([]string(nil), sdk.config.ReportMetadataAllowlist...)
+	configCopy.ReportMetadataDenylist = append([]string(nil), sdk.config.ReportMetadataDenylist...)
+	if sdk.config.OutgoingMetadata != nil {
+		configCopy.OutgoingMetadata = cloneStringMap(sdk.config.OutgoingMetadata)
+	}
+	if sdk.config.HeartbeatMetadata != nil {
+		configCopy.HeartbeatMetadata = cloneStringMap(sdk.config.HeartbeatMetadata)
+	}
+	configCopy.HeartbeatReregisterStatuses = append([]int(nil), sdk.config.HeartbeatReregisterStatuses...)
+	if sdk.config.ValidatorWeights != nil {
+		configCopy.ValidatorWeights = make(map[string]float64, len(sdk.config.ValidatorWeights))
+		for validator, weight := range sdk.config.ValidatorWeights {
+			configCopy.ValidatorWeights[validator] = weight
+		}
+	}
+	if sdk.config.MaxConcurrentTasksPerType != nil {
+		configCopy.MaxConcurrentTasksPerType = make(map[string]int, len(sdk.config.MaxConcurrentTasksPerType))
+		for taskType, limit := range sdk.config.MaxConcurrentTasksPerType {
+			configCopy.MaxConcurrentTasksPerType[taskType] = limit
+		}
+	}
 
 	return &configCopy
 }
@@ -300,64 +698,318 @@ func (sdk *SDK) GetMetrics() *Metrics {
 	return sdk.metrics
 }
 
-// ExecuteTask executes a task using the registered handler
-func (sdk *SDK) ExecuteTask(ctx context.Context, task *Task) (*Result, error) {
-	if !sdk.running {
-		return nil, errors.New("SDK not running")
-	}
+// ResetMetrics zeroes the agent's metric counters and returns the values it cleared; see Metrics.Reset
+func (sdk *SDK) ResetMetrics() MetricsSnapshot {
+	return sdk.metrics.Reset()
+}
 
-	if sdk.handler == nil {
-		return nil, errors.New("no handler registered")
+// ExecuteTask executes a task using the handler registered for its type
+func (sdk *SDK) ExecuteTask(ctx context.Context, task *Task) (*Result, error) {
+	if !sdk.running.Load() {
+		return nil, fmt.Errorf("execute task %s: %w", task.ID, ErrNotRunning)
 	}
 
-	// Set timeout
-	timeout := sdk.config.TaskTimeout
-	if timeout == 0 {
-		timeout = 30 * time.Second
+	if sdk.handlerFor(task.Type) == nil {
+		return nil, fmt.Errorf("execute task %s: %w", task.ID, ErrNoHandler)
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
-
 	// Record metrics
 	start := time.Now()
 
-	result, err := sdk.handler.Execute(ctx, task)
+	result, err := sdk.runHandler(ctx, task)
 
 	duration := time.Since(start)
+	sdk.metrics.RecordExecTime(duration)
+	sdk.metrics.RecordTaskTypeOutcome(task.Type, err == nil, duration)
 	if err != nil {
 		sdk.metrics.RecordTaskFailure()
 	} else {
 		sdk.metrics.RecordTaskSuccess()
 	}
 
-	log.Printf("Task %s completed in %v", task.ID, duration)
+	var panicErr *HandlerPanicError
+	if errors.As(err, &panicErr) {
+		sdk.logger.Error("Task handler panicked", "task_id", task.ID, "panic", panicErr.Value, "stack", panicErr.Stack)
+		sdk.fireCallback("OnError", fmt.Errorf("task %s: %w", task.ID, err))
+	}
+
+	sdk.logger.Info("Task completed", "task_id", task.ID, "duration", duration)
 	return result, err
 }
 
-// Sign signs data with the private key
+// ExecuteAndReport runs a task received outside the matcher stream through the same pipeline as
+// streamed tasks: it executes the handler, fires the task callbacks and submits the execution report
+// to the validators, returning the result together with their receipts. Unlike streamed tasks the
+// report is submitted before returning rather than through the report queue. The call counts as
+// in-flight work that Stop drains, and is refused once Stop has begun.
+func (sdk *SDK) ExecuteAndReport(ctx context.Context, task *Task) (*Result, []*ExecutionReceipt, error) {
+	// Stop clears running under sdk.mu before draining taskWG, so a call admitted here is waited for
+	sdk.mu.RLock()
+	running := sdk.running.Load()
+	if running {
+		sdk.taskWG.Add(1)
+	}
+	sdk.mu.RUnlock()
+	if !running {
+		return nil, nil, fmt.Errorf("execute task %s: %w", task.ID, ErrNotRunning)
+	}
+	defer sdk.taskWG.Done()
+
+	if sdk.handlerFor(task.Type) == nil {
+		return nil, nil, fmt.Errorf("execute task %s: %w", task.ID, ErrNoHandler)
+	}
+	if sdk.clients().validator == nil {
+		return nil, nil, fmt.Errorf("execute task %s: %w", task.ID, ErrNoValidators)
+	}
+
+	sdk.inFlightTasks.Add(1)
+	defer sdk.inFlightTasks.Add(-1)
+
+	ctx, span := sdk.startTaskSpan(ctx, task)
+	job := reportJob{task: task, span: span}
+
+	if reason, ok := sdk.admitTask(ctx, task); !ok {
+		sdk.fireCallback("OnTaskRejected", task, reason)
+		span.SetAttributes(Attribute{Key: AttrTaskAccepted, Value: "false"})
+		span.End()
+		return nil, nil, fmt.Errorf("execute task %s: rejected: %s", task.ID, reason)
+	}
+	span.SetAttributes(Attribute{Key: AttrTaskAccepted, Value: "true"})
+
+	result, execErr := sdk.runAdmittedTask(ctx, span, task)
+
+	job.reportID = sdk.persistTaskResult(task, result, execErr)
+	job.result = result
+	span.SetAttributes(Attribute{Key: AttrReportID, Value: job.reportID})
+	receipts, err := sdk.reportTaskResult(withTraceMetadata(ctx, sdk.traceMetadata(ctx)), job.reportID, task, result)
+	job.endReportSpan(receipts, err)
+
+	return result, receipts, errors.Join(execErr, err)
+}
+
+// DryExecute runs the handler on a task without recording metrics or firing callbacks.
+// It is intended for warmups and readiness probes that exercise the handler with synthetic tasks.
+func (sdk *SDK) DryExecute(ctx context.Context, task *Task) (*Result, error) {
+	return sdk.runHandler(ctx, task)
+}
+
+// runHandler executes the handler for the task's type under the task's execution deadline. A panic
+// in the handler or its middleware is recovered into a failed result and a *HandlerPanicError.
+func (sdk *SDK) runHandler(ctx context.Context, task *Task) (result *Result, err error) {
+	handler := sdk.wrappedHandler(task.Type)
+	if handler == nil {
+		return nil, ErrNoHandler
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = panicResult(r)
+		}
+	}()
+
+	ctx, cancel := context.WithDeadline(ctx, sdk.taskExecutionDeadline(task, time.Now()))
+	defer cancel()
+
+	return handler.Execute(ctx, task)
+}
+
+// TaskTimeoutMetadataKey is the task metadata key holding a per-task timeout as a duration string
+// (e.g. "90s"). It can only shorten the configured TaskTimeout.
+const TaskTimeoutMetadataKey = "timeout"
+
+// taskExecutionDeadline returns the earliest of now+TaskTimeout, now plus the task's "timeout"
+// metadata and task.Deadline when it is in the future
+func (sdk *SDK) taskExecutionDeadline(task *Task, now time.Time) time.Time {
+	timeout := sdk.config.TaskTimeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	deadline := now.Add(timeout)
+
+	if raw, ok := task.Metadata[TaskTimeoutMetadataKey]; ok {
+		override, err := time.ParseDuration(raw)
+		switch {
+		case err != nil || override <= 0:
+			sdk.logger.Warn("Ignoring invalid task timeout metadata", "task_id", task.ID, "timeout", raw)
+		case now.Add(override).Before(deadline):
+			deadline = now.Add(override)
+		}
+	}
+
+	if task.Deadline.After(now) && task.Deadline.Before(deadline) {
+		deadline = task.Deadline
+	}
+	return deadline
+}
+
+// SubmitBid submits a bid for intentID to the matcher outside the bidding strategy, e.g. in
+// response to an external signal. The bid is sent with the agent's chain address metadata and
+// counted in metrics like strategy bids, but no bid callbacks are fired; a rejection is reported
+// through the returned receipt rather than as an error.
+func (sdk *SDK) SubmitBid(ctx context.Context, intentID string, bid *Bid) (*BidReceipt, error) {
+	if !sdk.running.Load() {
+		return nil, ErrNotRunning
+	}
+	if intentID == "" {
+		return nil, errors.New("intent_id is required")
+	}
+	if bid == nil {
+		return nil, errors.New("bid is required")
+	}
+
+	ack, err := sdk.submitBid(ctx, intentID, bid)
+	if err != nil {
+		return nil, fmt.Errorf("submit bid: %w", err)
+	}
+	sdk.trackBid(intentID, ack, bid.Price)
+
+	sdk.logger.Info("Bid submitted", "intent_id", intentID, "bid_id", ack.BidID, "accepted", ack.Accepted)
+	return &BidReceipt{
+		BidID:      ack.BidID,
+		IntentID:   intentID,
+		Accepted:   ack.Accepted,
+		Reason:     ack.Reason,
+		Status:     ack.Status,
+		RecordedAt: ack.RecordedAt,
+	}, nil
+}
+
+// Sign signs data with the private key under the configured signature scheme
 func (sdk *SDK) Sign(data []byte) ([]byte, error) {
 	if sdk.privateKey == nil {
-		return nil, errors.New("no private key configured")
+		return nil, ErrNoPrivateKey
+	}
+
+	return signMessage(sdk.privateKey, data, sdk.config.SignatureScheme, sdk.config.EVMRecoveryID)
+}
+
+// SignCanonical signs a call to method with req exactly as the gRPC clients sign their requests,
+// returning the x-signature, x-signer-id, x-timestamp, x-nonce and x-chain-id headers (plus
+// x-capabilities and x-sdk-version with WithSignedAgentInfo, capabilities comma-joined). Use it to
+// sign calls to non-gRPC services that share the verification scheme. req may be nil.
+func (sdk *SDK) SignCanonical(method string, req proto.Message) (map[string]string, error) {
+	sdk.mu.RLock()
+	config := sdk.signingConfig()
+	sdk.mu.RUnlock()
+	if config == nil {
+		return nil, ErrNoPrivateKey
+	}
+
+	md, err := config.signedMetadata(method, req)
+	if err != nil {
+		return nil, err
+	}
+	headers := make(map[string]string, len(md))
+	for key, values := range md {
+		headers[key] = strings.Join(values, ",")
+	}
+	return headers, nil
+}
+
+func (sdk *SDK) registerWithRegistry(ctx context.Context) error {
+	if sdk.config.RegistryAddr == "" {
+		return nil
+	}
+
+	err := sdk.postRegistration(ctx)
+	if err != nil && !sdk.config.RegistryOptional {
+		return err
 	}
 
-	hash := crypto.Keccak256Hash(data)
-	signature, err := crypto.Sign(hash.Bytes(), sdk.privateKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign: %w", err)
+		sdk.logger.Warn("Registry unavailable, starting without it and retrying registration in the background", "error", err)
+		sdk.startRegistryLoop(ctx, sdk.registerInBackground)
+		return nil
 	}
+	sdk.lastHeartbeat.Store(time.Now().UnixNano())
+	sdk.startRegistryLoop(ctx, sdk.heartbeatLoop)
 
-	return signature, nil
+	return nil
 }
 
-func (sdk *SDK) registerWithRegistry() error {
+// startRegistryLoop runs loop, which must call registryWG.Done, until stopRegistryLoop or ctx ends it
+func (sdk *SDK) startRegistryLoop(ctx context.Context, loop func(context.Context)) {
+	loopCtx, cancel := context.WithCancel(ctx)
+	sdk.registryCancel = cancel
+	sdk.registryWG.Add(1)
+	go loop(loopCtx)
+}
+
+// stopRegistryLoop stops the heartbeat or background registration loop and waits for it to exit
+func (sdk *SDK) stopRegistryLoop() {
+	if sdk.registryCancel != nil {
+		sdk.registryCancel()
+		sdk.registryWG.Wait()
+		sdk.registryCancel = nil
+	}
+}
+
+// Unregister removes the agent from the registry and pauses heartbeats while the SDK keeps running,
+// e.g. to drain it for maintenance: in-flight tasks finish and the matcher stream stays up, but the
+// registry stops assigning the agent work. Reregister undoes it.
+func (sdk *SDK) Unregister(ctx context.Context) error {
+	sdk.mu.Lock()
+	defer sdk.mu.Unlock()
+
+	if !sdk.running.Load() {
+		return ErrNotRunning
+	}
 	if sdk.config.RegistryAddr == "" {
+		return errors.New("registry_addr not configured")
+	}
+
+	sdk.stopRegistryLoop()
+	if sdk.registration.Load() == nil {
 		return nil
 	}
+	if err := sdk.unregisterAgent(ctx); err != nil {
+		return err
+	}
+	sdk.logger.Info("Unregistered from registry", "agent_id", sdk.registryAgentID())
+	return nil
+}
+
+// Reregister registers the agent with the registry again and resumes heartbeats, e.g. after
+// Unregister
+func (sdk *SDK) Reregister(ctx context.Context) error {
+	sdk.mu.Lock()
+	defer sdk.mu.Unlock()
+
+	if !sdk.running.Load() {
+		return ErrNotRunning
+	}
+	if sdk.config.RegistryAddr == "" {
+		return errors.New("registry_addr not configured")
+	}
+
+	sdk.stopRegistryLoop()
+	if err := sdk.postRegistration(ctx); err != nil {
+		return err
+	}
+	sdk.lastHeartbeat.Store(time.Now().UnixNano())
+	// Heartbeats outlive ctx, which only bounds the registration call; Stop ends them
+	sdk.startRegistryLoop(context.Background(), sdk.heartbeatLoop)
+	sdk.logger.Info("Re-registered with registry", "agent_id", sdk.registeredAgentID())
+	return nil
+}
+
+// registerInBackground retries registration with backoff after the registry was unavailable at
+// Start, then keeps the agent registered with heartbeats
+func (sdk *SDK) registerInBackground(ctx context.Context) {
+	if !sdk.reregister(ctx, sdk.config.RegistryHeartbeatInterval) {
+		sdk.registryWG.Done()
+		return
+	}
+	sdk.logger.Info("Registered with registry", "agent_id", sdk.registeredAgentID())
+	sdk.lastHeartbeat.Store(time.Now().UnixNano())
+	sdk.heartbeatLoop(ctx)
+}
 
+// postRegistration registers the agent, its capabilities and endpoint with the registry
+func (sdk *SDK) postRegistration(ctx context.Context) error {
 	payload := map[string]interface{}{
-		"id":           sdk.GetAgentID(),
-		"capabilities": sdk.GetCapabilities(),
+		"id":           sdk.registryAgentID(),
+		"capabilities": sdk.config.Capabilities,
 		"endpoint":     sdk.config.AgentEndpoint,
 	}
 	body, err := json.Marshal(payload)
@@ -365,11 +1017,10 @@ func (sdk *SDK) registerWithRegistry() error {
 		return fmt.Errorf("marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, sdk.registryURL("/agents"), bytes.NewReader(body))
+	req, err := sdk.newRegistryRequest(ctx, http.MethodPost, "/agents", body)
 	if err != nil {
 		return fmt.Errorf("build request: %w", err)
 	}
-	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := sdk.httpClient.Do(req)
 	if err != nil {
@@ -380,14 +1031,69 @@ func (sdk *SDK) registerWithRegistry() error {
 		return fmt.Errorf("register agent: registry returned %s", resp.Status)
 	}
 
-	hbCtx, hbCancel := context.WithCancel(context.Background())
-	sdk.registryCancel = hbCancel
-	sdk.registryWG.Add(1)
-	go sdk.heartbeatLoop(hbCtx)
-
+	info := sdk.decodeRegistration(resp.Body)
+	if len(info.Capabilities) > 0 && !slices.Equal(normalizeCapabilities(info.Capabilities), sdk.config.Capabilities) {
+		sdk.logger.Warn("Registry granted different capabilities than configured",
+			"configured", strings.Join(sdk.config.Capabilities, ","),
+			"granted", strings.Join(info.Capabilities, ","))
+	}
+	sdk.registration.Store(info)
 	return nil
 }
 
+// decodeRegistration parses the registry's registration response. Registries that return no
+// body, or one the SDK cannot parse, still count as having accepted the registration.
+func (sdk *SDK) decodeRegistration(body io.Reader) *RegistrationInfo {
+	info := &RegistrationInfo{RegisteredAt: time.Now()}
+
+	var payload struct {
+		ID           string   `json:"id"`
+		Capabilities []string `json:"capabilities"`
+		Permissions  []string `json:"permissions"`
+		ExpiresAt    int64    `json:"expires_at"`
+	}
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		if !errors.Is(err, io.EOF) {
+			sdk.logger.Warn("Ignoring unreadable registry registration response", "error", err)
+		}
+		return info
+	}
+
+	info.AgentID = payload.ID
+	info.Capabilities = payload.Capabilities
+	info.Permissions = payload.Permissions
+	if payload.ExpiresAt > 0 {
+		info.ExpiresAt = time.Unix(payload.ExpiresAt, 0)
+	}
+	return info
+}
+
+// RegistrationInfo returns what the registry reported for the latest successful registration, or
+// nil when the agent has not registered
+func (sdk *SDK) RegistrationInfo() *RegistrationInfo {
+	info := sdk.registration.Load()
+	if info == nil {
+		return nil
+	}
+	infoCopy := *info
+	infoCopy.Capabilities = append([]string(nil), info.Capabilities...)
+	infoCopy.Permissions = append([]string(nil), info.Permissions...)
+	return &infoCopy
+}
+
+// registeredAgentID returns the ID the registry knows the agent by: the one it assigned at
+// registration, else the configured agent ID
+func (sdk *SDK) registeredAgentID() string {
+	if info := sdk.registration.Load(); info != nil && info.AgentID != "" {
+		return info.AgentID
+	}
+	return sdk.registryAgentID()
+}
+
+// heartbeatLoop keeps the agent registered. Every failed heartbeat fires OnHeartbeatFailed. A
+// heartbeat answered with one of HeartbeatReregisterStatuses means the registry forgot the agent,
+// so it is registered again, with backoff, before heartbeats resume. After HeartbeatFailureThreshold
+// consecutive failures of any other kind the loop also fires OnError and re-registers the agent.
 func (sdk *SDK) heartbeatLoop(ctx context.Context) {
 	defer sdk.registryWG.Done()
 
@@ -398,52 +1104,204 @@ func (sdk *SDK) heartbeatLoop(ctx context.Context) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	failures := 0
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			req, err := http.NewRequest(http.MethodPost, sdk.registryURL("/agents/"+sdk.GetAgentID()+"/heartbeat"), nil)
-			if err != nil {
-				log.Printf("registry heartbeat build error: %v", err)
+			err := sdk.sendHeartbeat(ctx)
+			if err == nil {
+				failures = 0
+				sdk.lastHeartbeat.Store(time.Now().UnixNano())
 				continue
 			}
-			req.Header.Set("Content-Type", "application/json")
+			if ctx.Err() != nil {
+				return
+			}
 
-			resp, err := sdk.httpClient.Do(req)
-			if err != nil {
-				log.Printf("registry heartbeat failed: %v", err)
+			failures++
+			sdk.logger.Warn("Registry heartbeat failed", "error", err, "consecutive_failures", failures)
+			sdk.fireCallback("OnHeartbeatFailed", err, failures)
+
+			var statusErr *registryStatusError
+			if errors.As(err, &statusErr) && sdk.config.reregistersOnStatus(statusErr.StatusCode) {
+				sdk.logger.Warn("Registry no longer knows the agent, re-registering", "status", statusErr.Status)
+				if !sdk.reregister(ctx, interval) {
+					return
+				}
+				sdk.logger.Info("Re-registered with registry", "agent_id", sdk.registeredAgentID(), "status", statusErr.Status)
+				failures = 0
+				sdk.lastHeartbeat.Store(time.Now().UnixNano())
+				continue
+			}
+
+			limit := sdk.config.HeartbeatFailureThreshold
+			if limit <= 0 || failures%limit != 0 {
 				continue
 			}
-			resp.Body.Close()
-			if resp.StatusCode >= 300 {
-				log.Printf("registry heartbeat unexpected status: %s", resp.Status)
+			sdk.fireCallback("OnError", fmt.Errorf("registry heartbeat failed %d times in a row: %w", failures, err))
+			if err := sdk.postRegistration(ctx); err != nil {
+				sdk.logger.Error("Registry re-registration failed", "error", err)
+				continue
 			}
+			sdk.logger.Info("Re-registered with registry after heartbeat failures", "consecutive_failures", failures)
+			failures = 0
+			sdk.lastHeartbeat.Store(time.Now().UnixNano())
+		}
+	}
+}
+
+// reregister registers the agent again, retrying with backoff until it succeeds. It returns false
+// if ctx is cancelled first.
+func (sdk *SDK) reregister(ctx context.Context, interval time.Duration) bool {
+	backoff := newReconnectBackoff(min(registryReregisterInitialBackoff, interval), registryReregisterMaxBackoff)
+	for {
+		err := sdk.postRegistration(ctx)
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		sdk.logger.Warn("Registry re-registration failed, retrying", "error", err)
+		if !backoff.wait(ctx, time.Now()) {
+			return false
 		}
 	}
 }
 
+// reregistersOnStatus reports whether a heartbeat answered with code means the registry forgot the agent
+func (c *Config) reregistersOnStatus(code int) bool {
+	return slices.Contains(c.HeartbeatReregisterStatuses, code)
+}
+
+// registryStatusError is returned when the registry answers with a non-success HTTP status
+type registryStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *registryStatusError) Error() string {
+	return fmt.Sprintf("registry returned %s", e.Status)
+}
+
+// registryAgentID returns the agent ID without taking sdk.mu, which Start and Stop hold while
+// registering and while waiting for the heartbeat loop
+func (sdk *SDK) registryAgentID() string {
+	if sdk.config.Identity != nil && sdk.config.Identity.AgentID != "" {
+		return sdk.config.Identity.AgentID
+	}
+	return sdk.config.AgentID
+}
+
+// Heartbeat status values reported to the registry
+const (
+	heartbeatStatusHealthy  = "healthy"
+	heartbeatStatusDraining = "draining" // Stop was called and in-flight tasks are finishing
+)
+
+// heartbeatPayload tells the registry the agent's current load and health. Registries that
+// predate it ignore the body.
+type heartbeatPayload struct {
+	Status        string            `json:"status"`
+	InFlightTasks int64             `json:"in_flight_tasks"`
+	Capabilities  []string          `json:"capabilities"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// heartbeatPayload reports the agent's state for the next heartbeat
+func (sdk *SDK) heartbeatPayload() heartbeatPayload {
+	status := heartbeatStatusHealthy
+	if !sdk.running.Load() {
+		status = heartbeatStatusDraining
+	}
+	return heartbeatPayload{
+		Status:        status,
+		InFlightTasks: sdk.inFlightTasks.Load(),
+		Capabilities:  sdk.config.Capabilities,
+		Metadata:      sdk.config.HeartbeatMetadata,
+	}
+}
+
+// sendHeartbeat posts a single registry heartbeat
+func (sdk *SDK) sendHeartbeat(ctx context.Context) error {
+	body, err := json.Marshal(sdk.heartbeatPayload())
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := sdk.newRegistryRequest(ctx, http.MethodPost, "/agents/"+sdk.registeredAgentID()+"/heartbeat", body)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	resp, err := sdk.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return &registryStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return nil
+}
+
 func (sdk *SDK) stopRegistry() {
-	if sdk.registryCancel != nil {
-		sdk.registryCancel()
-		sdk.registryWG.Wait()
-		sdk.registryCancel = nil
+	sdk.stopRegistryLoop()
+
+	// An optional registry may never have accepted the agent, or Unregister already removed it
+	if sdk.config.RegistryAddr != "" && sdk.registration.Load() != nil {
+		if err := sdk.unregisterAgent(context.Background()); err != nil {
+			sdk.logger.Warn("Failed to unregister agent", "error", err)
+		}
+		sdk.registration.Store(nil)
 	}
+}
 
-	if sdk.config.RegistryAddr != "" {
-		req, err := http.NewRequest(http.MethodDelete, sdk.registryURL("/agents/"+sdk.GetAgentID()), nil)
-		if err == nil {
-			resp, err := sdk.httpClient.Do(req)
-			if err != nil {
-				log.Printf("failed to unregister agent: %v", err)
-			} else {
-				resp.Body.Close()
-				if resp.StatusCode >= 300 {
-					log.Printf("unregister agent returned %s", resp.Status)
-				}
-			}
+// unregisterAgent deletes the agent's registry record
+func (sdk *SDK) unregisterAgent(ctx context.Context) error {
+	req, err := sdk.newRegistryRequest(ctx, http.MethodDelete, "/agents/"+sdk.registeredAgentID(), nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	resp, err := sdk.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unregister agent: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unregister agent: registry returned %s", resp.Status)
+	}
+	sdk.registration.Store(nil)
+	return nil
+}
+
+// newRegistryRequest builds a request to the registry, sending body as JSON and authenticating
+// with the registry bearer token when one is configured
+func (sdk *SDK) newRegistryRequest(ctx context.Context, method, path string, body []byte) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, sdk.registryURL(path), reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	token := sdk.config.RegistryToken
+	if provider := sdk.config.RegistryTokenProvider; provider != nil {
+		if token, err = provider(ctx); err != nil {
+			return nil, fmt.Errorf("registry token: %w", err)
 		}
 	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
 }
 
 func (sdk *SDK) registryURL(path string) string {
@@ -466,7 +1324,7 @@ func (sdk *SDK) DiscoverValidators(ctx context.Context) ([]ValidatorEndpoint, er
 		return nil, errors.New("registry_addr not configured")
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sdk.registryURL("/validators"), nil)
+	req, err := sdk.newRegistryRequest(ctx, http.MethodGet, "/validators", nil)
 	if err != nil {
 		return nil, fmt.Errorf("build request: %w", err)
 	}
@@ -507,64 +1365,80 @@ func (sdk *SDK) DiscoverValidators(ctx context.Context) ([]ValidatorEndpoint, er
 	return validators, nil
 }
 
-// SubmitExecutionReport sends the execution report to all discovered validators
-func (sdk *SDK) SubmitExecutionReport(ctx context.Context, report *ExecutionReport) ([]*ExecutionReceipt, error) {
-	if report == nil {
-		return nil, errors.New("execution report is required")
-	}
-
-	reportID := strings.TrimSpace(report.ReportID)
-	if reportID == "" {
-		return nil, errors.New("report_id is required")
-	}
-
-	assignmentID := strings.TrimSpace(report.AssignmentID)
-	if assignmentID == "" {
-		return nil, errors.New("assignment_id is required")
+// DiscoverActiveValidators returns the registry validators whose status is "active" and that were
+// last seen within maxAge; a non-positive maxAge skips the age check. Use DiscoverValidators to get
+// every registered validator.
+func (sdk *SDK) DiscoverActiveValidators(ctx context.Context, maxAge time.Duration) ([]ValidatorEndpoint, error) {
+	validators, err := sdk.DiscoverValidators(ctx)
+	if err != nil {
+		return nil, err
 	}
+	return activeValidators(validators, maxAge, time.Now()), nil
+}
 
-	intentID := strings.TrimSpace(report.IntentID)
-	if intentID == "" {
-		return nil, errors.New("intent_id is required")
+// activeValidators filters validators down to active ones seen within maxAge of now
+func activeValidators(validators []ValidatorEndpoint, maxAge time.Duration, now time.Time) []ValidatorEndpoint {
+	active := make([]ValidatorEndpoint, 0, len(validators))
+	for _, validator := range validators {
+		if !strings.EqualFold(validator.Status, validatorStatusActive) {
+			continue
+		}
+		if maxAge > 0 && now.Sub(validator.LastSeen) > maxAge {
+			continue
+		}
+		active = append(active, validator)
 	}
+	return active
+}
 
-	agentID := strings.TrimSpace(report.AgentID)
-	if agentID == "" {
-		agentID = sdk.GetAgentID()
-	}
-	if agentID == "" {
-		return nil, errors.New("agent_id is required")
+// SubmitExecutionReport sends the execution report to all discovered validators concurrently.
+// Receipts come back in endpoint order; a joined error reports the validators that failed. When ctx
+// is cancelled mid-fan-out, the receipts collected so far are returned with an error matching
+// ctx.Err().
+func (sdk *SDK) SubmitExecutionReport(ctx context.Context, report *ExecutionReport) ([]*ExecutionReceipt, error) {
+	fields, err := sdk.normalizeReport(report)
+	if err != nil {
+		return nil, err
 	}
+	reportID, assignmentID, intentID, agentID := fields.reportID, fields.assignmentID, fields.intentID, fields.agentID
+	status, timestamp := fields.status, fields.timestamp
 
-	status := report.Status
-	if status == "" {
-		status = ExecutionReportStatusSuccess
+	// Hash and sign the result data as transmitted, compressed or not
+	resultData, err := sdk.config.encodeResultData(report.ResultData)
+	if err != nil {
+		return nil, err
 	}
-	if !isValidExecutionStatus(status) {
-		return nil, fmt.Errorf("invalid status: %s", status)
+	encodedResult := ""
+	if len(resultData) > 0 {
+		encodedResult = base64.StdEncoding.EncodeToString(resultData)
 	}
 
-	timestamp := report.Timestamp
-	if timestamp.IsZero() {
-		timestamp = time.Now()
+	metadata := filterReportMetadata(report.Metadata, sdk.config.ReportMetadataAllowlist, sdk.config.ReportMetadataDenylist)
+	metadata = ensureChainAddressMetadata(metadata, sdk.GetChainAddress())
+	if algorithm := sdk.config.ResultHashAlgorithm; algorithm != "" {
+		digest, err := hashResultData(algorithm, resultData)
+		if err != nil {
+			return nil, err
+		}
+		if metadata == nil {
+			metadata = make(map[string]string, 2)
+		}
+		metadata[resultHashMetadataKey] = hex.EncodeToString(digest)
+		metadata[resultHashAlgorithmMetadataKey] = algorithm
 	}
-
-	endpoints, endpointErrs := sdk.validatorReportEndpoints(ctx)
-	if len(endpoints) == 0 {
-		if len(endpointErrs) == 0 {
-			return nil, errors.New("no validator endpoints available")
+	if sdk.config.shouldCompressResult(report.ResultData) {
+		if metadata == nil {
+			metadata = make(map[string]string, 1)
 		}
-		return nil, errors.Join(endpointErrs...)
+		metadata[resultEncodingMetadataKey] = ResultEncodingGzip
 	}
+	report.Metadata = metadata
 
-	encodedResult := ""
-	if len(report.ResultData) > 0 {
-		encodedResult = base64.StdEncoding.EncodeToString(report.ResultData)
+	signature, err := sdk.signReport(reportID, assignmentID, intentID, agentID, status, resultData, timestamp.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("sign report: %w", err)
 	}
 
-	metadata := ensureChainAddressMetadata(report.Metadata, sdk.GetChainAddress())
-	report.Metadata = metadata
-
 	payload := executionReportRequest{
 		ReportID:     reportID,
 		AssignmentID: assignmentID,
@@ -574,24 +1448,92 @@ func (sdk *SDK) SubmitExecutionReport(ctx context.Context, report *ExecutionRepo
 		ResultData:   encodedResult,
 		Timestamp:    timestamp.Unix(),
 		Metadata:     metadata,
+		Signature:    hex.EncodeToString(signature),
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+	if err := sdk.checkReportPayloadSize(len(body)); err != nil {
+		sdk.metrics.RecordReportFailure()
+		return nil, err
+	}
+	submitted := &ExecutionReport{
+		ReportID:     reportID,
+		AssignmentID: assignmentID,
+		IntentID:     intentID,
+		AgentID:      agentID,
+		Status:       status,
+		ResultData:   report.ResultData,
+		Timestamp:    timestamp,
+		Metadata:     metadata,
+	}
+
+	endpoints, endpointErrs := sdk.validatorReportEndpoints(ctx)
+	if len(endpoints) == 0 {
+		if len(endpointErrs) == 0 {
+			return nil, fmt.Errorf("report %s: %w", reportID, ErrNoValidators)
+		}
+		return nil, fmt.Errorf("report %s: %w: %w", reportID, ErrNoValidators, errors.Join(endpointErrs...))
+	}
+
+	// Post to validators concurrently; results are kept in endpoint order
+	results := make([]*ExecutionReceipt, len(endpoints))
+	errs := make([]error, len(endpoints))
+	sem := make(chan struct{}, defaultReportFanOutConcurrency)
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = fmt.Errorf("%s: %w", endpoint, ctx.Err())
+				sdk.metrics.RecordReportFailure()
+				return
+			}
+
+			var receipt *ExecutionReceipt
+			err := sdk.submitWithRetry(ctx, endpoint, []string{reportID}, func(ctx context.Context) error {
+				var err error
+				receipt, err = sdk.postExecutionReport(ctx, endpoint, reportID, body)
+				return err
+			})
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", endpoint, err)
+				sdk.metrics.RecordReportFailure()
+				sdk.invalidateCachedEndpoint(endpoint)
+				return
+			}
+
+			receipt.Endpoint = endpoint
+			results[i] = receipt
+			sdk.metrics.RecordReportSuccess()
+			sdk.fireCallback("OnReportSubmitted", submitted, receipt)
+		}()
 	}
+	wg.Wait()
 
 	var (
 		receipts   []*ExecutionReceipt
 		submitErrs []error
 	)
-
-	for _, endpoint := range endpoints {
-		receipt, err := sdk.postExecutionReport(ctx, endpoint, payload)
-		if err != nil {
-			submitErrs = append(submitErrs, fmt.Errorf("%s: %w", endpoint, err))
-			sdk.metrics.RecordReportFailure()
-			continue
+	for i := range endpoints {
+		if results[i] != nil {
+			receipts = append(receipts, results[i])
+		}
+		if errs[i] != nil {
+			submitErrs = append(submitErrs, errs[i])
 		}
+	}
 
-		receipt.Endpoint = endpoint
-		receipts = append(receipts, receipt)
-		sdk.metrics.RecordReportSuccess()
+	// An endpoint cut short by cancellation may fail with its last validator error instead; the
+	// caller still needs to see ctx.Err() to tell a cancelled fan-out from failed validators
+	if ctxErr := ctx.Err(); ctxErr != nil && len(submitErrs) > 0 && !errors.Is(errors.Join(submitErrs...), ctxErr) {
+		submitErrs = append(submitErrs, ctxErr)
 	}
 
 	if len(receipts) == 0 {
@@ -610,7 +1552,8 @@ func (sdk *SDK) SubmitExecutionReport(ctx context.Context, report *ExecutionRepo
 
 // GetExecutionReport retrieves a single execution report by report ID from the validator
 func (sdk *SDK) GetExecutionReport(ctx context.Context, reportID string) (*ExecutionReport, error) {
-	if sdk.validatorClient == nil {
+	validatorClient := sdk.clients().validator
+	if validatorClient == nil {
 		return nil, errors.New("validator client not initialized")
 	}
 
@@ -618,30 +1561,19 @@ func (sdk *SDK) GetExecutionReport(ctx context.Context, reportID string) (*Execu
 		return nil, errors.New("report_id is required")
 	}
 
-	pbReport, err := sdk.validatorClient.GetExecutionReport(ctx, reportID)
+	pbReport, err := validatorClient.GetExecutionReport(ctx, reportID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get execution report: %w", err)
 	}
 
-	// Convert protobuf ExecutionReport to SDK ExecutionReport
-	report := &ExecutionReport{
-		ReportID:     pbReport.ReportId,
-		AssignmentID: pbReport.AssignmentId,
-		IntentID:     pbReport.IntentId,
-		AgentID:      pbReport.AgentId,
-		Status:       convertProtoStatusToSDK(pbReport.Status),
-		ResultData:   pbReport.ResultData,
-		Timestamp:    time.Unix(pbReport.Timestamp, 0),
-		Metadata:     nil, // Protobuf ExecutionReport doesn't have metadata field
-	}
-
-	return report, nil
+	return executionReportFromProto(pbReport), nil
 }
 
 // ListExecutionReports retrieves a list of execution reports, optionally filtered by intent ID
 // If intentID is empty, returns all reports. The limit parameter controls the maximum number of reports returned.
 func (sdk *SDK) ListExecutionReports(ctx context.Context, intentID string, limit uint32) ([]*ExecutionReport, error) {
-	if sdk.validatorClient == nil {
+	validatorClient := sdk.clients().validator
+	if validatorClient == nil {
 		return nil, errors.New("validator client not initialized")
 	}
 
@@ -649,7 +1581,7 @@ func (sdk *SDK) ListExecutionReports(ctx context.Context, intentID string, limit
 		limit = 100 // default limit
 	}
 
-	resp, err := sdk.validatorClient.ListExecutionReports(ctx, intentID, limit)
+	resp, err := validatorClient.ListExecutionReports(ctx, intentID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list execution reports: %w", err)
 	}
@@ -660,22 +1592,26 @@ func (sdk *SDK) ListExecutionReports(ctx context.Context, intentID string, limit
 			continue
 		}
 
-		report := &ExecutionReport{
-			ReportID:     entry.Report.ReportId,
-			AssignmentID: entry.Report.AssignmentId,
-			IntentID:     entry.Report.IntentId,
-			AgentID:      entry.Report.AgentId,
-			Status:       convertProtoStatusToSDK(entry.Report.Status),
-			ResultData:   entry.Report.ResultData,
-			Timestamp:    time.Unix(entry.Report.Timestamp, 0),
-			Metadata:     nil, // Protobuf ExecutionReport doesn't have metadata field
-		}
-		reports = append(reports, report)
+		reports = append(reports, executionReportFromProto(entry.Report))
 	}
 
 	return reports, nil
 }
 
+// executionReportFromProto converts a protobuf ExecutionReport to the SDK ExecutionReport
+func executionReportFromProto(report *pb.ExecutionReport) *ExecutionReport {
+	return &ExecutionReport{
+		ReportID:     report.ReportId,
+		AssignmentID: report.AssignmentId,
+		IntentID:     report.IntentId,
+		AgentID:      report.AgentId,
+		Status:       convertProtoStatusToSDK(report.Status),
+		ResultData:   report.ResultData,
+		Timestamp:    time.Unix(report.Timestamp, 0),
+		Metadata:     nil, // Protobuf ExecutionReport doesn't have metadata field
+	}
+}
+
 // convertProtoStatusToSDK converts protobuf ExecutionReport.Status enum to SDK ExecutionReportStatus string
 func convertProtoStatusToSDK(protoStatus pb.ExecutionReport_Status) ExecutionReportStatus {
 	switch protoStatus {
@@ -699,6 +1635,7 @@ type executionReportRequest struct {
 	ResultData   string            `json:"result_data,omitempty"`
 	Timestamp    int64             `json:"timestamp"`
 	Metadata     map[string]string `json:"metadata,omitempty"`
+	Signature    string            `json:"signature,omitempty"`
 }
 
 func isValidExecutionStatus(status ExecutionReportStatus) bool {
@@ -713,21 +1650,10 @@ func isValidExecutionStatus(status ExecutionReportStatus) bool {
 	}
 }
 
-// ensureChainAddressMetadata ensures that the chain address is included in metadata.
-// This function is used to automatically inject the agent's on-chain address into
-// execution reports and bid submissions.
-//
-// Usage:
-//   - For execution reports: called in SubmitExecutionReport() (line 504)
-//   - For bid submissions: MUST be called when implementing SubmitBid()
-//
-// Example for future bid implementation:
-//
-//	bidMetadata := ensureChainAddressMetadata(bid.Metadata, sdk.GetChainAddress())
-//	// ... then use bidMetadata in the bid request
-//
-// This ensures consistency across all metadata-bearing requests and matches
-// the Python SDK's behavior (see Python SDK's _ensure_chain_metadata).
+// ensureChainAddressMetadata returns a copy of src carrying the agent's on-chain address under
+// chainAddressMetadataKey, unless src already sets it. SubmitExecutionReport applies it to report
+// metadata and newBidProto to bid metadata, covering both strategy bids and SubmitBid. This matches
+// the Python SDK's _ensure_chain_metadata.
 func ensureChainAddressMetadata(src map[string]string, addr string) map[string]string {
 	if src == nil && addr == "" {
 		return nil
@@ -750,6 +1676,39 @@ func ensureChainAddressMetadata(src map[string]string, addr string) map[string]s
 	return metadata
 }
 
+// filterReportMetadata drops caller-supplied report metadata keys that are not in allowlist
+// (when non-empty) or that appear in denylist. Keys the SDK adds afterwards are not affected.
+func filterReportMetadata(src map[string]string, allowlist, denylist []string) map[string]string {
+	if src == nil || (len(allowlist) == 0 && len(denylist) == 0) {
+		return src
+	}
+
+	filtered := make(map[string]string, len(src))
+	for key, value := range src {
+		if len(allowlist) > 0 && !slices.Contains(allowlist, key) {
+			continue
+		}
+		if slices.Contains(denylist, key) {
+			continue
+		}
+		filtered[key] = value
+	}
+	return filtered
+}
+
+// hashResultData hashes result bytes with the configured algorithm
+func hashResultData(algorithm string, data []byte) ([]byte, error) {
+	switch algorithm {
+	case ResultHashKeccak256:
+		return crypto.Keccak256(data), nil
+	case ResultHashSHA256:
+		digest := sha256.Sum256(data)
+		return digest[:], nil
+	default:
+		return nil, fmt.Errorf("unsupported result hash algorithm: %s", algorithm)
+	}
+}
+
 func cloneStringMap(src map[string]string) map[string]string {
 	clone := make(map[string]string, len(src)+1)
 	for k, v := range src {
@@ -761,12 +1720,13 @@ func cloneStringMap(src map[string]string) map[string]string {
 func (sdk *SDK) validatorReportEndpoints(ctx context.Context) ([]string, []error) {
 	seen := make(map[string]struct{})
 	var (
-		endpoints []string
-		errs      []error
+		endpoints  []string
+		sampledOut []string
+		errs       []error
 	)
 
-	addEndpoint := func(raw string) {
-		urlStr, err := buildExecutionReportURL(raw)
+	addEndpoint := func(raw, id string) {
+		urlStr, err := buildExecutionReportURL(raw, sdk.config.ReportScheme, sdk.config.ReportPath)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("%s: %w", raw, err))
 			return
@@ -778,22 +1738,44 @@ func (sdk *SDK) validatorReportEndpoints(ctx context.Context) ([]string, []error
 			return
 		}
 		seen[urlStr] = struct{}{}
+		if weight := sdk.validatorWeight(raw, id); weight < 1 && sdk.sampleFloat() >= weight {
+			if weight > 0 {
+				sampledOut = append(sampledOut, urlStr)
+			}
+			return
+		}
 		endpoints = append(endpoints, urlStr)
 	}
 
-	if sdk.config.RegistryAddr != "" {
-		validators, err := sdk.DiscoverValidators(ctx)
+	if resolver := sdk.config.ValidatorEndpointResolver; resolver != nil {
+		validators, err := sdk.cachedValidatorEndpoints(ctx, resolver)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("resolve validators: %w", err))
+		} else {
+			for _, validator := range validators {
+				addEndpoint(validator.Endpoint, validator.ID)
+			}
+		}
+	} else if sdk.config.RegistryAddr != "" {
+		validators, err := sdk.cachedValidatorEndpoints(ctx, func(ctx context.Context) ([]ValidatorEndpoint, error) {
+			return sdk.DiscoverActiveValidators(ctx, defaultActiveValidatorMaxAge)
+		})
 		if err != nil {
 			errs = append(errs, fmt.Errorf("discover validators: %w", err))
 		} else {
 			for _, validator := range validators {
-				addEndpoint(validator.Endpoint)
+				addEndpoint(validator.Endpoint, validator.ID)
 			}
 		}
 	}
 
-	if sdk.config.ValidatorAddr != "" {
-		addEndpoint(sdk.config.ValidatorAddr)
+	for _, addr := range sdk.config.validatorAddrs() {
+		addEndpoint(addr, "")
+	}
+
+	// Weighting limits exposure to canary validators but never leaves a report with no target
+	if len(endpoints) == 0 {
+		endpoints = sampledOut
 	}
 
 	if len(endpoints) > 1 {
@@ -803,13 +1785,28 @@ func (sdk *SDK) validatorReportEndpoints(ctx context.Context) ([]string, []error
 	return endpoints, errs
 }
 
-func buildExecutionReportURL(endpoint string) (string, error) {
+// validatorWeight returns the configured weight for a validator, matched by ID or by endpoint as
+// configured/discovered. Unweighted validators always receive reports.
+func (sdk *SDK) validatorWeight(endpoint, id string) float64 {
+	if weight, ok := sdk.config.ValidatorWeights[id]; ok && id != "" {
+		return weight
+	}
+	if weight, ok := sdk.config.ValidatorWeights[strings.TrimSpace(endpoint)]; ok {
+		return weight
+	}
+	return 1
+}
+
+// buildExecutionReportURL returns the URL reports are posted to for a validator endpoint. An
+// endpoint without a scheme gets scheme; an explicit scheme and port are kept. reportPath is
+// appended unless the endpoint already ends with it.
+func buildExecutionReportURL(endpoint, scheme, reportPath string) (string, error) {
 	trimmed := strings.TrimSpace(endpoint)
 	if trimmed == "" {
 		return "", nil
 	}
-	if !strings.HasPrefix(trimmed, "http://") && !strings.HasPrefix(trimmed, "https://") {
-		trimmed = "http://" + trimmed
+	if !strings.Contains(trimmed, "://") {
+		trimmed = scheme + "://" + trimmed
 	}
 
 	parsed, err := url.Parse(trimmed)
@@ -817,43 +1814,49 @@ func buildExecutionReportURL(endpoint string) (string, error) {
 		return "", err
 	}
 
+	suffix := strings.TrimSuffix(reportPath, "/")
 	path := strings.TrimSuffix(parsed.Path, "/")
-	if path == "" || path == "/" {
-		parsed.Path = "/api/v1/execution-report"
-	} else if strings.HasSuffix(path, "/api/v1/execution-report") {
-		parsed.Path = path
-	} else {
-		parsed.Path = path + "/api/v1/execution-report"
+	if !strings.HasSuffix(path, suffix) {
+		path += suffix
+	}
+	if path == "" {
+		path = "/"
 	}
+	parsed.Path = path
 
 	return parsed.String(), nil
 }
 
-func (sdk *SDK) postExecutionReport(parentCtx context.Context, endpoint string, payload executionReportRequest) (*ExecutionReceipt, error) {
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("marshal payload: %w", err)
+// checkReportPayloadSize fails oversized reports locally instead of letting validators reject them
+func (sdk *SDK) checkReportPayloadSize(size int) error {
+	limit := sdk.config.ReportMaxPayloadSize
+	if limit <= 0 || size <= limit {
+		return nil
 	}
+	return fmt.Errorf("report payload is %d bytes, exceeds max payload size of %d bytes", size, limit)
+}
 
-	timeout := defaultReportTimeout
-	if deadline, ok := parentCtx.Deadline(); ok {
-		remaining := time.Until(deadline)
-		if remaining > 0 && remaining < timeout {
-			timeout = remaining
-		}
-		if remaining <= 0 {
-			return nil, context.DeadlineExceeded
-		}
+// postExecutionReport posts an encoded report to a validator endpoint. reportID is sent as the
+// idempotency key, so retries of the same report can be deduplicated.
+func (sdk *SDK) postExecutionReport(parentCtx context.Context, endpoint, reportID string, body []byte) (*ExecutionReceipt, error) {
+	if deadline, ok := parentCtx.Deadline(); ok && time.Until(deadline) <= 0 {
+		return nil, context.DeadlineExceeded
 	}
 
-	reqCtx, cancel := context.WithTimeout(parentCtx, timeout)
-	defer cancel()
+	// An explicit HTTPClient brings its own timeout; otherwise HTTPTimeout bounds the call
+	reqCtx := parentCtx
+	if sdk.config.HTTPClient == nil {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(parentCtx, sdk.config.HTTPTimeout)
+		defer cancel()
+	}
 
 	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, endpoint, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("build request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IdempotencyKeyHeader, reportID)
 
 	resp, err := sdk.httpClient.Do(req)
 	if err != nil {
@@ -875,6 +1878,7 @@ func (sdk *SDK) postExecutionReport(parentCtx context.Context, endpoint string,
 		IntentID    string `json:"intent_id"`
 		ValidatorID string `json:"validator_id"`
 		Status      string `json:"status"`
+		Phase       string `json:"phase"`
 		ReceivedTs  int64  `json:"received_ts"`
 		Message     string `json:"message"`
 	}
@@ -888,6 +1892,7 @@ func (sdk *SDK) postExecutionReport(parentCtx context.Context, endpoint string,
 		IntentID:    reply.IntentID,
 		ValidatorID: reply.ValidatorID,
 		Status:      reply.Status,
+		Phase:       reply.Phase,
 		Message:     reply.Message,
 	}
 	if reply.ReceivedTs > 0 {
@@ -928,18 +1933,165 @@ func (c *Config) Validate() error {
 		c.ChainAddress = common.HexToAddress(addr).Hex()
 	}
 
-	// Validate capabilities
+	switch c.ResultHashAlgorithm {
+	case "", ResultHashKeccak256, ResultHashSHA256:
+	default:
+		return fmt.Errorf("result_hash_algorithm must be %q or %q", ResultHashKeccak256, ResultHashSHA256)
+	}
+	if err := validateSignatureScheme(c.SignatureScheme); err != nil {
+		return err
+	}
+
+	// Validate capabilities; blanks are dropped and duplicates collapsed
+	c.Capabilities = normalizeCapabilities(c.Capabilities)
 	if len(c.Capabilities) == 0 {
 		return errors.New("at least one capability must be configured")
 	}
 
+	if c.MaxConcurrentTasks < 0 {
+		return errors.New("max_concurrent_tasks must not be negative")
+	}
+	if c.TaskQueueDepth < 0 {
+		return errors.New("task_queue_depth must not be negative")
+	}
+	if c.TaskStreamBuffer < 0 {
+		return errors.New("task_stream_buffer must not be negative")
+	}
+	if c.ResultCompressionThreshold < 0 {
+		return errors.New("result_compression_threshold must not be negative")
+	}
+
+	if c.BidRatePerSecond < 0 || c.BidRateBurst < 0 {
+		return errors.New("bid rate limit must not be negative")
+	}
+
+	if c.MaxBidPrice != 0 && c.MinBidPrice > c.MaxBidPrice {
+		return errors.New("min_bid_price must not exceed max_bid_price")
+	}
+
+	if c.ReconnectInitialBackoff < 0 || c.ReconnectMaxBackoff < 0 {
+		return errors.New("reconnect backoff must not be negative")
+	}
+	if c.ReconnectMaxBackoff > 0 && c.ReconnectMaxBackoff < c.ReconnectInitialBackoff {
+		return errors.New("reconnect max backoff must be at least the initial backoff")
+	}
+
+	if c.ShutdownTimeout < 0 {
+		return errors.New("shutdown_timeout must not be negative")
+	}
+
+	if c.HTTPTimeout < 0 {
+		return errors.New("http_timeout must not be negative")
+	}
+
+	if err := validateReportOverflowPolicy(c.ReportOverflowPolicy); err != nil {
+		return err
+	}
+	switch c.ReportScheme {
+	case "", "http", "https":
+	default:
+		return fmt.Errorf("report_scheme must be \"http\" or \"https\", got %q", c.ReportScheme)
+	}
+
+	if _, err := parseLogLevel(c.LogLevel); err != nil {
+		return err
+	}
+
+	if c.ValidatorSetCacheTTL < 0 {
+		return errors.New("validator_set_cache_ttl must not be negative")
+	}
+	if c.ValidatorCacheTTL < 0 {
+		return errors.New("validator_cache_ttl must not be negative")
+	}
+
+	for validator, weight := range c.ValidatorWeights {
+		if !(weight >= 0 && weight <= 1) {
+			return fmt.Errorf("weight for validator %q must be in [0, 1]", validator)
+		}
+	}
+
+	for _, code := range c.HeartbeatReregisterStatuses {
+		if code < 400 || code > 599 {
+			return fmt.Errorf("heartbeat_reregister_statuses: %d is not an HTTP 4xx or 5xx status", code)
+		}
+	}
+
+	if c.BidBatchSize < 0 {
+		return errors.New("bid_batch_size must not be negative")
+	}
+	if c.BidBatchSize > 0 && c.BidBatchFlushInterval <= 0 {
+		return errors.New("bid_batch_flush_interval must be positive when bid batching is enabled")
+	}
+
+	if c.ReportBatchSize < 0 {
+		return errors.New("report_batch_size must not be negative")
+	}
+	if c.ReportBatchSize > 0 && c.ReportBatchFlushInterval <= 0 {
+		return errors.New("report_batch_flush_interval must be positive when report batching is enabled")
+	}
+
+	if c.MatcherSubscriptionAttempts < 0 {
+		return errors.New("matcher_subscription_attempts must not be negative")
+	}
+
+	if c.ReportFinalizeThreshold < 0 {
+		return errors.New("report_finalize_threshold must not be negative")
+	}
+
+	for taskType, limit := range c.MaxConcurrentTasksPerType {
+		if limit <= 0 {
+			return fmt.Errorf("max concurrent tasks for type %q must be positive", taskType)
+		}
+	}
+
+	if c.ReportMaxRetries < 0 || c.ReportRetryBackoff < 0 {
+		return errors.New("report retries and backoff must not be negative")
+	}
+	if c.ReportRetryJitter >= 1 {
+		return errors.New("report_retry_jitter must be below 1")
+	}
+
 	// Validate matcher address
 	if c.MatcherAddr == "" {
 		return errors.New("matcher_addr must be configured")
 	}
+	if err := validateHostPort("matcher_addr", c.MatcherAddr); err != nil {
+		return err
+	}
+	if c.ValidatorAddr != "" {
+		if err := validateHostPort("validator_addr", c.ValidatorAddr); err != nil {
+			return err
+		}
+	}
+	for _, addr := range c.ValidatorAddrs {
+		if strings.TrimSpace(addr) == "" {
+			continue
+		}
+		if err := validateHostPort("validator_addrs", addr); err != nil {
+			return err
+		}
+	}
+
+	if c.RegistryAddr != "" {
+		if err := validateHTTPAddr("registry_addr", c.RegistryAddr); err != nil {
+			return err
+		}
+		if c.AgentEndpoint == "" {
+			return errors.New("agent_endpoint must be configured when registry_addr is set")
+		}
+		if c.StrictRegistryAuth && c.RegistryToken == "" && c.RegistryTokenProvider == nil &&
+			strings.HasPrefix(strings.ToLower(strings.TrimSpace(c.RegistryAddr)), "https://") {
+			return errors.New("registry_token must be configured for an https registry_addr when strict_registry_auth is set")
+		}
+	}
+	if c.AgentEndpoint != "" {
+		if err := validateHTTPAddr("agent_endpoint", c.AgentEndpoint); err != nil {
+			return err
+		}
+	}
 
-	if c.RegistryAddr != "" && c.AgentEndpoint == "" {
-		return errors.New("agent_endpoint must be configured when registry_addr is set")
+	if c.PersistTaskResults && c.DataDir == "" {
+		return errors.New("data_dir must be configured when task result persistence is enabled")
 	}
 
 	return nil
@@ -950,15 +2102,15 @@ func (c *Config) applyDefaults() {
 	if c.MaxConcurrentTasks == 0 {
 		c.MaxConcurrentTasks = 5
 	}
+	if c.TaskQueueDepth == 0 {
+		c.TaskQueueDepth = defaultTaskQueueDepth
+	}
 	if c.TaskTimeout == 0 {
 		c.TaskTimeout = 30 * time.Second
 	}
 	if c.BidTimeout == 0 {
 		c.BidTimeout = 5 * time.Second
 	}
-	if c.BiddingStrategy == "" {
-		c.BiddingStrategy = "fixed"
-	}
 	if c.MinBidPrice == 0 {
 		c.MinBidPrice = 100
 	}
@@ -978,87 +2130,298 @@ func (c *Config) applyDefaults() {
 	if c.RegistryHeartbeatInterval == 0 {
 		c.RegistryHeartbeatInterval = 30 * time.Second
 	}
+	if c.HTTPTimeout == 0 {
+		c.HTTPTimeout = defaultHTTPTimeout
+	}
+	if c.HeartbeatFailureThreshold == 0 {
+		c.HeartbeatFailureThreshold = defaultHeartbeatFailureThreshold
+	}
+	if c.HeartbeatReregisterStatuses == nil {
+		c.HeartbeatReregisterStatuses = []int{http.StatusNotFound}
+	}
+	if c.BidResponseTimeout == 0 {
+		c.BidResponseTimeout = c.BidTimeout
+	}
+	if c.ReconnectInitialBackoff == 0 {
+		c.ReconnectInitialBackoff = defaultReconnectInitialBackoff
+	}
+	if c.ReconnectMaxBackoff == 0 {
+		c.ReconnectMaxBackoff = defaultReconnectMaxBackoff
+	}
+	if c.ShutdownTimeout == 0 {
+		c.ShutdownTimeout = 30 * time.Second
+	}
+	if c.ReportOverflowPolicy == "" {
+		c.ReportOverflowPolicy = ReportOverflowBlock
+	}
+	if c.ReportRetryBackoff == 0 {
+		c.ReportRetryBackoff = defaultReportRetryBackoff
+	}
+	if c.ReportRetryJitter == 0 {
+		c.ReportRetryJitter = defaultReportRetryJitter
+	}
+	if c.ValidatorSetCacheTTL == 0 {
+		c.ValidatorSetCacheTTL = defaultValidatorSetCacheTTL
+	}
+	if c.ValidatorCacheTTL == 0 {
+		c.ValidatorCacheTTL = defaultValidatorCacheTTL
+	}
+	if c.ReportFinalizeThreshold == 0 {
+		c.ReportFinalizeThreshold = 1
+	}
+	if c.TaskDedupWindow == 0 {
+		c.TaskDedupWindow = defaultTaskDedupWindow
+	}
+	if c.MatcherSubscriptionAttempts == 0 {
+		c.MatcherSubscriptionAttempts = defaultMatcherSubscriptionAttempts
+	}
+	if c.PersistTaskResults && c.TaskResultRetention == 0 {
+		c.TaskResultRetention = defaultTaskResultRetention
+	}
+	if c.ReportMaxPayloadSize == 0 {
+		c.ReportMaxPayloadSize = defaultReportMaxPayloadSize
+	}
+	if c.ReportScheme == "" {
+		c.ReportScheme = defaultReportScheme
+	}
+	if c.ReportPath = strings.TrimSpace(c.ReportPath); c.ReportPath == "" {
+		c.ReportPath = defaultReportPath
+	} else if !strings.HasPrefix(c.ReportPath, "/") {
+		c.ReportPath = "/" + c.ReportPath
+	}
+}
+
+// signingConfig returns the configuration requests are signed with, or nil without a private key.
+// Callers hold sdk.mu.
+func (sdk *SDK) signingConfig() *SigningConfig {
+	if sdk.privateKey == nil {
+		return nil
+	}
+	config := &SigningConfig{
+		PrivateKey:    sdk.privateKey,
+		Address:       sdk.address,
+		ChainID:       sdk.subnetID(),
+		Rand:          sdk.config.RandSource,
+		Scheme:        sdk.config.SignatureScheme,
+		EVMRecoveryID: sdk.config.EVMRecoveryID,
+	}
+	if sdk.config.SignAgentInfo {
+		config.Capabilities = append([]string{}, sdk.config.Capabilities...)
+		config.SDKVersion = Version
+	}
+	return config
 }
 
 // initGRPCClients initializes gRPC clients for matcher and validator
 func (sdk *SDK) initGRPCClients() error {
-	var signingConfig *SigningConfig
-	if sdk.privateKey != nil {
-		signingConfig = &SigningConfig{
-			PrivateKey: sdk.privateKey,
-			Address:    sdk.address,
-			ChainID:    sdk.GetSubnetID(),
-		}
-	}
+	signingConfig := sdk.signingConfig()
+	dialOpts := sdk.grpcDialOptions()
 
 	// Initialize matcher client
 	if sdk.config.MatcherAddr != "" {
-		client, err := NewMatcherClient(sdk.config.MatcherAddr, signingConfig, sdk.config.UseTLS)
+		client, err := NewMatcherClient(sdk.config.MatcherAddr, signingConfig, sdk.config.UseTLS, dialOpts...)
 		if err != nil {
 			return fmt.Errorf("failed to create matcher client: %w", err)
 		}
+		client.SetStreamReceiveTimeout(sdk.config.StreamReceiveTimeout)
+		client.SetTaskBufferSize(sdk.config.TaskStreamBuffer)
+		client.SetLogger(sdk.logger)
 		sdk.matcherClient = client
 	}
 
-	// Initialize validator client
-	if sdk.config.ValidatorAddr != "" {
-		client, err := NewValidatorClient(sdk.config.ValidatorAddr, signingConfig, sdk.config.UseTLS)
+	// Initialize one validator client per address; the first is the primary
+	for _, addr := range sdk.config.validatorAddrs() {
+		client, err := NewValidatorClient(addr, signingConfig, sdk.config.UseTLS, dialOpts...)
 		if err != nil {
-			if sdk.matcherClient != nil {
-				sdk.matcherClient.Close()
-			}
-			return fmt.Errorf("failed to create validator client: %w", err)
+			sdk.closeGRPCClients()
+			return fmt.Errorf("failed to create validator client for %s: %w", addr, err)
+		}
+		sdk.validators = append(sdk.validators, validatorTarget{addr: addr, client: client})
+	}
+	if len(sdk.validators) > 0 {
+		sdk.validatorClient = sdk.validators[0].client
+	}
+
+	return nil
+}
+
+// validatorTarget is a validator gRPC client and the address it was dialed with
+type validatorTarget struct {
+	addr   string
+	client *ValidatorClient
+}
+
+// validatorAddrs returns ValidatorAddr followed by ValidatorAddrs, without blanks or duplicates
+func (c *Config) validatorAddrs() []string {
+	seen := make(map[string]struct{})
+	var addrs []string
+	for _, addr := range append([]string{c.ValidatorAddr}, c.ValidatorAddrs...) {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
 		}
-		sdk.validatorClient = client
+		if _, ok := seen[addr]; ok {
+			continue
+		}
+		seen[addr] = struct{}{}
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// normalizeCapabilities trims capabilities, drops blanks and returns them deduplicated and sorted
+func normalizeCapabilities(capabilities []string) []string {
+	normalized := make([]string, 0, len(capabilities))
+	for _, capability := range capabilities {
+		if capability = strings.TrimSpace(capability); capability != "" {
+			normalized = append(normalized, capability)
+		}
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// validateHostPort checks that addr is a gRPC host:port target without a scheme
+func validateHostPort(field, addr string) error {
+	addr = strings.TrimSpace(addr)
+	if strings.Contains(addr, "://") {
+		return fmt.Errorf("%s %q must be host:port without a scheme", field, addr)
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("%s %q must be host:port: %w", field, addr, err)
+	}
+	if host == "" {
+		return fmt.Errorf("%s %q is missing a host", field, addr)
 	}
+	if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+		return fmt.Errorf("%s %q has an invalid port %q", field, addr, port)
+	}
+	return nil
+}
 
+// validateHTTPAddr checks that addr is an HTTP URL; the http:// scheme may be omitted as it is
+// added when the address is used
+func validateHTTPAddr(field, addr string) error {
+	raw := strings.TrimSpace(addr)
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%s %q must be a valid URL: %w", field, addr, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("%s %q must use the http or https scheme", field, addr)
+	}
+	if parsed.Hostname() == "" {
+		return fmt.Errorf("%s %q is missing a host", field, addr)
+	}
+	if port := parsed.Port(); port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+			return fmt.Errorf("%s %q has an invalid port %q", field, addr, port)
+		}
+	}
 	return nil
 }
 
+// grpcDialOptions returns the extra dial options shared by matcher and validator clients
+func (sdk *SDK) grpcDialOptions() []grpc.DialOption {
+	var opts []grpc.DialOption
+
+	if sdk.tlsConfig != nil {
+		opts = append(opts, tlsDialOption(sdk.tlsConfig))
+	}
+
+	if len(sdk.config.OutgoingMetadata) > 0 || sdk.config.OutgoingMetadataProvider != nil {
+		interceptor := NewMetadataInterceptor(sdk.config.OutgoingMetadata, sdk.config.OutgoingMetadataProvider)
+		opts = append(opts,
+			grpc.WithChainUnaryInterceptor(interceptor.UnaryInterceptor()),
+			grpc.WithChainStreamInterceptor(interceptor.StreamInterceptor()),
+		)
+	}
+
+	return opts
+}
+
 // closeGRPCClients closes all gRPC client connections
 func (sdk *SDK) closeGRPCClients() {
 	if sdk.matcherClient != nil {
 		sdk.matcherClient.Close()
 		sdk.matcherClient = nil
 	}
-	if sdk.validatorClient != nil {
-		sdk.validatorClient.Close()
-		sdk.validatorClient = nil
+	for _, validator := range sdk.validators {
+		validator.client.Close()
+	}
+	sdk.validators = nil
+	sdk.validatorClient = nil
+}
+
+// fireReportCompleted safely invokes the configured report completion callback
+func (sdk *SDK) fireReportCompleted(task *Task, receipts []*ExecutionReceipt, err error) {
+	callback := sdk.config.ReportCompletionCallback
+	if callback == nil {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			sdk.logger.Error("Report completion callback panicked", "panic", r)
+		}
+	}()
+
+	callback(task, receipts, err)
+}
+
+// fireReportFinalized safely invokes the configured report finalizer
+func (sdk *SDK) fireReportFinalized(task *Task, receipts []*ExecutionReceipt) {
+	finalizer := sdk.config.ReportFinalizer
+	if finalizer == nil {
+		return
 	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			sdk.logger.Error("Report finalizer panicked", "panic", r)
+		}
+	}()
+
+	finalizer(task, receipts)
 }
 
 // fireCallback safely invokes a callback if registered
 func (sdk *SDK) fireCallback(name string, args ...interface{}) {
-	if sdk.callbacks == nil {
+	callbacks, exts := sdk.registeredCallbacks()
+	if callbacks == nil {
 		return
 	}
 
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("Callback %s panicked: %v", name, r)
+			sdk.logger.Error("Callback panicked", "callback", name, "panic", r)
 		}
 	}()
 
 	switch name {
 	case "OnStart":
-		if err := sdk.callbacks.OnStart(); err != nil {
-			log.Printf("OnStart callback error: %v", err)
+		if err := callbacks.OnStart(); err != nil {
+			sdk.logger.Error("OnStart callback error", "error", err)
 		}
 	case "OnStop":
-		if err := sdk.callbacks.OnStop(); err != nil {
-			log.Printf("OnStop callback error: %v", err)
+		if err := callbacks.OnStop(); err != nil {
+			sdk.logger.Error("OnStop callback error", "error", err)
 		}
 	case "OnTaskAccepted":
 		if len(args) > 0 {
 			if task, ok := args[0].(*Task); ok {
-				sdk.callbacks.OnTaskAccepted(task)
+				callbacks.OnTaskAccepted(task)
 			}
 		}
 	case "OnTaskRejected":
 		if len(args) > 1 {
 			if task, ok := args[0].(*Task); ok {
 				if reason, ok := args[1].(string); ok {
-					sdk.callbacks.OnTaskRejected(task, reason)
+					callbacks.OnTaskRejected(task, reason)
 				}
 			}
 		}
@@ -1072,7 +2435,7 @@ func (sdk *SDK) fireCallback(name string, args ...interface{}) {
 							err = e
 						}
 					}
-					sdk.callbacks.OnTaskCompleted(task, result, err)
+					callbacks.OnTaskCompleted(task, result, err)
 				}
 			}
 		}
@@ -1080,26 +2443,79 @@ func (sdk *SDK) fireCallback(name string, args ...interface{}) {
 		if len(args) > 1 {
 			if intent, ok := args[0].(*Intent); ok {
 				if bid, ok := args[1].(*Bid); ok {
-					sdk.callbacks.OnBidSubmitted(intent, bid)
+					callbacks.OnBidSubmitted(intent, bid)
 				}
 			}
 		}
+	case "OnBidAccepted", "OnBidRejected":
+		ackCallbacks := exts.bidAck
+		if ackCallbacks == nil || len(args) < 3 {
+			return
+		}
+		intent, _ := args[0].(*Intent)
+		bid, _ := args[1].(*Bid)
+		ack, _ := args[2].(*BidAck)
+		if name == "OnBidAccepted" {
+			ackCallbacks.OnBidAccepted(intent, bid, ack)
+		} else {
+			ackCallbacks.OnBidRejected(intent, bid, ack)
+		}
+	case "OnHeartbeatFailed":
+		heartbeatCallbacks := exts.heartbeat
+		if heartbeatCallbacks == nil || len(args) < 2 {
+			return
+		}
+		err, _ := args[0].(error)
+		failures, _ := args[1].(int)
+		heartbeatCallbacks.OnHeartbeatFailed(err, failures)
+	case "OnReady":
+		readyCallbacks := exts.ready
+		if readyCallbacks == nil || len(args) < 1 {
+			return
+		}
+		if summary, ok := args[0].(StartupSummary); ok {
+			readyCallbacks.OnReady(summary)
+		}
+	case "OnReportDropped":
+		dropCallbacks := exts.reportDrop
+		if dropCallbacks == nil || len(args) < 2 {
+			return
+		}
+		task, _ := args[0].(*Task)
+		reportID, _ := args[1].(string)
+		dropCallbacks.OnReportDropped(task, reportID)
+	case "OnStreamReconnect":
+		reconnectCallbacks := exts.reconnect
+		if reconnectCallbacks == nil || len(args) < 2 {
+			return
+		}
+		stream, _ := args[0].(string)
+		err, _ := args[1].(error)
+		reconnectCallbacks.OnStreamReconnect(stream, err)
+	case "OnReportSubmitted":
+		ackCallbacks := exts.reportAck
+		if ackCallbacks == nil || len(args) < 2 {
+			return
+		}
+		report, _ := args[0].(*ExecutionReport)
+		receipt, _ := args[1].(*ExecutionReceipt)
+		ackCallbacks.OnReportSubmitted(report, receipt)
 	case "OnBidWon":
 		if len(args) > 0 {
 			if intentID, ok := args[0].(string); ok {
-				sdk.callbacks.OnBidWon(intentID)
+				callbacks.OnBidWon(intentID)
 			}
 		}
 	case "OnBidLost":
 		if len(args) > 0 {
 			if intentID, ok := args[0].(string); ok {
-				sdk.callbacks.OnBidLost(intentID)
+				callbacks.OnBidLost(intentID)
 			}
 		}
 	case "OnError":
 		if len(args) > 0 {
 			if err, ok := args[0].(error); ok {
-				sdk.callbacks.OnError(err)
+				callbacks.OnError(err)
 			}
 		}
 	}
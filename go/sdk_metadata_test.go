@@ -1,6 +1,8 @@
 package agentsdk
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -73,3 +75,97 @@ func TestConfigValidateChainAddressMismatch(t *testing.T) {
 		t.Fatal("expected error for mismatched chain address and private key")
 	}
 }
+
+func TestConfigValidateAddresses(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(cfg *Config)
+		errMsg string
+	}{
+		{"valid", func(cfg *Config) {
+			cfg.ValidatorAddrs = []string{"validator-2:9090", "[::1]:9090"}
+			cfg.RegistryAddr = "registry:8092"
+			cfg.AgentEndpoint = "https://agent.example.com/hooks"
+		}, ""},
+		{"matcher without port", func(cfg *Config) { cfg.MatcherAddr = "matcher" }, "matcher_addr"},
+		{"matcher with scheme", func(cfg *Config) { cfg.MatcherAddr = "http://matcher:8090" }, "without a scheme"},
+		{"validator bad port", func(cfg *Config) { cfg.ValidatorAddr = "validator:90900" }, "validator_addr"},
+		{"extra validator missing host", func(cfg *Config) { cfg.ValidatorAddrs = []string{":9090"} }, "validator_addrs"},
+		{"registry bad scheme", func(cfg *Config) {
+			cfg.RegistryAddr = "ftp://registry:8092"
+			cfg.AgentEndpoint = "agent:7000"
+		}, "registry_addr"},
+		{"endpoint missing host", func(cfg *Config) { cfg.AgentEndpoint = "http://:7000" }, "agent_endpoint"},
+		{"strict https registry without token", func(cfg *Config) {
+			cfg.RegistryAddr = "https://registry:8092"
+			cfg.AgentEndpoint = "agent:7000"
+			cfg.StrictRegistryAuth = true
+		}, "registry_token"},
+		{"strict plain http registry without token", func(cfg *Config) {
+			cfg.RegistryAddr = "registry:8092"
+			cfg.AgentEndpoint = "agent:7000"
+			cfg.StrictRegistryAuth = true
+		}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				AgentID:       "agent-1",
+				MatcherAddr:   "matcher:8090",
+				ValidatorAddr: "validator-1:9090",
+				Capabilities:  []string{"compute"},
+			}
+			tt.mutate(cfg)
+			err := cfg.Validate()
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Fatalf("unexpected validation error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidateNormalizesCapabilities(t *testing.T) {
+	cfg, err := NewConfigBuilder().
+		WithSubnetID("subnet-1").
+		WithAgentID("agent-1").
+		WithMatcherAddr("matcher:8090").
+		WithCapabilities("ml", " compute ", "", "compute").
+		AddCapability("ml").
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Capabilities, []string{"compute", "ml"}) {
+		t.Fatalf("expected trimmed, deduplicated and sorted capabilities, got %v", cfg.Capabilities)
+	}
+
+	cfg.Capabilities = []string{" ", ""}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "at least one capability") {
+		t.Fatalf("expected blank capabilities to be rejected, got %v", err)
+	}
+}
+
+func TestFilterReportMetadata(t *testing.T) {
+	src := map[string]string{"model": "v2", "debug_trace": "...", "internal_id": "42"}
+
+	allowed := filterReportMetadata(src, []string{"model", "internal_id"}, []string{"internal_id"})
+	if len(allowed) != 1 || allowed["model"] != "v2" {
+		t.Fatalf("expected only the allowed, non-denied key, got %v", allowed)
+	}
+
+	denied := filterReportMetadata(src, nil, []string{"debug_trace"})
+	if len(denied) != 2 || denied["debug_trace"] != "" {
+		t.Fatalf("expected denied key to be dropped, got %v", denied)
+	}
+
+	if unfiltered := filterReportMetadata(src, nil, nil); len(unfiltered) != 3 {
+		t.Fatalf("expected all keys without allow/deny lists, got %v", unfiltered)
+	}
+}
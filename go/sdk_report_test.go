@@ -0,0 +1,376 @@
+package agentsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestSDK(t *testing.T, mutate func(cfg *Config)) *SDK {
+	t.Helper()
+	cfg := &Config{
+		AgentID:      "agent-1",
+		MatcherAddr:  "matcher:8090",
+		Capabilities: []string{"compute"},
+	}
+	if mutate != nil {
+		mutate(cfg)
+	}
+	sdk, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error creating sdk: %v", err)
+	}
+	return sdk
+}
+
+func TestSubmitExecutionReportRejectsOversizedPayload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("oversized report should not reach the validator")
+	}))
+	defer server.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ValidatorAddr = server.Listener.Addr().String()
+		cfg.ReportMaxPayloadSize = 256
+	})
+
+	_, err := sdk.SubmitExecutionReport(context.Background(), &ExecutionReport{
+		ReportID:     "report-1",
+		AssignmentID: "task-1",
+		IntentID:     "intent-1",
+		ResultData:   []byte(strings.Repeat("x", 512)),
+	})
+	if err == nil || !strings.Contains(err.Error(), "exceeds max payload size") {
+		t.Fatalf("expected payload size error, got %v", err)
+	}
+	if failed := sdk.metrics.ReportsFailed; failed != 1 {
+		t.Fatalf("expected oversized report to be recorded as failed, got %d", failed)
+	}
+}
+
+type countingTransport struct {
+	requests atomic.Int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestSubmitExecutionReportUsesConfiguredHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"report_id":"report-1","status":"accepted"}`))
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ValidatorAddr = server.Listener.Addr().String()
+		cfg.HTTPClient = &http.Client{Transport: transport}
+		cfg.HTTPTimeout = time.Minute
+	})
+
+	if _, err := sdk.SubmitExecutionReport(context.Background(), &ExecutionReport{ReportID: "report-1", AssignmentID: "task-1", IntentID: "intent-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if transport.requests.Load() != 1 {
+		t.Fatalf("expected the report to go through the configured client, got %d requests", transport.requests.Load())
+	}
+	if sdk.httpClient.Timeout != 0 {
+		t.Fatal("expected an explicit client to take precedence over HTTPTimeout")
+	}
+
+	if timeout := newTestSDK(t, nil).httpClient.Timeout; timeout != defaultHTTPTimeout {
+		t.Fatalf("expected default HTTP timeout %v, got %v", defaultHTTPTimeout, timeout)
+	}
+	if timeout := newTestSDK(t, func(cfg *Config) { cfg.HTTPTimeout = 3 * time.Second }).httpClient.Timeout; timeout != 3*time.Second {
+		t.Fatalf("expected configured HTTP timeout, got %v", timeout)
+	}
+}
+
+func TestSubmitExecutionReportRetriesFailedAttempts(t *testing.T) {
+	var calls int32
+	keys := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys <- r.Header.Get(IdempotencyKeyHeader)
+		if atomic.AddInt32(&calls, 1) < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"report_id":"report-1","status":"accepted"}`))
+	}))
+	defer server.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ValidatorAddr = server.Listener.Addr().String()
+		cfg.ReportMaxRetries = 2
+		cfg.ReportRetryBackoff = time.Millisecond
+	})
+
+	report := &ExecutionReport{ReportID: "report-1", AssignmentID: "task-1", IntentID: "intent-1"}
+	if _, err := sdk.SubmitExecutionReport(context.Background(), report); err != nil {
+		t.Fatalf("expected report to succeed on the third attempt, got %v", err)
+	}
+
+	snapshot := sdk.metrics.Snapshot()
+	if snapshot.ReportRetries != 2 || snapshot.ReportRetryExhausted != 0 {
+		t.Fatalf("unexpected retry metrics %+v", snapshot)
+	}
+	if snapshot.ReportsSubmitted != 1 || snapshot.ReportsFailed != 0 {
+		t.Fatalf("retries should not distort success/failure counters: %+v", snapshot)
+	}
+	for i := 0; i < 3; i++ {
+		if key := <-keys; key != "report-1" {
+			t.Fatalf("expected every attempt keyed by the report ID, got %q", key)
+		}
+	}
+	for endpoint, buckets := range sdk.metrics.ReportAttemptHistogram() {
+		if buckets[3] != 1 || len(buckets) != 1 {
+			t.Fatalf("expected one report needing 3 attempts at %s, got %v", endpoint, buckets)
+		}
+	}
+
+	atomic.StoreInt32(&calls, -10)
+	if _, err := sdk.SubmitExecutionReport(context.Background(), report); err == nil {
+		t.Fatal("expected report to fail once retries are exhausted")
+	}
+	snapshot = sdk.metrics.Snapshot()
+	if snapshot.ReportRetryExhausted != 1 || snapshot.ReportsFailed != 1 {
+		t.Fatalf("expected exhausted retry to count one failure, got %+v", snapshot)
+	}
+}
+
+func TestSubmitWithRetryCancelledDuringBackoffIsNotExhausted(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ReportMaxRetries = 3
+		cfg.ReportRetryBackoff = time.Hour
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int
+	submitErr := errors.New("unavailable")
+	time.AfterFunc(20*time.Millisecond, cancel)
+	err := sdk.submitWithRetry(ctx, "validator-1:9090", nil, func(context.Context) error {
+		calls++
+		return submitErr
+	})
+	if !errors.Is(err, submitErr) || calls != 1 {
+		t.Fatalf("expected the first attempt's error after cancellation, got %v after %d calls", err, calls)
+	}
+	snapshot := sdk.metrics.Snapshot()
+	if snapshot.ReportRetries != 1 || snapshot.ReportRetryExhausted != 0 {
+		t.Fatalf("cancellation must not count as exhausted retries, got %+v", snapshot)
+	}
+	if buckets := sdk.metrics.ReportAttemptHistogram()["validator-1:9090"]; buckets[1] != 1 {
+		t.Fatalf("expected the single attempt to be recorded, got %v", buckets)
+	}
+}
+
+func TestValidatorReportEndpointsAppliesWeights(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ValidatorAddr = "validator-1:8080"
+		cfg.ValidatorAddrs = []string{"canary:8080", "retired:8080"}
+		cfg.ValidatorWeights = map[string]float64{"canary:8080": 0.1, "retired:8080": 0}
+	})
+
+	sdk.sampleFloat = func() float64 { return 0.5 }
+	endpoints, errs := sdk.validatorReportEndpoints(context.Background())
+	if len(errs) != 0 || len(endpoints) != 1 || !strings.Contains(endpoints[0], "validator-1") {
+		t.Fatalf("expected only the unweighted validator, got %v %v", endpoints, errs)
+	}
+
+	sdk.sampleFloat = func() float64 { return 0.05 }
+	endpoints, _ = sdk.validatorReportEndpoints(context.Background())
+	if len(endpoints) != 2 {
+		t.Fatalf("expected the canary to be sampled in, got %v", endpoints)
+	}
+}
+
+func TestValidatorReportEndpointsKeepsSampledOutWhenNoneRemain(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ValidatorAddr = "canary:8080"
+		cfg.ValidatorWeights = map[string]float64{"canary:8080": 0.1}
+	})
+	sdk.sampleFloat = func() float64 { return 0.9 }
+
+	if endpoints, _ := sdk.validatorReportEndpoints(context.Background()); len(endpoints) != 1 {
+		t.Fatalf("expected the only validator to be kept, got %v", endpoints)
+	}
+}
+
+func TestValidatorReportEndpointsSkipInactiveAndStaleValidators(t *testing.T) {
+	now := time.Now().Unix()
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"validators":[
+			{"id":"v1","endpoint":"live:8080","status":"active","last_seen":%d},
+			{"id":"v2","endpoint":"stale:8080","status":"active","last_seen":%d},
+			{"id":"v3","endpoint":"down:8080","status":"inactive","last_seen":%d}]}`, now, now-3600, now)
+	}))
+	defer registry.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.RegistryAddr = registry.URL
+		cfg.AgentEndpoint = "http://agent:9000"
+	})
+
+	all, err := sdk.DiscoverValidators(context.Background())
+	if err != nil || len(all) != 3 {
+		t.Fatalf("expected every registered validator, got %v %v", all, err)
+	}
+	active, err := sdk.DiscoverActiveValidators(context.Background(), time.Minute)
+	if err != nil || len(active) != 1 || active[0].ID != "v1" {
+		t.Fatalf("expected only the live validator, got %v %v", active, err)
+	}
+
+	endpoints, errs := sdk.validatorReportEndpoints(context.Background())
+	if len(errs) != 0 || len(endpoints) != 1 || !strings.Contains(endpoints[0], "live") {
+		t.Fatalf("expected reports to target only the live validator, got %v %v", endpoints, errs)
+	}
+}
+
+func TestSubmitExecutionReportFansOutConcurrently(t *testing.T) {
+	slow := func(status int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+			if status != http.StatusOK {
+				http.Error(w, "unavailable", status)
+				return
+			}
+			w.Write([]byte(`{"report_id":"report-1","status":"accepted","phase":"VALIDATING"}`))
+		}))
+	}
+	up1, up2, down := slow(http.StatusOK), slow(http.StatusOK), slow(http.StatusServiceUnavailable)
+	defer up1.Close()
+	defer up2.Close()
+	defer down.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ValidatorAddr = up1.Listener.Addr().String()
+		cfg.ValidatorAddrs = []string{up2.Listener.Addr().String(), down.Listener.Addr().String()}
+	})
+
+	start := time.Now()
+	receipts, err := sdk.SubmitExecutionReport(context.Background(), &ExecutionReport{ReportID: "report-1", AssignmentID: "task-1", IntentID: "intent-1"})
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Fatalf("expected validators to be posted concurrently, took %v", elapsed)
+	}
+	if len(receipts) != 2 || err == nil || !strings.Contains(err.Error(), down.Listener.Addr().String()) {
+		t.Fatalf("expected two receipts and an error naming the failed validator, got %v %v", receipts, err)
+	}
+	if receipts[0].Phase != ReportPhaseValidating || receipts[0].Final() {
+		t.Fatalf("expected a validating, non-final receipt, got %+v", receipts[0])
+	}
+	if receipts[0].Endpoint > receipts[1].Endpoint {
+		t.Fatalf("expected receipts in endpoint order, got %s then %s", receipts[0].Endpoint, receipts[1].Endpoint)
+	}
+}
+
+func TestSubmitExecutionReportKeepsReceiptsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	served := make(chan struct{})
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"report_id":"report-1","status":"accepted"}`))
+		close(served)
+	}))
+	defer fast.Close()
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		<-served
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer hung.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ValidatorAddrs = []string{fast.Listener.Addr().String(), hung.Listener.Addr().String()}
+	})
+
+	receipts, err := sdk.SubmitExecutionReport(ctx, &ExecutionReport{ReportID: "report-1", AssignmentID: "task-1", IntentID: "intent-1"})
+	if len(receipts) != 1 || receipts[0].Endpoint == "" || !strings.Contains(receipts[0].Endpoint, fast.Listener.Addr().String()) {
+		t.Fatalf("expected the receipt collected before cancellation, got %v", receipts)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled alongside the receipt, got %v", err)
+	}
+}
+
+func TestSubmitExecutionReportFiresReportSubmitted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"report_id":"report-1","validator_id":"validator-1","status":"accepted"}`))
+	}))
+	defer server.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ValidatorAddr = server.Listener.Addr().String()
+	})
+	callbacks := &reportAckCallbacks{}
+	sdk.RegisterCallbacks(callbacks)
+
+	receipts, err := sdk.SubmitExecutionReport(context.Background(), &ExecutionReport{ReportID: "report-1", AssignmentID: "task-1", IntentID: "intent-1"})
+	if err != nil || len(receipts) != 1 || receipts[0].ValidatorID != "validator-1" {
+		t.Fatalf("unexpected submission result %v %v", receipts, err)
+	}
+	if want := []string{"report-1@" + receipts[0].Endpoint}; !reflect.DeepEqual(callbacks.acks, want) {
+		t.Fatalf("expected the acknowledgement with the validator endpoint, got %v", callbacks.acks)
+	}
+}
+
+func TestBuildExecutionReportURL(t *testing.T) {
+	tests := []struct {
+		endpoint, scheme, reportPath, want string
+	}{
+		{"validator:8080", "http", defaultReportPath, "http://validator:8080/api/v1/execution-report"},
+		{"https://validator:8080/", "http", defaultReportPath, "https://validator:8080/api/v1/execution-report"},
+		{"validator:8080/api/v1/execution-report/", "http", defaultReportPath, "http://validator:8080/api/v1/execution-report"},
+		{"https://gateway/validators/1", "http", "/reports/submit", "https://gateway/validators/1/reports/submit"},
+		{"gateway/reports/submit", "http", "/reports/submit/", "http://gateway/reports/submit"},
+		{"gateway/custom", "http", "/", "http://gateway/custom"},
+		// The default scheme only applies when the endpoint has none
+		{"validator", "https", defaultReportPath, "https://validator/api/v1/execution-report"},
+		{"validator:9443", "https", defaultReportPath, "https://validator:9443/api/v1/execution-report"},
+		{"http://validator:8080", "https", defaultReportPath, "http://validator:8080/api/v1/execution-report"},
+		{"https://validator:9443/v2", "https", defaultReportPath, "https://validator:9443/v2/api/v1/execution-report"},
+	}
+	for _, tt := range tests {
+		got, err := buildExecutionReportURL(tt.endpoint, tt.scheme, tt.reportPath)
+		if err != nil || got != tt.want {
+			t.Errorf("buildExecutionReportURL(%q, %q, %q) = %q, %v; want %q", tt.endpoint, tt.scheme, tt.reportPath, got, err, tt.want)
+		}
+	}
+
+	if _, err := New(&Config{AgentID: "agent-1", MatcherAddr: "matcher:8090", Capabilities: []string{"compute"}, ReportScheme: "ftp"}); err == nil {
+		t.Fatal("expected an unsupported report scheme to be rejected")
+	}
+}
+
+func TestSubmitExecutionReportPostsToReportPath(t *testing.T) {
+	paths := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+		w.Write([]byte(`{"report_id":"report-1","status":"accepted"}`))
+	}))
+	defer server.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ValidatorAddr = server.Listener.Addr().String()
+		cfg.ReportPath = "gateway/reports"
+	})
+	if _, err := sdk.SubmitExecutionReport(context.Background(), &ExecutionReport{ReportID: "report-1", AssignmentID: "task-1", IntentID: "intent-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path := <-paths; path != "/gateway/reports" {
+		t.Fatalf("expected the report at /gateway/reports, got %s", path)
+	}
+}
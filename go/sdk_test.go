@@ -0,0 +1,594 @@
+package agentsdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "subnet/proto/subnet"
+)
+
+type readyCallbacks struct {
+	recordingCallbacks
+	summaries []StartupSummary
+}
+
+func (c *readyCallbacks) OnReady(summary StartupSummary) {
+	c.summaries = append(c.summaries, summary)
+}
+
+func TestStartupSummaryReflectsEffectiveConfig(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.Identity = &IdentityConfig{SubnetID: "0x1", AgentID: "agent-1"}
+		cfg.PrivateKey = testPrivateKey
+		cfg.ValidatorAddr = "validator-1:9090"
+		cfg.ValidatorAddrs = []string{"validator-2:9090"}
+		cfg.RegistryAddr = "http://registry:8080"
+		cfg.AgentEndpoint = "http://agent:9000"
+		cfg.MaxConcurrentTasksPerType = map[string]int{"ml": 2}
+	})
+	callbacks := &readyCallbacks{}
+	sdk.RegisterCallbacks(callbacks)
+
+	summary := sdk.startupSummary()
+	sdk.fireCallback("OnReady", summary)
+
+	if len(callbacks.summaries) != 1 {
+		t.Fatalf("expected one OnReady call, got %d", len(callbacks.summaries))
+	}
+	got := callbacks.summaries[0]
+	if got.AgentID != "agent-1" || got.SubnetID != "0x1" || got.SigningAddress != sdk.GetAddress() {
+		t.Fatalf("unexpected identity in summary %+v", got)
+	}
+	if !reflect.DeepEqual(got.ValidatorAddrs, []string{"validator-1:9090", "validator-2:9090"}) || got.ValidatorDiscovery != "registry" {
+		t.Fatalf("unexpected validator targets in summary %+v", got)
+	}
+	if got.MaxConcurrentTasks != 5 || got.TaskTypeLimits["ml"] != 2 || got.ReportOverflow != ReportOverflowBlock || got.Tracing {
+		t.Fatalf("expected defaults in summary, got %+v", got)
+	}
+}
+
+type heartbeatCallbacks struct {
+	recordingCallbacks
+	hbMu     sync.Mutex
+	failures []int
+}
+
+func (c *heartbeatCallbacks) OnHeartbeatFailed(err error, consecutiveFailures int) {
+	c.hbMu.Lock()
+	defer c.hbMu.Unlock()
+	c.failures = append(c.failures, consecutiveFailures)
+}
+
+func TestHeartbeatFailuresTriggerReRegistration(t *testing.T) {
+	var registrations, heartbeats atomic.Int32
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/agents":
+			registrations.Add(1)
+		case strings.HasSuffix(r.URL.Path, "/heartbeat"):
+			// The registry is unhealthy until the agent registers again
+			if registrations.Load() == 0 {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			heartbeats.Add(1)
+		}
+	}))
+	defer registry.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.RegistryAddr = registry.URL
+		cfg.AgentEndpoint = "http://agent:9000"
+		cfg.RegistryHeartbeatInterval = 5 * time.Millisecond
+		cfg.HeartbeatFailureThreshold = 2
+	})
+	callbacks := &heartbeatCallbacks{}
+	sdk.RegisterCallbacks(callbacks)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sdk.registryWG.Add(1)
+	go sdk.heartbeatLoop(ctx)
+	deadline := time.Now().Add(2 * time.Second)
+	for heartbeats.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	sdk.registryWG.Wait()
+
+	if registrations.Load() != 1 || heartbeats.Load() == 0 {
+		t.Fatalf("expected one re-registration followed by heartbeats, got %d registrations and %d heartbeats", registrations.Load(), heartbeats.Load())
+	}
+	if !reflect.DeepEqual(callbacks.failures, []int{1, 2}) || len(callbacks.errs) != 1 {
+		t.Fatalf("expected OnHeartbeatFailed for each failure and one OnError, got %v and %v", callbacks.failures, callbacks.errs)
+	}
+}
+
+func TestHeartbeatNotFoundReregistersWithBackoff(t *testing.T) {
+	var registrations, heartbeats atomic.Int32
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/agents":
+			// The registry is still coming up for the first attempt
+			if registrations.Add(1) == 1 {
+				http.Error(w, "starting", http.StatusServiceUnavailable)
+			}
+		case strings.HasSuffix(r.URL.Path, "/heartbeat"):
+			// The registry restarted and forgot the agent until it registers again
+			if registrations.Load() < 2 {
+				http.Error(w, "unknown agent", http.StatusNotFound)
+				return
+			}
+			heartbeats.Add(1)
+		}
+	}))
+	defer registry.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.RegistryAddr = registry.URL
+		cfg.AgentEndpoint = "http://agent:9000"
+		cfg.RegistryHeartbeatInterval = 5 * time.Millisecond
+	})
+	callbacks := &heartbeatCallbacks{}
+	sdk.RegisterCallbacks(callbacks)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sdk.registryWG.Add(1)
+	go sdk.heartbeatLoop(ctx)
+	deadline := time.Now().Add(2 * time.Second)
+	for heartbeats.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	sdk.registryWG.Wait()
+
+	if registrations.Load() != 2 || heartbeats.Load() == 0 {
+		t.Fatalf("expected a retried re-registration followed by heartbeats, got %d registrations and %d heartbeats", registrations.Load(), heartbeats.Load())
+	}
+	if !reflect.DeepEqual(callbacks.failures, []int{1}) || len(callbacks.errs) != 0 {
+		t.Fatalf("expected one heartbeat failure and no OnError, got %v and %v", callbacks.failures, callbacks.errs)
+	}
+}
+
+func TestRegistryRequestsCarryBearerToken(t *testing.T) {
+	var mu sync.Mutex
+	auth := make(map[string]string)
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		auth[r.Method+" "+r.URL.Path] = r.Header.Get("Authorization")
+		mu.Unlock()
+		if r.URL.Path == "/validators" {
+			w.Write([]byte(`{"validators":[]}`))
+		}
+	}))
+	defer registry.Close()
+
+	var issued atomic.Int32
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.RegistryAddr = registry.URL
+		cfg.AgentEndpoint = "http://agent:9000"
+		cfg.RegistryToken = "static"
+		cfg.RegistryTokenProvider = func(ctx context.Context) (string, error) {
+			return fmt.Sprintf("rotated-%d", issued.Add(1)), nil
+		}
+	})
+
+	ctx := context.Background()
+	if err := sdk.postRegistration(ctx); err != nil {
+		t.Fatalf("unexpected registration error: %v", err)
+	}
+	if err := sdk.sendHeartbeat(ctx); err != nil {
+		t.Fatalf("unexpected heartbeat error: %v", err)
+	}
+	if _, err := sdk.DiscoverValidators(ctx); err != nil {
+		t.Fatalf("unexpected discovery error: %v", err)
+	}
+	sdk.stopRegistry()
+
+	want := map[string]string{
+		"POST /agents":                   "Bearer rotated-1",
+		"POST /agents/agent-1/heartbeat": "Bearer rotated-2",
+		"GET /validators":                "Bearer rotated-3",
+		"DELETE /agents/agent-1":         "Bearer rotated-4",
+	}
+	if !reflect.DeepEqual(auth, want) {
+		t.Fatalf("expected the provider's token on every registry request, got %v", auth)
+	}
+}
+
+func TestRegistrationInfoFromRegistryResponse(t *testing.T) {
+	var heartbeatPath atomic.Value
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/agents":
+			w.Write([]byte(`{"id":"agent-7f3","capabilities":["compute"],"permissions":["bid"],"expires_at":1700000000}`))
+		case strings.HasSuffix(r.URL.Path, "/heartbeat"):
+			heartbeatPath.Store(r.URL.Path)
+		}
+	}))
+	defer registry.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.RegistryAddr = registry.URL
+		cfg.AgentEndpoint = "http://agent:9000"
+	})
+	if sdk.RegistrationInfo() != nil {
+		t.Fatal("expected no registration info before registering")
+	}
+
+	ctx := context.Background()
+	if err := sdk.postRegistration(ctx); err != nil {
+		t.Fatalf("unexpected registration error: %v", err)
+	}
+	info := sdk.RegistrationInfo()
+	if info == nil || info.AgentID != "agent-7f3" || !reflect.DeepEqual(info.Capabilities, []string{"compute"}) ||
+		!reflect.DeepEqual(info.Permissions, []string{"bid"}) || info.ExpiresAt.Unix() != 1700000000 || info.RegisteredAt.IsZero() {
+		t.Fatalf("unexpected registration info %+v", info)
+	}
+
+	if err := sdk.sendHeartbeat(ctx); err != nil {
+		t.Fatalf("unexpected heartbeat error: %v", err)
+	}
+	if path := heartbeatPath.Load(); path != "/agents/agent-7f3/heartbeat" {
+		t.Fatalf("expected heartbeats under the assigned ID, got %v", path)
+	}
+}
+
+func TestStartWithOptionalRegistryRetriesRegistration(t *testing.T) {
+	var registrations, heartbeats atomic.Int32
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/agents" && r.Method == http.MethodPost:
+			// Down when the agent starts, back shortly after
+			if registrations.Add(1) <= 2 {
+				http.Error(w, "starting", http.StatusServiceUnavailable)
+			}
+		case strings.HasSuffix(r.URL.Path, "/heartbeat"):
+			heartbeats.Add(1)
+		}
+	}))
+	defer registry.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.RegistryAddr = registry.URL
+		cfg.AgentEndpoint = "http://agent:9000"
+		cfg.RegistryHeartbeatInterval = 5 * time.Millisecond
+		cfg.RegistryOptional = true
+	})
+	sdk.RegisterHandler(namedHandler("handler"))
+	if err := sdk.Start(); err != nil {
+		t.Fatalf("expected Start to succeed without the registry, got %v", err)
+	}
+	defer sdk.Stop()
+	if sdk.RegistrationInfo() != nil {
+		t.Fatal("expected the agent to start unregistered")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for heartbeats.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if sdk.RegistrationInfo() == nil || heartbeats.Load() == 0 {
+		t.Fatalf("expected background registration followed by heartbeats, got %d registrations and %d heartbeats", registrations.Load(), heartbeats.Load())
+	}
+}
+
+func TestUnregisterPausesHeartbeatsUntilReregister(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	var heartbeats atomic.Int32
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/heartbeat") {
+			heartbeats.Add(1)
+			return
+		}
+		mu.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+	}))
+	defer registry.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.RegistryAddr = registry.URL
+		cfg.AgentEndpoint = "http://agent:9000"
+		cfg.RegistryHeartbeatInterval = 5 * time.Millisecond
+	})
+	ctx := context.Background()
+	if err := sdk.Unregister(ctx); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("expected ErrNotRunning before Start, got %v", err)
+	}
+	sdk.RegisterHandler(namedHandler("handler"))
+	if err := sdk.Start(); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+
+	waitForHeartbeat := func() {
+		t.Helper()
+		start := heartbeats.Load()
+		deadline := time.Now().Add(2 * time.Second)
+		for heartbeats.Load() == start && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if heartbeats.Load() == start {
+			t.Fatal("expected heartbeats to be sent")
+		}
+	}
+	waitForHeartbeat()
+
+	if err := sdk.Unregister(ctx); err != nil {
+		t.Fatalf("unexpected unregister error: %v", err)
+	}
+	if sdk.RegistrationInfo() != nil {
+		t.Fatal("expected no registration after Unregister")
+	}
+	// A heartbeat cancelled by Unregister can still reach the server; let it land first
+	time.Sleep(10 * time.Millisecond)
+	paused := heartbeats.Load()
+	time.Sleep(30 * time.Millisecond)
+	if heartbeats.Load() != paused {
+		t.Fatal("expected heartbeats to pause while unregistered")
+	}
+
+	if err := sdk.Reregister(ctx); err != nil {
+		t.Fatalf("unexpected reregister error: %v", err)
+	}
+	waitForHeartbeat()
+	if err := sdk.Stop(); err != nil {
+		t.Fatalf("unexpected stop error: %v", err)
+	}
+
+	want := []string{"POST /agents", "DELETE /agents/agent-1", "POST /agents", "DELETE /agents/agent-1"}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("expected registry calls %v, got %v", want, calls)
+	}
+}
+
+func TestHeartbeatReportsAgentState(t *testing.T) {
+	payloads := make(chan heartbeatPayload, 1)
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload heartbeatPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode heartbeat: %v", err)
+		}
+		payloads <- payload
+	}))
+	defer registry.Close()
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.RegistryAddr = registry.URL
+		cfg.AgentEndpoint = "http://agent:9000"
+		cfg.HeartbeatMetadata = map[string]string{"region": "eu-west"}
+	})
+	sdk.running.Store(true)
+	sdk.inFlightTasks.Add(2)
+
+	if err := sdk.sendHeartbeat(context.Background()); err != nil {
+		t.Fatalf("unexpected heartbeat error: %v", err)
+	}
+	want := heartbeatPayload{
+		Status:        "healthy",
+		InFlightTasks: 2,
+		Capabilities:  []string{"compute"},
+		Metadata:      map[string]string{"region": "eu-west"},
+	}
+	if got := <-payloads; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected heartbeat %+v, got %+v", want, got)
+	}
+
+	sdk.running.Store(false)
+	if err := sdk.sendHeartbeat(context.Background()); err != nil {
+		t.Fatalf("unexpected heartbeat error: %v", err)
+	}
+	if got := <-payloads; got.Status != "draining" {
+		t.Fatalf("expected a stopping agent to report draining, got %q", got.Status)
+	}
+}
+
+type partialCallbacks struct {
+	BaseCallbacks
+	reconnects []string
+	dropped    []string
+}
+
+func (c *partialCallbacks) OnStreamReconnect(stream string, err error) {
+	c.reconnects = append(c.reconnects, stream)
+}
+
+func (c *partialCallbacks) OnReportDropped(task *Task, reportID string) {
+	c.dropped = append(c.dropped, reportID)
+}
+
+func TestOptionalCallbacksDetectedAtRegistration(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	callbacks := &partialCallbacks{}
+	sdk.RegisterCallbacks(callbacks)
+
+	exts := sdk.callbackExts
+	if exts.reconnect == nil || exts.reportDrop == nil || exts.bidAck != nil || exts.heartbeat != nil {
+		t.Fatalf("unexpected optional callbacks detected: %+v", exts)
+	}
+
+	sdk.handleStreamFailure(context.Background(), "task", nil, new(int), newReconnectBackoff(time.Millisecond, time.Millisecond), time.Now())
+	sdk.fireCallback("OnReportDropped", &Task{ID: "task-1"}, "report-1")
+	sdk.fireCallback("OnHeartbeatFailed", context.DeadlineExceeded, 1)
+
+	if !reflect.DeepEqual(callbacks.reconnects, []string{"task"}) || !reflect.DeepEqual(callbacks.dropped, []string{"report-1"}) {
+		t.Fatalf("expected reconnect and drop events, got %v and %v", callbacks.reconnects, callbacks.dropped)
+	}
+}
+
+type stopSignalCallbacks struct {
+	BaseCallbacks
+	stopped chan struct{}
+}
+
+func (c *stopSignalCallbacks) OnStop() error {
+	close(c.stopped)
+	return nil
+}
+
+func TestStartContextStopsWhenContextCancelled(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	handler := &blockingHandler{release: make(chan struct{})}
+	close(handler.release)
+	callbacks := &stopSignalCallbacks{stopped: make(chan struct{})}
+	sdk.RegisterHandler(handler)
+	sdk.RegisterCallbacks(callbacks)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := sdk.StartContext(cancelled); err == nil {
+		t.Fatal("expected an already cancelled context to be rejected")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := sdk.StartContext(ctx); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	cancel()
+
+	select {
+	case <-callbacks.stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected cancelling the start context to stop the SDK")
+	}
+	if err := sdk.Stop(); err == nil || err.Error() != "SDK not running" {
+		t.Fatalf("expected the SDK to be stopped already, got %v", err)
+	}
+}
+
+func TestUptimeResetsAcrossRestarts(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	handler := &blockingHandler{release: make(chan struct{})}
+	close(handler.release)
+	sdk.RegisterHandler(handler)
+	if !sdk.StartedAt().IsZero() || sdk.Uptime() != 0 {
+		t.Fatal("expected no start time before Start")
+	}
+
+	if err := sdk.Start(); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	firstStart := sdk.StartedAt()
+	if firstStart.IsZero() || sdk.Uptime() <= 0 {
+		t.Fatalf("expected a start time and uptime once started, got %v %v", firstStart, sdk.Uptime())
+	}
+	if err := sdk.Stop(); err != nil {
+		t.Fatalf("unexpected stop error: %v", err)
+	}
+	if !sdk.StartedAt().IsZero() || sdk.Uptime() != 0 {
+		t.Fatal("expected the start time to clear on Stop")
+	}
+
+	time.Sleep(time.Millisecond)
+	if err := sdk.Start(); err != nil {
+		t.Fatalf("unexpected restart error: %v", err)
+	}
+	defer sdk.Stop()
+	if !sdk.StartedAt().After(firstStart) {
+		t.Fatalf("expected the restart to reset the start time, got %v after %v", sdk.StartedAt(), firstStart)
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+
+	if _, err := sdk.ExecuteTask(context.Background(), &Task{ID: "task-1"}); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("expected ErrNotRunning, got %v", err)
+	}
+	sdk.running.Store(true)
+	if _, err := sdk.ExecuteTask(context.Background(), &Task{ID: "task-1"}); !errors.Is(err, ErrNoHandler) {
+		t.Fatalf("expected ErrNoHandler, got %v", err)
+	}
+	sdk.running.Store(false)
+
+	if _, err := sdk.Sign([]byte("payload")); !errors.Is(err, ErrNoPrivateKey) {
+		t.Fatalf("expected ErrNoPrivateKey, got %v", err)
+	}
+
+	_, err := sdk.SubmitExecutionReport(context.Background(), &ExecutionReport{
+		ReportID:     "report-1",
+		AssignmentID: "task-1",
+		IntentID:     "intent-1",
+		Status:       ExecutionReportStatusSuccess,
+	})
+	if !errors.Is(err, ErrNoValidators) {
+		t.Fatalf("expected ErrNoValidators, got %v", err)
+	}
+}
+
+type decliningStrategy struct{}
+
+func (decliningStrategy) ShouldBid(intent *Intent) bool    { return false }
+func (decliningStrategy) CalculateBid(intent *Intent) *Bid { return nil }
+
+// Run with -race: handlers, strategies and callbacks may be swapped while tasks and intents are in flight
+func TestRegistrationWhileRunningIsRaceFree(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	sdk.matcherClient = &MatcherClient{client: &fakeMatcherService{}, logger: sdk.logger}
+	sdk.RegisterHandler(namedHandler("initial"))
+	sdk.running.Store(true)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			sdk.RegisterHandler(namedHandler("swapped"))
+			sdk.RegisterHandlerForType("ml", namedHandler("ml"))
+			sdk.RegisterBiddingStrategy(decliningStrategy{})
+			sdk.RegisterCallbacks(&BaseCallbacks{})
+			sdk.Use(func(next Handler) Handler { return next })
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		ctx := context.Background()
+		for i := 0; i < 200; i++ {
+			sdk.handleExecutionTask(ctx, &pb.ExecutionTask{TaskId: fmt.Sprintf("task-%d", i), IntentType: "ml.inference"})
+			sdk.handleIntentUpdate(ctx, &pb.MatcherIntentUpdate{IntentId: fmt.Sprintf("intent-%d", i)})
+		}
+	}()
+	wg.Wait()
+}
+
+// Run with -race: the task paths check running while Stop clears it
+func TestStopWhileTasksStreamIsRaceFree(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	sdk.RegisterHandler(namedHandler("handler"))
+	if err := sdk.Start(); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			sdk.handleExecutionTask(context.Background(), &pb.ExecutionTask{TaskId: fmt.Sprintf("task-%d", i)})
+			sdk.ExecuteTask(context.Background(), &Task{ID: fmt.Sprintf("direct-%d", i)})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if err := sdk.Stop(); err != nil {
+			t.Errorf("unexpected stop error: %v", err)
+		}
+	}()
+	wg.Wait()
+
+	if sdk.running.Load() {
+		t.Fatal("expected the SDK to be stopped")
+	}
+}
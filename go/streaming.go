@@ -2,32 +2,53 @@ package agentsdk
 
 import (
 	"context"
-	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
+	"io"
 	"time"
 
+	"google.golang.org/protobuf/proto"
 	pb "subnet/proto/subnet"
 )
 
-// startMatcherStreams starts task and intent streaming
-func (sdk *SDK) startMatcherStreams() error {
+// startMatcherStreams starts task and intent streaming bound to parent. The caller holds sdk.mu.
+func (sdk *SDK) startMatcherStreams(parent context.Context) error {
 	if sdk.matcherClient == nil {
 		return nil
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(parent)
 	sdk.matcherCancel = cancel
 
+	// Tasks run on their own context, detached from parent's cancellation, so Stop can drain
+	// them after the streams close
+	taskCtx, taskCancel := context.WithCancel(context.WithoutCancel(parent))
+	sdk.taskCancel = taskCancel
+
 	// Start task streaming
 	sdk.matcherWG.Add(1)
-	go sdk.taskStreamLoop(ctx)
+	go sdk.taskStreamLoop(ctx, taskCtx)
+
+	if sdk.validatorClient != nil {
+		sdk.startReportWorkers(taskCtx)
+	}
+
+	// Re-submit results persisted before a restart
+	if sdk.resultStore != nil && sdk.validatorClient != nil {
+		sdk.matcherWG.Add(1)
+		go sdk.replayPersistedResults(ctx)
+	}
 
 	// Start intent streaming if bidding strategy is registered
-	if sdk.biddingStrategy != nil {
+	if sdk.currentBiddingStrategy() != nil {
 		sdk.matcherWG.Add(1)
 		go sdk.intentStreamLoop(ctx)
+
+		if sdk.config.bidBatchingEnabled() {
+			sdk.matcherWG.Add(1)
+			go sdk.bidBatchLoop(ctx)
+		}
 	}
 
 	return nil
@@ -42,9 +63,118 @@ func (sdk *SDK) stopMatcherStreams() {
 	}
 }
 
-// taskStreamLoop handles incoming execution tasks
-func (sdk *SDK) taskStreamLoop(ctx context.Context) {
+// handleStreamFailure applies the reconnect policy after a matcher stream ends and reports whether the
+// loop should keep going. Rejected subscriptions are retried at most MatcherSubscriptionAttempts times
+// in a row before the loop gives up; transient errors and clean closes reconnect with backoff.
+func (sdk *SDK) handleStreamFailure(ctx context.Context, stream string, err error, rejections *int, backoff *reconnectBackoff, connectedAt time.Time) bool {
+	var subscriptionErr *StreamSubscriptionError
+	switch {
+	case err == nil:
+		*rejections = 0
+		sdk.logger.Info("Matcher stream closed, reconnecting", "stream", stream)
+	case errors.As(err, &subscriptionErr):
+		*rejections++
+		if *rejections >= sdk.config.MatcherSubscriptionAttempts {
+			err = fmt.Errorf("giving up after %d rejected subscription attempts: %w", *rejections, err)
+			sdk.logger.Error("Matcher stream subscription rejected, not retrying", "stream", stream, "error", err)
+			sdk.fireCallback("OnError", err)
+			return false
+		}
+		sdk.logger.Warn("Matcher stream subscription rejected, retrying", "stream", stream,
+			"attempt", *rejections, "max_attempts", sdk.config.MatcherSubscriptionAttempts, "error", err)
+		sdk.fireCallback("OnError", err)
+	default:
+		*rejections = 0
+		sdk.logger.Warn("Matcher stream error, reconnecting", "stream", stream, "error", err)
+		sdk.fireCallback("OnError", err)
+	}
+
+	sdk.streamStateFor(stream).reconnecting(time.Since(connectedAt))
+	sdk.fireCallback("OnStreamReconnect", stream, err)
+	return backoff.wait(ctx, connectedAt)
+}
+
+// drainTasks waits up to timeout for in-flight tasks and queued reports to finish,
+// then cancels whatever is still running
+func (sdk *SDK) drainTasks(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		sdk.taskWG.Wait()
+		close(done)
+	}()
+
+	// Flush partially filled report batches instead of waiting for the flush interval
+	if sdk.reportDrain != nil {
+		close(sdk.reportDrain)
+		sdk.reportDrain = nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		sdk.logger.Warn("Shutdown timeout elapsed, abandoning unfinished work", "timeout", timeout,
+			"in_flight_tasks", sdk.inFlightTasks.Load(), "queued_reports", len(sdk.reportQueue))
+	}
+
+	if sdk.taskCancel != nil {
+		sdk.taskCancel()
+		sdk.taskCancel = nil
+	}
+	sdk.reportWG.Wait()
+}
+
+// reserveStreamSlot waits until the agent can take another streamed task: MaxConcurrentTasks running
+// plus TaskQueueDepth waiting for a slot. It returns false when ctx is done first.
+func (sdk *SDK) reserveStreamSlot(ctx context.Context) bool {
+	select {
+	case sdk.streamSlots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// grpcClients is a snapshot of the SDK's gRPC clients taken by clients. Stop nils the fields
+// under sdk.mu while tasks, batch workers and API calls may still be running, so those paths read
+// the clients once through clients and use the copy instead of the SDK fields.
+type grpcClients struct {
+	matcher    *MatcherClient
+	validator  *ValidatorClient
+	validators []validatorTarget
+}
+
+// clients returns the current gRPC clients. All fields are nil once Stop has closed them. Callers
+// that already hold sdk.mu read the fields directly instead.
+func (sdk *SDK) clients() grpcClients {
+	sdk.mu.RLock()
+	defer sdk.mu.RUnlock()
+	return grpcClients{
+		matcher:    sdk.matcherClient,
+		validator:  sdk.validatorClient,
+		validators: sdk.validators,
+	}
+}
+
+// dispatchTask runs a streamed task in the background, tracked so Stop can drain it. The caller
+// holds a stream slot from reserveStreamSlot, which is released once the task has been handled.
+func (sdk *SDK) dispatchTask(ctx context.Context, task *pb.ExecutionTask) {
+	sdk.taskWG.Add(1)
+	sdk.inFlightTasks.Add(1)
+	go func() {
+		defer sdk.taskWG.Done()
+		defer sdk.inFlightTasks.Add(-1)
+		defer func() { <-sdk.streamSlots }()
+		sdk.handleExecutionTask(ctx, task)
+	}()
+}
+
+// taskStreamLoop handles incoming execution tasks; tasks execute on taskCtx
+func (sdk *SDK) taskStreamLoop(ctx, taskCtx context.Context) {
 	defer sdk.matcherWG.Done()
+	defer sdk.taskStream.disconnected()
 
 	// Read agent ID directly to avoid potential deadlock
 	var agentID string
@@ -58,40 +188,63 @@ func (sdk *SDK) taskStreamLoop(ctx context.Context) {
 		AgentId: agentID,
 	}
 
-	log.Printf("[SDK DEBUG] Starting task stream loop for agent: %s", agentID)
+	backoff := newReconnectBackoff(sdk.config.ReconnectInitialBackoff, sdk.config.ReconnectMaxBackoff)
+	rejections := 0
+
+	sdk.logger.Debug("Starting task stream loop", "agent_id", agentID)
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Printf("[SDK DEBUG] Task stream loop context done, exiting")
+			sdk.logger.Debug("Task stream loop exiting")
 			return
 		default:
 		}
 
-		log.Printf("[SDK DEBUG] Calling StreamTasks...")
-		taskCh, errCh := sdk.matcherClient.StreamTasks(ctx, req)
-		log.Printf("[SDK DEBUG] StreamTasks called, waiting for tasks...")
+		matcherClient := sdk.clients().matcher
+		if matcherClient == nil {
+			sdk.logger.Debug("Task stream loop exiting, matcher client closed")
+			return
+		}
+
+		connectedAt := time.Now()
+		taskCh, errCh := matcherClient.StreamTasks(ctx, req)
+		sdk.taskStream.connected()
+		sdk.logger.Debug("Task stream connected, waiting for tasks")
 
 		for {
+			// Stop reading while the agent is full so tasks back up in the stream and gRPC flow
+			// control throttles the matcher instead of tasks being accepted only to be rejected
+			if !sdk.reserveStreamSlot(ctx) {
+				sdk.logger.Debug("Task stream loop exiting")
+				return
+			}
+
 			select {
 			case <-ctx.Done():
-				log.Printf("[SDK DEBUG] Task stream context done")
+				<-sdk.streamSlots
+				sdk.logger.Debug("Task stream loop exiting")
 				return
 			case task, ok := <-taskCh:
 				if !ok {
-					// Channel closed, reconnect
-					log.Printf("[SDK DEBUG] Task stream channel closed, reconnecting...")
-					time.Sleep(5 * time.Second)
+					<-sdk.streamSlots
+					// Stream ended; the error, if any, is buffered on errCh
+					if !sdk.handleStreamFailure(ctx, "task", <-errCh, &rejections, backoff, connectedAt) {
+						return
+					}
 					goto reconnect
 				}
-				log.Printf("[SDK DEBUG] Received task from stream: %s (intent: %s)", task.TaskId, task.IntentId)
+				rejections = 0
+				sdk.taskStream.delivered()
+				sdk.logger.Debug("Received task", "task_id", task.TaskId, "intent_id", task.IntentId)
 				// Handle task in separate goroutine to avoid blocking the stream
-				go sdk.handleExecutionTask(ctx, task)
-			case err := <-errCh:
-				if err != nil {
-					log.Printf("[SDK DEBUG] Task stream error: %v", err)
-					sdk.fireCallback("OnError", err)
-					time.Sleep(5 * time.Second)
+				sdk.dispatchTask(taskCtx, task)
+			case err, ok := <-errCh:
+				<-sdk.streamSlots
+				if ok && err != nil {
+					if !sdk.handleStreamFailure(ctx, "task", err, &rejections, backoff, connectedAt) {
+						return
+					}
 					goto reconnect
 				}
 			}
@@ -103,44 +256,58 @@ func (sdk *SDK) taskStreamLoop(ctx context.Context) {
 // intentStreamLoop handles incoming intents for bidding
 func (sdk *SDK) intentStreamLoop(ctx context.Context) {
 	defer sdk.matcherWG.Done()
+	defer sdk.intentStream.disconnected()
 
 	req := &pb.StreamIntentsRequest{
 		SubnetId: sdk.GetSubnetID(),
 	}
 
-	log.Printf("[SDK DEBUG] Starting intent stream loop for subnet: %s", req.SubnetId)
+	backoff := newReconnectBackoff(sdk.config.ReconnectInitialBackoff, sdk.config.ReconnectMaxBackoff)
+	rejections := 0
+
+	sdk.logger.Debug("Starting intent stream loop", "subnet_id", req.SubnetId)
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Printf("[SDK DEBUG] Intent stream loop context done, exiting")
+			sdk.logger.Debug("Intent stream loop exiting")
 			return
 		default:
 		}
 
-		log.Printf("[SDK DEBUG] Calling StreamIntents...")
-		intentCh, errCh := sdk.matcherClient.StreamIntents(ctx, req)
-		log.Printf("[SDK DEBUG] StreamIntents called, waiting for updates...")
+		matcherClient := sdk.clients().matcher
+		if matcherClient == nil {
+			sdk.logger.Debug("Intent stream loop exiting, matcher client closed")
+			return
+		}
+
+		connectedAt := time.Now()
+		intentCh, errCh := matcherClient.StreamIntents(ctx, req)
+		sdk.intentStream.connected()
+		sdk.logger.Debug("Intent stream connected, waiting for updates")
 
 		for {
 			select {
 			case <-ctx.Done():
-				log.Printf("[SDK DEBUG] Intent stream context done")
+				sdk.logger.Debug("Intent stream loop exiting")
 				return
 			case update, ok := <-intentCh:
 				if !ok {
-					// Channel closed, reconnect
-					log.Printf("[SDK DEBUG] Intent stream channel closed, reconnecting...")
-					time.Sleep(5 * time.Second)
+					// Stream ended; the error, if any, is buffered on errCh
+					if !sdk.handleStreamFailure(ctx, "intent", <-errCh, &rejections, backoff, connectedAt) {
+						return
+					}
 					goto reconnect
 				}
-				log.Printf("[SDK DEBUG] Received intent update: %s, type: %s", update.IntentId, update.UpdateType)
+				rejections = 0
+				sdk.intentStream.delivered()
+				sdk.logger.Debug("Received intent update", "intent_id", update.IntentId, "type", update.UpdateType)
 				sdk.handleIntentUpdate(ctx, update)
-			case err := <-errCh:
-				if err != nil {
-					log.Printf("[SDK DEBUG] Intent stream error: %v", err)
-					sdk.fireCallback("OnError", err)
-					time.Sleep(5 * time.Second)
+			case err, ok := <-errCh:
+				if ok && err != nil {
+					if !sdk.handleStreamFailure(ctx, "intent", err, &rejections, backoff, connectedAt) {
+						return
+					}
 					goto reconnect
 				}
 			}
@@ -151,10 +318,16 @@ func (sdk *SDK) intentStreamLoop(ctx context.Context) {
 
 // handleExecutionTask processes an execution task
 func (sdk *SDK) handleExecutionTask(ctx context.Context, taskProto *pb.ExecutionTask) {
-	log.Printf("[SDK DEBUG] handleExecutionTask called for task: %s", taskProto.TaskId)
+	sdk.logger.Debug("Handling execution task", "task_id", taskProto.TaskId)
+
+	if !sdk.running.Load() {
+		sdk.logger.Debug("SDK not running, skipping task", "task_id", taskProto.TaskId)
+		return
+	}
 
-	if !sdk.running {
-		log.Printf("[SDK DEBUG] SDK not running, skipping task")
+	if sdk.taskDedup.checkAndRecord(taskProto.TaskId, time.Now()) {
+		sdk.logger.Info("Skipping re-delivered task already processed", "task_id", taskProto.TaskId,
+			"window", sdk.config.TaskDedupWindow)
 		return
 	}
 
@@ -164,37 +337,307 @@ func (sdk *SDK) handleExecutionTask(ctx context.Context, taskProto *pb.Execution
 		Type:      taskProto.IntentType,
 		Data:      taskProto.IntentData,
 		Metadata:  map[string]string{"bid_id": taskProto.BidId},
-		Deadline:  time.Unix(taskProto.Deadline, 0),
 		CreatedAt: time.Unix(taskProto.CreatedAt, 0),
 	}
+	// A zero deadline means the matcher set none
+	if taskProto.Deadline > 0 {
+		task.Deadline = time.Unix(taskProto.Deadline, 0)
+	}
+
+	ctx, span := sdk.startTaskSpan(ctx, task)
+
+	if reason, ok := sdk.admitTask(ctx, task); !ok {
+		sdk.rejectTask(ctx, span, taskProto, task, reason)
+		return
+	}
+	span.SetAttributes(Attribute{Key: AttrTaskAccepted, Value: "true"})
+
+	sdk.respondToTask(ctx, taskProto, true, "")
+	result, err := sdk.runAdmittedTask(ctx, span, task)
+
+	// Submit execution report via gRPC
+	if sdk.clients().validator == nil {
+		sdk.logger.Debug("No validator client configured, skipping execution report", "task_id", task.ID)
+		reportJob{span: span}.endSpan(reportStatusSkipped, 0, nil)
+		return
+	}
+
+	reportID := sdk.persistTaskResult(task, result, err)
+	span.SetAttributes(Attribute{Key: AttrReportID, Value: reportID})
+	sdk.enqueueReport(ctx, reportJob{
+		reportID:      reportID,
+		task:          task,
+		result:        result,
+		span:          span,
+		traceMetadata: sdk.traceMetadata(ctx),
+	})
+}
+
+// startTaskSpan starts the span covering a task from admission until its report outcome is known
+func (sdk *SDK) startTaskSpan(ctx context.Context, task *Task) (context.Context, Span) {
+	return sdk.tracer.Start(ctx, SpanTaskExecute,
+		Attribute{Key: AttrTaskID, Value: task.ID},
+		Attribute{Key: AttrIntentID, Value: task.IntentID},
+		Attribute{Key: AttrTaskType, Value: task.Type},
+	)
+}
+
+// admitTask checks that a task can run now and reserves its execution slots. When it cannot, it
+// returns the reason for rejecting the task.
+func (sdk *SDK) admitTask(ctx context.Context, task *Task) (string, bool) {
+	if !task.Deadline.IsZero() && !task.Deadline.After(time.Now()) {
+		return "deadline passed", false
+	}
+
+	handler := sdk.handlerFor(task.Type)
+	if handler == nil {
+		return "no handler for type", false
+	}
+
+	if acceptor, ok := handler.(TaskAcceptor); ok {
+		if accepted, reason := acceptor.CanAccept(task); !accepted {
+			if reason == "" {
+				reason = "declined by handler"
+			}
+			return reason, false
+		}
+	}
 
-	log.Printf("[SDK DEBUG] Task created, starting execution...")
+	return sdk.acquireTaskSlot(ctx, task)
+}
 
-	// Call OnTaskAccepted callback (no need to respond to matcher like validator_test_agent)
-	log.Printf("[SDK DEBUG] Calling OnTaskAccepted callback")
+// runAdmittedTask executes a task admitted by admitTask, releasing its slots and firing the
+// accepted and completed callbacks. A handler error without a result yields a failed result so
+// the failure can still be reported.
+func (sdk *SDK) runAdmittedTask(ctx context.Context, span Span, task *Task) (*Result, error) {
 	sdk.fireCallback("OnTaskAccepted", task)
 
-	// Execute task
-	log.Printf("[SDK DEBUG] Executing task...")
 	result, err := sdk.ExecuteTask(ctx, task)
 	if err != nil {
-		log.Printf("[SDK DEBUG] Task %s execution failed: %v", task.ID, err)
+		sdk.logger.Warn("Task execution failed", "task_id", task.ID, "error", err)
+		span.RecordError(err)
+		if result == nil {
+			result = &Result{Success: false, Error: err.Error()}
+		}
 	} else {
-		log.Printf("[SDK DEBUG] Task %s executed successfully", task.ID)
+		sdk.logger.Debug("Task executed successfully", "task_id", task.ID)
 	}
 
-	log.Printf("[SDK DEBUG] Calling OnTaskCompleted callback")
+	sdk.releaseTaskSlot(task.Type)
+
 	sdk.fireCallback("OnTaskCompleted", task, result, err)
+	return result, err
+}
 
-	// Submit execution report via gRPC
-	log.Printf("[SDK DEBUG] Submitting execution report...")
+// persistTaskResult assigns the report ID for a task result, persisting successful results when a
+// result store is configured so they survive a restart
+func (sdk *SDK) persistTaskResult(task *Task, result *Result, err error) string {
+	reportID := generateReportID(sdk.config.RandSource)
+	if sdk.resultStore != nil && err == nil && result != nil && result.Success {
+		if err := sdk.resultStore.Save(reportID, task, result); err != nil {
+			sdk.logger.Error("Failed to persist task result", "task_id", task.ID, "error", err)
+		}
+	}
+	return reportID
+}
+
+// rejectTask tells the matcher the agent will not run the task so it can be reassigned, ending its span.
+// The task ID is forgotten by deduplication so a later re-delivery can still be accepted.
+func (sdk *SDK) rejectTask(ctx context.Context, span Span, taskProto *pb.ExecutionTask, task *Task, reason string) {
+	sdk.taskDedup.forget(task.ID)
+	sdk.logger.Warn("Rejecting task", "task_id", task.ID, "type", task.Type, "reason", reason)
+	sdk.respondToTask(ctx, taskProto, false, reason)
+	sdk.fireCallback("OnTaskRejected", task, reason)
+	span.SetAttributes(Attribute{Key: AttrTaskAccepted, Value: "false"})
+	span.End()
+}
 
-	if sdk.validatorClient == nil {
-		log.Printf("[SDK DEBUG] No validator client configured, skipping execution report submission")
+// respondToTask reports task acceptance or rejection to the matcher. Failures are logged only:
+// the matcher falls back to its assignment timeout when no response arrives.
+func (sdk *SDK) respondToTask(ctx context.Context, taskProto *pb.ExecutionTask, accepted bool, reason string) {
+	matcherClient := sdk.clients().matcher
+	if matcherClient == nil {
 		return
 	}
 
-	reportID := generateReportID()
+	agentID := taskProto.AgentId
+	if agentID == "" {
+		agentID = sdk.config.AgentID
+		if sdk.config.Identity != nil {
+			agentID = sdk.config.Identity.AgentID
+		}
+	}
+
+	_, err := matcherClient.RespondToTask(ctx, &pb.RespondToTaskRequest{
+		Response: &pb.TaskResponse{
+			TaskId:    taskProto.TaskId,
+			AgentId:   agentID,
+			Accepted:  accepted,
+			Reason:    reason,
+			Timestamp: time.Now().Unix(),
+		},
+	})
+	if err != nil {
+		sdk.logger.Warn("Failed to respond to task", "task_id", taskProto.TaskId, "accepted", accepted, "error", err)
+	}
+}
+
+// acquireTaskSlot reserves one of the MaxConcurrentTasks execution slots, queueing the task by
+// deadline while all are busy, and, when the task type has its own limit, one of that type's slots.
+// It waits at most BidTimeout for both and returns the rejection reason when it gives up.
+func (sdk *SDK) acquireTaskSlot(ctx context.Context, task *Task) (string, bool) {
+	timer := time.NewTimer(sdk.config.BidTimeout)
+	defer timer.Stop()
+
+	if reason, ok := sdk.taskQueue.acquire(ctx, task.Deadline, timer.C); !ok {
+		return reason, false
+	}
+	if typeSlots, ok := sdk.typeSlots[task.Type]; ok && !acquireSlot(ctx, typeSlots, timer.C) {
+		sdk.taskQueue.release()
+		return "at capacity", false
+	}
+
+	sdk.metrics.RecordTaskStart()
+	sdk.metrics.RecordTaskTypeStart(task.Type)
+	return "", true
+}
+
+// acquireSlot takes a slot from a semaphore channel, giving up when timeout fires or ctx is done
+func acquireSlot(ctx context.Context, slots chan struct{}, timeout <-chan time.Time) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timeout:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// releaseTaskSlot frees the slots reserved by acquireTaskSlot
+func (sdk *SDK) releaseTaskSlot(taskType string) {
+	if typeSlots, ok := sdk.typeSlots[taskType]; ok {
+		<-typeSlots
+	}
+	sdk.taskQueue.release()
+	sdk.metrics.RecordTaskEnd()
+	sdk.metrics.RecordTaskTypeEnd(taskType)
+}
+
+// reportTaskResult submits the report for a task result and releases its persisted copy once acknowledged
+func (sdk *SDK) reportTaskResult(ctx context.Context, reportID string, task *Task, result *Result) ([]*ExecutionReceipt, error) {
+	sdk.pendingReports.track(reportID, task.ID)
+
+	receipts, err := sdk.submitTaskReport(ctx, reportID, task, result)
+	sdk.finishReport(reportID, task, result, receipts, err)
+	return receipts, err
+}
+
+// finishReport settles the bookkeeping for a submitted report: pending state, the persisted result,
+// and the completion and finalization callbacks
+func (sdk *SDK) finishReport(reportID string, task *Task, result *Result, receipts []*ExecutionReceipt, err error) {
+	if err == nil {
+		sdk.pendingReports.done(reportID)
+		if sdk.resultStore != nil {
+			if err := sdk.resultStore.Delete(reportID); err != nil {
+				sdk.logger.Warn("Failed to remove persisted result", "report_id", reportID, "error", err)
+			}
+		}
+	} else if sdk.resultStore != nil && result != nil && result.Success {
+		// Persisted results are re-submitted on the next start, so the report is still owed
+		sdk.pendingReports.fail(reportID, err)
+	} else {
+		sdk.pendingReports.done(reportID)
+	}
+	sdk.fireReportCompleted(task, receipts, err)
+	if err == nil && len(receipts) >= sdk.config.ReportFinalizeThreshold {
+		sdk.fireReportFinalized(task, receipts)
+	}
+}
+
+// replayPersistedResults re-submits results that were persisted but never acknowledged before a restart
+func (sdk *SDK) replayPersistedResults(ctx context.Context) {
+	defer sdk.matcherWG.Done()
+
+	records, err := sdk.resultStore.Load()
+	if err != nil {
+		sdk.logger.Error("Failed to load persisted results", "error", err)
+		return
+	}
+
+	for _, record := range records {
+		if ctx.Err() != nil {
+			return
+		}
+		sdk.logger.Info("Re-submitting persisted result", "task_id", record.Task.ID, "report_id", record.ReportID)
+		sdk.reportTaskResult(ctx, record.ReportID, record.Task, record.Result)
+	}
+}
+
+// submitTaskReport builds the execution report for a completed task and submits it via gRPC
+func (sdk *SDK) submitTaskReport(ctx context.Context, reportID string, task *Task, result *Result) ([]*ExecutionReceipt, error) {
+	sdk.logger.Debug("Submitting execution report", "report_id", reportID, "task_id", task.ID)
+
+	clients := sdk.clients()
+	if clients.validator == nil {
+		return nil, errors.New("validator client not initialized")
+	}
+
+	reportProto, err := sdk.buildTaskReport(reportID, task, result)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = withResultEncodings(ctx, sdk.config.resultEncoding(result.Data))
+	submitted := executionReportFromProto(reportProto)
+	submitted.ResultData = result.Data
+
+	// Try validators in order until enough accept the report to finalize it
+	var (
+		receipts   []*ExecutionReceipt
+		submitErrs []error
+	)
+	for _, validator := range clients.validators {
+		if len(receipts) >= sdk.config.ReportFinalizeThreshold {
+			break
+		}
+
+		var receipt *pb.Receipt
+		err := sdk.submitWithRetry(ctx, validator.addr, []string{reportID}, func(ctx context.Context) error {
+			var err error
+			receipt, err = validator.client.SubmitExecutionReport(ctx, reportProto)
+			return err
+		})
+		if err != nil {
+			sdk.logger.Warn("Failed to submit execution report", "report_id", reportID, "validator", validator.addr, "error", err)
+			sdk.metrics.RecordReportFailure()
+			submitErrs = append(submitErrs, fmt.Errorf("%s: %w", validator.addr, err))
+			continue
+		}
+		sdk.metrics.RecordReportSuccess()
+
+		sdk.logger.Debug("Execution report submitted", "report_id", reportID, "validator", validator.addr,
+			"status", receipt.Status, "phase", receipt.Phase)
+		acknowledged := receiptFromProto(receipt, validator.addr)
+		receipts = append(receipts, acknowledged)
+		sdk.fireCallback("OnReportSubmitted", submitted, acknowledged)
+	}
+
+	if len(receipts) == 0 {
+		return nil, fmt.Errorf("submit execution report %s: %w", reportID, errors.Join(submitErrs...))
+	}
+	return receipts, nil
+}
+
+// buildTaskReport builds and signs the execution report for a completed task, rejecting reports
+// that miss required fields or are larger than ReportMaxPayloadSize
+func (sdk *SDK) buildTaskReport(reportID string, task *Task, result *Result) (*pb.ExecutionReport, error) {
 	status := pb.ExecutionReport_SUCCESS
 	if !result.Success {
 		status = pb.ExecutionReport_FAILED
@@ -202,71 +645,212 @@ func (sdk *SDK) handleExecutionTask(ctx context.Context, taskProto *pb.Execution
 
 	// Prepare error info if task failed
 	var errorInfo *pb.ErrorInfo
-	if !result.Success && result.Error != "" {
+	if !result.Success && (result.Error != "" || result.ErrorCode != "" || len(result.ErrorDetails) > 0) {
+		code := result.ErrorCode
+		if code == "" {
+			code = "EXECUTION_FAILED"
+		}
 		errorInfo = &pb.ErrorInfo{
-			Code:    "EXECUTION_FAILED",
+			Code:    code,
 			Message: result.Error,
+			Details: result.ErrorDetails,
 		}
 	}
 
+	evidence := evidenceToProto(result.Evidence)
+
+	// Hash and sign the result data as transmitted, compressed or not
+	resultData, err := sdk.config.encodeResultData(result.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bind the result hash into the evidence so validators can cross-check the transmitted bytes
+	if algorithm := sdk.config.ResultHashAlgorithm; algorithm != "" {
+		digest, err := hashResultData(algorithm, resultData)
+		if err != nil {
+			return nil, err
+		}
+		if evidence == nil {
+			evidence = &pb.VerificationEvidence{}
+		}
+		evidence.OutputsHash = digest
+	}
+
+	// Use chain address for RootLayer compatibility, falling back to the agent ID like HTTP reports
+	agentID := sdk.GetChainAddress()
+	if agentID == "" {
+		agentID = sdk.GetAgentID()
+	}
+
 	reportProto := &pb.ExecutionReport{
 		ReportId:     reportID,
 		AssignmentId: task.ID,
 		IntentId:     task.IntentID,
-		AgentId:      sdk.GetChainAddress(), // Use chain address for RootLayer compatibility
+		AgentId:      agentID,
 		Status:       status,
-		ResultData:   result.Data,
+		ResultData:   resultData,
 		Timestamp:    time.Now().Unix(),
-		Evidence:     nil,       // Optional: verification evidence
+		Evidence:     evidence,  // Optional: verification evidence
 		Error:        errorInfo, // Optional: error details
-		Signature:    []byte{},  // TODO: Sign the report
 	}
 
-	receipt, err := sdk.validatorClient.SubmitExecutionReport(ctx, reportProto)
+	if err := validateExecutionReport(reportProto.ReportId, reportProto.AssignmentId, reportProto.IntentId, reportProto.AgentId,
+		convertProtoStatusToSDK(reportProto.Status)); err != nil {
+		return nil, sdk.rejectTaskReport(reportID, task, err)
+	}
+
+	signature, err := sdk.signReport(reportProto.ReportId, reportProto.AssignmentId, reportProto.IntentId, reportProto.AgentId,
+		convertProtoStatusToSDK(reportProto.Status), reportProto.ResultData, reportProto.Timestamp)
 	if err != nil {
-		log.Printf("[SDK DEBUG] Failed to submit execution report %s: %v", reportID, err)
-		return
+		return nil, fmt.Errorf("sign execution report %s: %w", reportID, err)
 	}
+	reportProto.Signature = signature
 
-	log.Printf("[SDK DEBUG] Execution report %s submitted successfully", reportID)
-	log.Printf("[SDK DEBUG] Receipt: ReportID=%s, Status=%s, Phase=%s", receipt.ReportId, receipt.Status, receipt.Phase)
+	if err := sdk.checkReportPayloadSize(proto.Size(reportProto)); err != nil {
+		return nil, sdk.rejectTaskReport(reportID, task, err)
+	}
+	return reportProto, nil
+}
+
+// evidenceToProto converts handler-supplied evidence to its wire form
+func evidenceToProto(evidence *Evidence) *pb.VerificationEvidence {
+	if evidence == nil {
+		return nil
+	}
+	converted := &pb.VerificationEvidence{
+		EnvFingerprint: evidence.EnvFingerprint,
+		InputsHash:     evidence.InputsHash,
+		TranscriptRoot: evidence.TranscriptRoot,
+		ProofExec:      evidence.ProofExec,
+		ExternalRefs:   evidence.ExternalRefs,
+	}
+	if usage := evidence.ResourceUsage; usage != nil {
+		converted.ResourceUsage = &pb.ResourceUsage{
+			CpuMs:        usage.CPUMs,
+			MemoryMb:     usage.MemoryMB,
+			IoOps:        usage.IOOps,
+			NetworkBytes: usage.NetworkBytes,
+		}
+	}
+	return converted
+}
+
+// rejectTaskReport records a task report that will not be submitted because it is malformed and
+// returns the error to report
+func (sdk *SDK) rejectTaskReport(reportID string, task *Task, err error) error {
+	sdk.logger.Error("Execution report not submitted", "report_id", reportID, "task_id", task.ID, "error", err)
+	sdk.metrics.RecordReportFailure()
+	err = fmt.Errorf("execution report %s: %w", reportID, err)
+	sdk.fireCallback("OnError", err)
+	return err
+}
+
+// receiptFromProto converts a gRPC validator receipt to the SDK ExecutionReceipt
+func receiptFromProto(receipt *pb.Receipt, endpoint string) *ExecutionReceipt {
+	converted := &ExecutionReceipt{
+		ReportID:    receipt.ReportId,
+		IntentID:    receipt.IntentId,
+		ValidatorID: receipt.ValidatorId,
+		Status:      receipt.Status,
+		Phase:       receipt.Phase,
+		Endpoint:    endpoint,
+	}
+	if receipt.ReceivedTs > 0 {
+		converted.ReceivedAt = time.Unix(receipt.ReceivedTs, 0).UTC()
+	}
+	return converted
+}
+
+// intentFromUpdate maps a matcher intent update to an Intent. The update only carries
+// intent_id, update_type and timestamp; there is nothing to map into Description, Data or Metadata.
+func intentFromUpdate(update *pb.MatcherIntentUpdate) *Intent {
+	return &Intent{
+		ID:        update.GetIntentId(),
+		Type:      update.GetUpdateType(),
+		CreatedAt: time.Unix(update.GetTimestamp(), 0),
+	}
 }
 
 // handleIntentUpdate processes an intent update for bidding
 func (sdk *SDK) handleIntentUpdate(ctx context.Context, update *pb.MatcherIntentUpdate) {
-	if sdk.biddingStrategy == nil {
+	if won, resolved := bidOutcome(update.GetUpdateType()); resolved {
+		sdk.handleBidOutcome(update.GetIntentId(), won)
 		return
 	}
 
-	intent := &Intent{
-		ID:          update.IntentId,
-		Type:        update.UpdateType,
-		Description: "",
-		CreatedAt:   time.Unix(update.Timestamp, 0),
+	strategy := sdk.currentBiddingStrategy()
+	if strategy == nil {
+		return
 	}
 
+	intent := intentFromUpdate(update)
+
 	// Check if we should bid
-	if !sdk.biddingStrategy.ShouldBid(intent) {
+	if !strategy.ShouldBid(intent) {
 		return
 	}
 
 	// Calculate bid
-	bid := sdk.biddingStrategy.CalculateBid(intent)
+	bid := strategy.CalculateBid(intent)
 	if bid == nil {
 		return
 	}
 
+	if sdk.config.BidDryRun {
+		simulated := *bid
+		simulated.DryRun = true
+		sdk.metrics.RecordSimulatedBid()
+		sdk.fireCallback("OnBidSubmitted", intent, &simulated)
+		sdk.logger.Info("Simulated bid", "intent_id", intent.ID, "price", simulated.Price)
+		return
+	}
+
+	if !sdk.bidLimiter.allow(time.Now()) {
+		sdk.metrics.RecordBidRateLimited()
+		sdk.logger.Debug("Bid rate limit reached, dropping bid", "intent_id", intent.ID)
+		return
+	}
+
+	if sdk.config.bidBatchingEnabled() {
+		sdk.enqueueBid(ctx, intent, bid)
+		return
+	}
+
+	ack, err := sdk.submitBid(ctx, intent.ID, bid)
+	if err != nil {
+		sdk.logger.Warn("Failed to submit bid", "intent_id", intent.ID, "error", err)
+		sdk.fireCallback("OnError", fmt.Errorf("bid submission failed: %w", err))
+		return
+	}
+	sdk.notifyBidAck(intent, bid, ack)
+}
+
+// notifyBidAck fires the bid callbacks for the matcher's acknowledgement of a strategy bid
+func (sdk *SDK) notifyBidAck(intent *Intent, bid *Bid, ack *BidAck) {
+	sdk.trackBid(intent.ID, ack, bid.Price)
+	if ack.Accepted {
+		sdk.fireCallback("OnBidSubmitted", intent, bid)
+		sdk.fireCallback("OnBidAccepted", intent, bid, ack)
+		sdk.logger.Info("Bid submitted", "intent_id", intent.ID, "bid_id", ack.BidID)
+	} else {
+		sdk.fireCallback("OnBidRejected", intent, bid, ack)
+		sdk.logger.Info("Bid rejected", "intent_id", intent.ID, "reason", ack.Reason)
+	}
+}
+
+// newBidProto builds the pb.Bid submitted to the matcher for intentID
+func (sdk *SDK) newBidProto(intentID string, bid *Bid) *pb.Bid {
 	// Ensure chain address in metadata
 	metadata := ensureChainAddressMetadata(bid.Metadata, sdk.GetChainAddress())
 
 	// Generate nonce
-	nonce := make([]byte, 16)
-	rand.Read(nonce)
+	nonce := randomBytes(sdk.config.RandSource, 16)
 
 	// Create bid request
-	bidProto := &pb.Bid{
-		BidId:       generateBidID(),
-		IntentId:    intent.ID,
+	return &pb.Bid{
+		BidId:       generateBidID(sdk.config.RandSource),
+		IntentId:    intentID,
 		AgentId:     sdk.GetAgentID(),
 		Price:       bid.Price,
 		Token:       bid.Currency,
@@ -274,45 +858,66 @@ func (sdk *SDK) handleIntentUpdate(ctx context.Context, update *pb.MatcherIntent
 		Nonce:       hex.EncodeToString(nonce),
 		Metadata:    metadata,
 	}
+}
+
+// submitBid submits a single bid for intentID to the matcher under the bid response timeout and
+// records the outcome in metrics
+func (sdk *SDK) submitBid(ctx context.Context, intentID string, bid *Bid) (*BidAck, error) {
+	matcherClient := sdk.clients().matcher
+	if matcherClient == nil {
+		return nil, errors.New("matcher client not initialized")
+	}
 
+	bidProto := sdk.newBidProto(intentID, bid)
 	req := &pb.SubmitBidRequest{
 		Bid: bidProto,
 	}
 
 	// Submit bid
-	resp, err := sdk.matcherClient.SubmitBid(ctx, req)
+	bidCtx, cancel := context.WithTimeout(ctx, sdk.config.BidResponseTimeout)
+	defer cancel()
+
+	resp, err := matcherClient.SubmitBid(bidCtx, req)
 	if err != nil {
-		log.Printf("Failed to submit bid for intent %s: %v", intent.ID, err)
-		sdk.fireCallback("OnError", fmt.Errorf("bid submission failed: %w", err))
 		sdk.metrics.RecordBid(false)
-		return
+		return nil, err
 	}
 
-	accepted := resp.Ack != nil && resp.Ack.Accepted
-	sdk.metrics.RecordBid(accepted)
+	ack := bidAckFromProto(resp.Ack, bidProto.BidId)
+	sdk.metrics.RecordBid(ack.Accepted)
+	return ack, nil
+}
 
-	if accepted {
-		sdk.fireCallback("OnBidSubmitted", intent, bid)
-		log.Printf("Bid submitted for intent %s: %s", intent.ID, bidProto.BidId)
-	} else {
-		reason := "rejected"
-		if resp.Ack != nil {
-			reason = resp.Ack.Reason
-		}
-		log.Printf("Bid rejected for intent %s: %s", intent.ID, reason)
+// bidAckFromProto converts the matcher's bid acknowledgement; a missing ack is treated as a rejection
+func bidAckFromProto(ack *pb.BidSubmissionAck, bidID string) *BidAck {
+	if ack == nil {
+		return &BidAck{BidID: bidID, Reason: "rejected"}
+	}
+
+	converted := &BidAck{
+		BidID:    ack.BidId,
+		Accepted: ack.Accepted,
+		Reason:   ack.Reason,
+		Status:   ack.Status.String(),
+	}
+	if converted.BidID == "" {
+		converted.BidID = bidID
+	}
+	if !converted.Accepted && converted.Reason == "" {
+		converted.Reason = "rejected"
+	}
+	if ack.RecordedAt > 0 {
+		converted.RecordedAt = time.Unix(ack.RecordedAt, 0).UTC()
 	}
+	return converted
 }
 
 // generateReportID generates a unique report ID
-func generateReportID() string {
-	b := make([]byte, 16)
-	rand.Read(b)
-	return fmt.Sprintf("report-%s", hex.EncodeToString(b))
+func generateReportID(source io.Reader) string {
+	return fmt.Sprintf("report-%s", hex.EncodeToString(randomBytes(source, 16)))
 }
 
 // generateBidID generates a unique bid ID in format 0x + 64 hex characters (32 bytes)
-func generateBidID() string {
-	b := make([]byte, 32)
-	rand.Read(b)
-	return fmt.Sprintf("0x%s", hex.EncodeToString(b))
+func generateBidID(source io.Reader) string {
+	return fmt.Sprintf("0x%s", hex.EncodeToString(randomBytes(source, 32)))
 }
@@ -0,0 +1,1000 @@
+package agentsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "subnet/proto/subnet"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type blockingHandler struct {
+	release  chan struct{}
+	running  int32
+	maxSeen  int32
+	executed int32
+}
+
+func (h *blockingHandler) Execute(ctx context.Context, task *Task) (*Result, error) {
+	current := atomic.AddInt32(&h.running, 1)
+	defer atomic.AddInt32(&h.running, -1)
+	for {
+		seen := atomic.LoadInt32(&h.maxSeen)
+		if current <= seen || atomic.CompareAndSwapInt32(&h.maxSeen, seen, current) {
+			break
+		}
+	}
+	atomic.AddInt32(&h.executed, 1)
+	<-h.release
+	return &Result{Success: true}, nil
+}
+
+type recordingCallbacks struct {
+	mu       sync.Mutex
+	rejected []string
+	dropped  []string
+	won      []string
+	lost     []string
+	errs     []error
+}
+
+func (c *recordingCallbacks) OnReportDropped(task *Task, reportID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dropped = append(c.dropped, reportID)
+}
+
+func (c *recordingCallbacks) OnStart() error               { return nil }
+func (c *recordingCallbacks) OnStop() error                { return nil }
+func (c *recordingCallbacks) OnTaskAccepted(task *Task)    {}
+func (c *recordingCallbacks) OnBidSubmitted(*Intent, *Bid) {}
+func (c *recordingCallbacks) OnBidWon(intentID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.won = append(c.won, intentID)
+}
+func (c *recordingCallbacks) OnBidLost(intentID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lost = append(c.lost, intentID)
+}
+func (c *recordingCallbacks) OnError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errs = append(c.errs, err)
+}
+func (c *recordingCallbacks) OnTaskCompleted(*Task, *Result, error) {}
+func (c *recordingCallbacks) OnTaskRejected(task *Task, reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rejected = append(c.rejected, reason)
+}
+
+func TestHandleExecutionTaskEnforcesMaxConcurrentTasks(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.MaxConcurrentTasks = 2
+		cfg.BidTimeout = 20 * time.Millisecond
+	})
+	handler := &blockingHandler{release: make(chan struct{})}
+	callbacks := &recordingCallbacks{}
+	sdk.RegisterHandler(handler)
+	sdk.RegisterCallbacks(callbacks)
+	sdk.running.Store(true)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sdk.handleExecutionTask(context.Background(), &pb.ExecutionTask{TaskId: fmt.Sprintf("task-%d", i)})
+		}()
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if current := atomic.LoadInt32(&sdk.metrics.CurrentTasks); current != 2 {
+		t.Fatalf("expected 2 tasks in flight, got %d", current)
+	}
+	close(handler.release)
+	wg.Wait()
+
+	if handler.maxSeen != 2 || handler.executed != 2 {
+		t.Fatalf("expected exactly 2 concurrent executions, got max=%d executed=%d", handler.maxSeen, handler.executed)
+	}
+	if len(callbacks.rejected) != 3 || callbacks.rejected[0] != "at capacity" {
+		t.Fatalf("expected 3 at-capacity rejections, got %v", callbacks.rejected)
+	}
+	if current := atomic.LoadInt32(&sdk.metrics.CurrentTasks); current != 0 {
+		t.Fatalf("expected no tasks in flight after completion, got %d", current)
+	}
+}
+
+func TestConnectionStateTracksStreamReconnects(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.MatcherSubscriptionAttempts = 3
+		cfg.ReconnectInitialBackoff = time.Millisecond
+		cfg.ReconnectMaxBackoff = time.Millisecond
+	})
+	if state := sdk.ConnectionState(); state.MatcherStreamConnected || state.Task.Status != StreamDisconnected || !state.Task.LastConnectedAt.IsZero() {
+		t.Fatalf("expected a disconnected stream before start, got %+v", state)
+	}
+
+	sdk.matcherClient = &MatcherClient{client: &fakeMatcherService{streamErr: status.Error(codes.Unauthenticated, "bad signature")}, logger: sdk.logger}
+	sdk.matcherWG.Add(1)
+	sdk.taskStreamLoop(context.Background(), context.Background())
+
+	state := sdk.ConnectionState()
+	if state.MatcherStreamConnected || state.Task.Status != StreamDisconnected || state.Task.ReconnectAttempts != 2 || state.Task.LastConnectedAt.IsZero() {
+		t.Fatalf("expected two reconnects before giving up, got %+v", state)
+	}
+
+	sdk.intentStream.connected()
+	sdk.handleStreamFailure(context.Background(), "intent", errors.New("reset"), new(int), newReconnectBackoff(time.Millisecond, time.Millisecond), time.Now())
+	if intent := sdk.ConnectionState().Intent; intent.Status != StreamReconnecting || intent.ReconnectAttempts != 1 {
+		t.Fatalf("expected the intent stream to be reconnecting, got %+v", intent)
+	}
+	sdk.intentStream.connected()
+	sdk.intentStream.delivered()
+	if intent := sdk.ConnectionState().Intent; intent.Status != StreamConnected || intent.ReconnectAttempts != 0 {
+		t.Fatalf("expected a delivered message to reset reconnect attempts, got %+v", intent)
+	}
+}
+
+func TestBidAckFromProto(t *testing.T) {
+	ack := bidAckFromProto(&pb.BidSubmissionAck{
+		Accepted:   true,
+		Status:     pb.BidStatus_BID_STATUS_ACCEPTED,
+		RecordedAt: 1700000000,
+	}, "bid-1")
+	if !ack.Accepted || ack.BidID != "bid-1" || ack.Status != "BID_STATUS_ACCEPTED" || ack.RecordedAt.Unix() != 1700000000 {
+		t.Fatalf("unexpected ack %+v", ack)
+	}
+
+	missing := bidAckFromProto(nil, "bid-2")
+	if missing.Accepted || missing.Reason == "" || missing.BidID != "bid-2" {
+		t.Fatalf("expected missing ack to be a rejection, got %+v", missing)
+	}
+}
+
+func TestDrainTasksWaitsForInFlightTasks(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	handler := &blockingHandler{release: make(chan struct{})}
+	sdk.RegisterHandler(handler)
+	sdk.running.Store(true)
+
+	sdk.reserveStreamSlot(context.Background())
+	sdk.dispatchTask(context.Background(), &pb.ExecutionTask{TaskId: "task"})
+	time.Sleep(20 * time.Millisecond)
+
+	time.AfterFunc(20*time.Millisecond, func() { close(handler.release) })
+	sdk.drainTasks(time.Second)
+
+	if handler.executed != 1 || sdk.inFlightTasks.Load() != 0 {
+		t.Fatalf("expected task to finish during drain, executed=%d inFlight=%d", handler.executed, sdk.inFlightTasks.Load())
+	}
+}
+
+func TestDrainTasksGivesUpAfterTimeout(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	handler := &blockingHandler{release: make(chan struct{})}
+	defer close(handler.release)
+	sdk.RegisterHandler(handler)
+	sdk.running.Store(true)
+
+	sdk.reserveStreamSlot(context.Background())
+	sdk.dispatchTask(context.Background(), &pb.ExecutionTask{TaskId: "task"})
+	time.Sleep(20 * time.Millisecond)
+
+	start := time.Now()
+	sdk.drainTasks(20 * time.Millisecond)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("drain should stop waiting at the timeout, took %v", elapsed)
+	}
+	if sdk.inFlightTasks.Load() != 1 {
+		t.Fatalf("expected the blocked task to be abandoned, inFlight=%d", sdk.inFlightTasks.Load())
+	}
+}
+
+func TestEnqueueReportOverflowPolicies(t *testing.T) {
+	for _, tc := range []struct {
+		policy  string
+		dropped string
+	}{
+		{ReportOverflowDropOldest, "report-0"},
+		{ReportOverflowDropNewest, "report-new"},
+	} {
+		t.Run(tc.policy, func(t *testing.T) {
+			sdk := newTestSDK(t, func(cfg *Config) { cfg.ReportOverflowPolicy = tc.policy })
+			callbacks := &recordingCallbacks{}
+			sdk.RegisterCallbacks(callbacks)
+
+			task := &Task{ID: "task"}
+			for i := 0; i < cap(sdk.reportQueue); i++ {
+				sdk.enqueueReport(context.Background(), reportJob{reportID: fmt.Sprintf("report-%d", i), task: task})
+			}
+			sdk.enqueueReport(context.Background(), reportJob{reportID: "report-new", task: task})
+
+			if len(callbacks.dropped) != 1 || callbacks.dropped[0] != tc.dropped {
+				t.Fatalf("expected %s to be dropped, got %v", tc.dropped, callbacks.dropped)
+			}
+			if dropped := atomic.LoadInt64(&sdk.metrics.ReportsDropped); dropped != 1 {
+				t.Fatalf("expected 1 dropped report, got %d", dropped)
+			}
+			if len(sdk.reportQueue) != cap(sdk.reportQueue) {
+				t.Fatalf("expected queue to stay full, got %d", len(sdk.reportQueue))
+			}
+			pending := sdk.PendingReports()
+			if len(pending) != cap(sdk.reportQueue) {
+				t.Fatalf("expected every queued report to be pending, got %d", len(pending))
+			}
+			for _, report := range pending {
+				if report.ReportID == tc.dropped || report.Attempts != 0 {
+					t.Fatalf("expected only unsubmitted queued reports, got %+v", report)
+				}
+			}
+		})
+	}
+}
+
+func TestDryExecuteSkipsMetrics(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	handler := &blockingHandler{release: make(chan struct{})}
+	close(handler.release)
+	sdk.RegisterHandler(handler)
+
+	result, err := sdk.DryExecute(context.Background(), &Task{ID: "probe"})
+	if err != nil || result == nil || !result.Success {
+		t.Fatalf("unexpected dry run result %+v, err=%v", result, err)
+	}
+	if handler.executed != 1 {
+		t.Fatalf("expected handler to run once, got %d", handler.executed)
+	}
+	if snapshot := sdk.metrics.Snapshot(); snapshot != (MetricsSnapshot{}) {
+		t.Fatalf("dry run should not touch metrics, got %+v", snapshot)
+	}
+}
+
+func TestHandleExecutionTaskEnforcesPerTypeLimit(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.MaxConcurrentTasks = 10
+		cfg.MaxConcurrentTasksPerType = map[string]int{"ml": 1}
+		cfg.BidTimeout = 20 * time.Millisecond
+	})
+	handler := &blockingHandler{release: make(chan struct{})}
+	callbacks := &recordingCallbacks{}
+	sdk.RegisterHandler(handler)
+	sdk.RegisterCallbacks(callbacks)
+	sdk.running.Store(true)
+
+	var wg sync.WaitGroup
+	for i, taskType := range []string{"ml", "ml", "compute", "compute"} {
+		wg.Add(1)
+		go func(taskType string) {
+			defer wg.Done()
+			sdk.handleExecutionTask(context.Background(), &pb.ExecutionTask{TaskId: fmt.Sprintf("task-%d", i), IntentType: taskType})
+		}(taskType)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	byType := sdk.metrics.CurrentTasksByType()
+	if byType["ml"] != 1 || byType["compute"] != 2 {
+		t.Fatalf("unexpected per-type concurrency %v", byType)
+	}
+	close(handler.release)
+	wg.Wait()
+
+	if len(callbacks.rejected) != 1 {
+		t.Fatalf("expected one ml task to be rejected, got %v", callbacks.rejected)
+	}
+	if current := atomic.LoadInt32(&sdk.metrics.CurrentTasks); current != 0 {
+		t.Fatalf("expected global slots to be released, got %d in flight", current)
+	}
+}
+
+type fakeValidatorService struct {
+	pb.ValidatorServiceClient
+	err    error
+	calls  int32
+	onCall func()
+}
+
+func (f *fakeValidatorService) SubmitExecutionReport(ctx context.Context, in *pb.ExecutionReport, opts ...grpc.CallOption) (*pb.Receipt, error) {
+	atomic.AddInt32(&f.calls, 1)
+	if f.onCall != nil {
+		f.onCall()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &pb.Receipt{ReportId: in.ReportId, Status: "accepted", Phase: ReportPhaseReceived}, nil
+}
+
+// keyRecordingValidatorService records the idempotency keys sent with report submissions
+type keyRecordingValidatorService struct {
+	pb.ValidatorServiceClient
+	keys [][]string
+}
+
+func (v *keyRecordingValidatorService) SubmitExecutionReport(ctx context.Context, in *pb.ExecutionReport, opts ...grpc.CallOption) (*pb.Receipt, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	v.keys = append(v.keys, md.Get(IdempotencyKeyMetadata))
+	return &pb.Receipt{ReportId: in.ReportId, Status: "accepted"}, nil
+}
+
+func (v *keyRecordingValidatorService) SubmitExecutionReportBatch(ctx context.Context, in *pb.ExecutionReportBatchRequest, opts ...grpc.CallOption) (*pb.ExecutionReportBatchResponse, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	v.keys = append(v.keys, md.Get(IdempotencyKeyMetadata))
+	return &pb.ExecutionReportBatchResponse{}, nil
+}
+
+func TestValidatorClientSendsReportIDsAsIdempotencyKeys(t *testing.T) {
+	service := &keyRecordingValidatorService{}
+	client := &ValidatorClient{client: service}
+	ctx := context.Background()
+
+	client.SubmitExecutionReport(ctx, &pb.ExecutionReport{ReportId: "report-1"})
+	client.SubmitExecutionReportBatch(ctx, &pb.ExecutionReportBatchRequest{
+		Reports: []*pb.ExecutionReport{{ReportId: "report-2"}, {ReportId: "report-3"}},
+	})
+
+	want := [][]string{{"report-1"}, {"report-2", "report-3"}}
+	if !reflect.DeepEqual(service.keys, want) {
+		t.Fatalf("expected idempotency keys %v, got %v", want, service.keys)
+	}
+}
+
+func TestSubmitTaskReportFailsOverAcrossValidators(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	down := &fakeValidatorService{err: errors.New("unavailable")}
+	up := &fakeValidatorService{}
+	sdk.validators = []validatorTarget{
+		{addr: "validator-1:9090", client: &ValidatorClient{client: down}},
+		{addr: "validator-2:9090", client: &ValidatorClient{client: up}},
+	}
+	sdk.validatorClient = sdk.validators[0].client
+
+	receipts, err := sdk.submitTaskReport(context.Background(), "report-1", &Task{ID: "task-1", IntentID: "intent-1"}, &Result{Success: true})
+	if err != nil {
+		t.Fatalf("expected failover to succeed, got %v", err)
+	}
+	if len(receipts) != 1 || receipts[0].Endpoint != "validator-2:9090" || receipts[0].Phase != ReportPhaseReceived {
+		t.Fatalf("expected receipt from the second validator, got %+v", receipts)
+	}
+	if down.calls != 1 || up.calls != 1 {
+		t.Fatalf("expected one call per validator, got down=%d up=%d", down.calls, up.calls)
+	}
+	if snapshot := sdk.metrics.Snapshot(); snapshot.ReportsFailed != 1 || snapshot.ReportsSubmitted != 1 {
+		t.Fatalf("expected one failure and one success, got %+v", snapshot)
+	}
+}
+
+func TestPendingReportsRecordEveryRetryWithoutResultStore(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ReportMaxRetries = 2
+		cfg.ReportRetryBackoff = time.Millisecond
+	})
+	down := &fakeValidatorService{err: errors.New("unavailable")}
+	sdk.validators = []validatorTarget{{addr: "validator-1:9090", client: &ValidatorClient{client: down}}}
+	sdk.validatorClient = sdk.validators[0].client
+
+	var pending []PendingReport
+	down.onCall = func() { pending = sdk.PendingReports() }
+	sdk.reportTaskResult(context.Background(), "report-1", &Task{ID: "task-1", IntentID: "intent-1"}, &Result{Success: true})
+
+	if len(pending) != 1 || pending[0].Attempts != 3 || pending[0].LastError != "unavailable" {
+		t.Fatalf("expected the third attempt to see two recorded failures, got %+v", pending)
+	}
+	if remaining := sdk.PendingReports(); len(remaining) != 0 {
+		t.Fatalf("expected the abandoned report to be dropped without a result store, got %+v", remaining)
+	}
+}
+
+type reportAckCallbacks struct {
+	BaseCallbacks
+	mu   sync.Mutex
+	acks []string // report ID @ endpoint
+}
+
+func (c *reportAckCallbacks) OnReportSubmitted(report *ExecutionReport, receipt *ExecutionReceipt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.acks = append(c.acks, report.ReportID+"@"+receipt.Endpoint)
+}
+
+func TestSubmitTaskReportFiresReportSubmitted(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	callbacks := &reportAckCallbacks{}
+	sdk.RegisterCallbacks(callbacks)
+	sdk.config.ReportFinalizeThreshold = 2
+	sdk.validators = []validatorTarget{
+		{addr: "validator-1:9090", client: &ValidatorClient{client: &fakeValidatorService{}}},
+		{addr: "validator-2:9090", client: &ValidatorClient{client: &fakeValidatorService{err: errors.New("unavailable")}}},
+		{addr: "validator-3:9090", client: &ValidatorClient{client: &fakeValidatorService{}}},
+	}
+	sdk.validatorClient = sdk.validators[0].client
+
+	if _, err := sdk.submitTaskReport(context.Background(), "report-1", &Task{ID: "task-1", IntentID: "intent-1"}, &Result{Success: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"report-1@validator-1:9090", "report-1@validator-3:9090"}
+	if !reflect.DeepEqual(callbacks.acks, want) {
+		t.Fatalf("expected one acknowledgement per accepting validator, got %v", callbacks.acks)
+	}
+}
+
+type fakeMatcherService struct {
+	pb.MatcherServiceClient
+	mu          sync.Mutex
+	responses   []*pb.TaskResponse
+	bids        []*pb.Bid
+	batches     [][]*pb.Bid
+	bidAck      *pb.BidSubmissionAck
+	streamErr   error
+	streamCalls int
+	taskStream  *endlessTaskStream
+}
+
+func (f *fakeMatcherService) SubmitBid(ctx context.Context, in *pb.SubmitBidRequest, opts ...grpc.CallOption) (*pb.SubmitBidResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.bids = append(f.bids, in.Bid)
+	return &pb.SubmitBidResponse{Ack: f.bidAck}, nil
+}
+
+func (f *fakeMatcherService) StreamTasks(ctx context.Context, in *pb.StreamTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[pb.ExecutionTask], error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.streamCalls++
+	if f.taskStream != nil {
+		f.taskStream.ctx = ctx
+		return f.taskStream, nil
+	}
+	return nil, f.streamErr
+}
+
+// endlessTaskStream delivers a new task on every Recv until its context is cancelled
+type endlessTaskStream struct {
+	grpc.ClientStream
+	ctx   context.Context
+	recvs atomic.Int32
+}
+
+func (s *endlessTaskStream) Recv() (*pb.ExecutionTask, error) {
+	if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
+	n := s.recvs.Add(1)
+	return &pb.ExecutionTask{TaskId: fmt.Sprintf("task-%d", n), IntentId: "intent-1"}, nil
+}
+
+func (f *fakeMatcherService) RespondToTask(ctx context.Context, in *pb.RespondToTaskRequest, opts ...grpc.CallOption) (*pb.RespondToTaskResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses = append(f.responses, in.Response)
+	return &pb.RespondToTaskResponse{}, nil
+}
+
+// SubmitBidBatch accepts bids priced at 100 or more and rejects the rest
+func (f *fakeMatcherService) SubmitBidBatch(ctx context.Context, in *pb.SubmitBidBatchRequest, opts ...grpc.CallOption) (*pb.SubmitBidBatchResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, in.Bids)
+	resp := &pb.SubmitBidBatchResponse{}
+	for _, bid := range in.Bids {
+		accepted := bid.Price >= 100
+		resp.Acks = append(resp.Acks, &pb.BidSubmissionAck{BidId: bid.BidId, Accepted: accepted})
+		if accepted {
+			resp.Success++
+		} else {
+			resp.Failed++
+		}
+	}
+	return resp, nil
+}
+
+func (f *fakeMatcherService) batchSizes() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sizes := make([]int, 0, len(f.batches))
+	for _, batch := range f.batches {
+		sizes = append(sizes, len(batch))
+	}
+	return sizes
+}
+
+type decliningHandler struct {
+	blockingHandler
+}
+
+func (h *decliningHandler) CanAccept(task *Task) (bool, string) {
+	return task.Type != "ml", "no GPU available"
+}
+
+func TestHandleExecutionTaskRespondsToMatcher(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	matcher := &fakeMatcherService{}
+	sdk.matcherClient = &MatcherClient{client: matcher, logger: sdk.logger}
+	handler := &decliningHandler{blockingHandler{release: make(chan struct{})}}
+	close(handler.release)
+	callbacks := &recordingCallbacks{}
+	sdk.RegisterHandler(handler)
+	sdk.RegisterCallbacks(callbacks)
+	sdk.running.Store(true)
+
+	sdk.handleExecutionTask(context.Background(), &pb.ExecutionTask{TaskId: "task-ml", IntentType: "ml"})
+	sdk.handleExecutionTask(context.Background(), &pb.ExecutionTask{TaskId: "task-compute", IntentType: "compute"})
+
+	if len(matcher.responses) != 2 {
+		t.Fatalf("expected two task responses, got %d", len(matcher.responses))
+	}
+	if r := matcher.responses[0]; r.TaskId != "task-ml" || r.Accepted || r.Reason != "no GPU available" {
+		t.Fatalf("unexpected rejection response %+v", r)
+	}
+	if r := matcher.responses[1]; r.TaskId != "task-compute" || !r.Accepted {
+		t.Fatalf("unexpected acceptance response %+v", r)
+	}
+	if handler.executed != 1 || len(callbacks.rejected) != 1 {
+		t.Fatalf("expected only the compute task to run, executed=%d rejected=%v", handler.executed, callbacks.rejected)
+	}
+}
+
+type deadlineHandler struct {
+	deadline time.Time
+}
+
+func (h *deadlineHandler) Execute(ctx context.Context, task *Task) (*Result, error) {
+	h.deadline, _ = ctx.Deadline()
+	return &Result{Success: true}, nil
+}
+
+func TestHandleExecutionTaskHonorsDeadline(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) { cfg.TaskTimeout = time.Hour })
+	matcher := &fakeMatcherService{}
+	sdk.matcherClient = &MatcherClient{client: matcher, logger: sdk.logger}
+	handler := &deadlineHandler{}
+	callbacks := &recordingCallbacks{}
+	sdk.RegisterHandler(handler)
+	sdk.RegisterCallbacks(callbacks)
+	sdk.running.Store(true)
+
+	sdk.handleExecutionTask(context.Background(), &pb.ExecutionTask{TaskId: "task-expired", Deadline: time.Now().Add(-time.Minute).Unix()})
+	if len(callbacks.rejected) != 1 || callbacks.rejected[0] != "deadline passed" || !handler.deadline.IsZero() {
+		t.Fatalf("expected the expired task to be rejected without running, got %v", callbacks.rejected)
+	}
+	if r := matcher.responses[0]; r.Accepted || r.Reason != "deadline passed" {
+		t.Fatalf("unexpected rejection response %+v", r)
+	}
+
+	deadline := time.Now().Add(time.Minute).Truncate(time.Second)
+	sdk.handleExecutionTask(context.Background(), &pb.ExecutionTask{TaskId: "task-due", Deadline: deadline.Unix()})
+	if !handler.deadline.Equal(deadline) {
+		t.Fatalf("expected execution to be bounded by the task deadline %v, got %v", deadline, handler.deadline)
+	}
+
+	sdk.handleExecutionTask(context.Background(), &pb.ExecutionTask{TaskId: "task-open"})
+	if len(callbacks.rejected) != 1 || time.Until(handler.deadline) < 50*time.Minute {
+		t.Fatalf("expected a task without deadline to use TaskTimeout, got %v", handler.deadline)
+	}
+}
+
+func TestSubmitTaskReportSkipsInvalidReport(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	validator := &fakeValidatorService{}
+	sdk.validators = []validatorTarget{{addr: "validator-1:9090", client: &ValidatorClient{client: validator}}}
+	sdk.validatorClient = sdk.validators[0].client
+	callbacks := &recordingCallbacks{}
+	sdk.RegisterCallbacks(callbacks)
+
+	_, err := sdk.submitTaskReport(context.Background(), "report-1", &Task{ID: "task-1"}, &Result{Success: true})
+	if err == nil || !strings.Contains(err.Error(), "intent_id is required") {
+		t.Fatalf("expected the report to be rejected for its missing intent ID, got %v", err)
+	}
+	if validator.calls != 0 || len(callbacks.errs) != 1 {
+		t.Fatalf("expected no submission and one OnError, got %d calls and %v", validator.calls, callbacks.errs)
+	}
+	if failures := sdk.metrics.Snapshot().ReportsFailed; failures != 1 {
+		t.Fatalf("expected the skipped report to count as a failure, got %d", failures)
+	}
+}
+
+func TestBuildTaskReportCarriesHandlerEvidence(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ResultHashAlgorithm = "sha256"
+	})
+	task := &Task{ID: "task-1", IntentID: "intent-1"}
+
+	report, err := sdk.buildTaskReport("report-1", task, &Result{
+		Error:        "exit status 2",
+		ErrorCode:    "NONZERO_EXIT",
+		ErrorDetails: []byte(`{"exit_code":2}`),
+		Evidence: &Evidence{
+			InputsHash:    []byte("inputs"),
+			ResourceUsage: &ResourceUsage{CPUMs: 1500, MemoryMB: 256},
+			ExternalRefs:  []string{"s3://logs/task-1"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("buildTaskReport: %v", err)
+	}
+	if report.Error.GetCode() != "NONZERO_EXIT" || report.Error.GetMessage() != "exit status 2" || string(report.Error.GetDetails()) != `{"exit_code":2}` {
+		t.Fatalf("unexpected error info %v", report.Error)
+	}
+	evidence := report.Evidence
+	if string(evidence.GetInputsHash()) != "inputs" || evidence.GetResourceUsage().GetCpuMs() != 1500 ||
+		evidence.GetResourceUsage().GetMemoryMb() != 256 || len(evidence.GetExternalRefs()) != 1 {
+		t.Fatalf("handler evidence not propagated: %v", evidence)
+	}
+	if len(evidence.GetOutputsHash()) == 0 {
+		t.Fatal("expected the result hash to be merged into the handler evidence")
+	}
+
+	report, err = sdk.buildTaskReport("report-2", task, &Result{Error: "boom"})
+	if err != nil {
+		t.Fatalf("buildTaskReport: %v", err)
+	}
+	if report.Error.GetCode() != "EXECUTION_FAILED" {
+		t.Fatalf("expected the default error code, got %q", report.Error.GetCode())
+	}
+}
+
+func TestExecuteAndReportSubmitsOutOfBandTask(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	validator := &fakeValidatorService{}
+	sdk.validators = []validatorTarget{{addr: "validator-1:9090", client: &ValidatorClient{client: validator}}}
+	sdk.validatorClient = sdk.validators[0].client
+	task := &Task{ID: "task-1", IntentID: "intent-1", Type: "compute"}
+
+	if _, _, err := sdk.ExecuteAndReport(context.Background(), task); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("expected ErrNotRunning before start, got %v", err)
+	}
+	sdk.running.Store(true)
+	if _, _, err := sdk.ExecuteAndReport(context.Background(), task); !errors.Is(err, ErrNoHandler) {
+		t.Fatalf("expected ErrNoHandler without a handler, got %v", err)
+	}
+
+	sdk.RegisterHandlerFunc(func(ctx context.Context, task *Task) (*Result, error) {
+		return &Result{Data: []byte("done"), Success: true}, nil
+	})
+	result, receipts, err := sdk.ExecuteAndReport(context.Background(), task)
+	if err != nil {
+		t.Fatalf("ExecuteAndReport: %v", err)
+	}
+	if string(result.Data) != "done" || len(receipts) != 1 || receipts[0].Endpoint != "validator-1:9090" {
+		t.Fatalf("unexpected result %v and receipts %v", result, receipts)
+	}
+	if validator.calls != 1 {
+		t.Fatalf("expected one report submission, got %d", validator.calls)
+	}
+	if snap := sdk.metrics.Snapshot(); snap.ReportsSubmitted != 1 || snap.CurrentTasks != 0 {
+		t.Fatalf("expected a recorded report and released task slot, got %+v", snap)
+	}
+
+	sdk.RegisterHandlerFunc(func(ctx context.Context, task *Task) (*Result, error) {
+		return nil, errors.New("boom")
+	})
+	result, receipts, err = sdk.ExecuteAndReport(context.Background(), task)
+	if err == nil || result == nil || result.Success || len(receipts) != 1 {
+		t.Fatalf("expected the handler failure to be reported, got %v, %v, %v", result, receipts, err)
+	}
+}
+
+func TestDrainTasksWaitsForExecuteAndReport(t *testing.T) {
+	sdk := newTestSDK(t, nil)
+	validator := &fakeValidatorService{}
+	sdk.validators = []validatorTarget{{addr: "validator-1:9090", client: &ValidatorClient{client: validator}}}
+	sdk.validatorClient = sdk.validators[0].client
+	handler := &blockingHandler{release: make(chan struct{})}
+	sdk.RegisterHandler(handler)
+	sdk.running.Store(true)
+
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := sdk.ExecuteAndReport(context.Background(), &Task{ID: "task-1", IntentID: "intent-1"})
+		done <- err
+	}()
+	for sdk.inFlightTasks.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	sdk.running.Store(false)
+	if _, _, err := sdk.ExecuteAndReport(context.Background(), &Task{ID: "task-2"}); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("expected calls after Stop began to be refused, got %v", err)
+	}
+
+	time.AfterFunc(20*time.Millisecond, func() { close(handler.release) })
+	sdk.drainTasks(time.Second)
+
+	if atomic.LoadInt32(&handler.executed) != 1 || validator.calls != 1 {
+		t.Fatalf("expected the task to run and report before drain returned, executed=%d calls=%d", handler.executed, validator.calls)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("ExecuteAndReport: %v", err)
+	}
+}
+
+func TestReportFinalizerFiresAtThreshold(t *testing.T) {
+	var finalized [][]*ExecutionReceipt
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ReportFinalizeThreshold = 2
+		cfg.ReportFinalizer = func(task *Task, receipts []*ExecutionReceipt) {
+			finalized = append(finalized, receipts)
+		}
+	})
+	sdk.validators = []validatorTarget{
+		{addr: "validator-1:9090", client: &ValidatorClient{client: &fakeValidatorService{}}},
+		{addr: "validator-2:9090", client: &ValidatorClient{client: &fakeValidatorService{err: errors.New("unavailable")}}},
+		{addr: "validator-3:9090", client: &ValidatorClient{client: &fakeValidatorService{}}},
+		{addr: "validator-4:9090", client: &ValidatorClient{client: &fakeValidatorService{}}},
+	}
+	sdk.validatorClient = sdk.validators[0].client
+
+	sdk.reportTaskResult(context.Background(), "report-1", &Task{ID: "task-1", IntentID: "intent-1"}, &Result{Success: true})
+
+	if len(finalized) != 1 || len(finalized[0]) != 2 {
+		t.Fatalf("expected one finalization with 2 receipts, got %v", finalized)
+	}
+	if finalized[0][1].Endpoint != "validator-3:9090" {
+		t.Fatalf("expected the second receipt from validator-3, got %s", finalized[0][1].Endpoint)
+	}
+	if calls := sdk.validators[3].client.client.(*fakeValidatorService).calls; calls != 0 {
+		t.Fatalf("validators beyond the threshold should not be contacted, got %d calls", calls)
+	}
+}
+
+func TestTaskStreamLoopStopsAfterRejectedSubscriptions(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.MatcherSubscriptionAttempts = 2
+		cfg.ReconnectInitialBackoff = time.Millisecond
+		cfg.ReconnectMaxBackoff = time.Millisecond
+	})
+	matcher := &fakeMatcherService{streamErr: status.Error(codes.Unauthenticated, "bad signature")}
+	sdk.matcherClient = &MatcherClient{client: matcher, logger: sdk.logger}
+	callbacks := &recordingCallbacks{}
+	sdk.RegisterCallbacks(callbacks)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	sdk.matcherWG.Add(1)
+	sdk.taskStreamLoop(ctx, ctx)
+
+	if ctx.Err() != nil {
+		t.Fatal("stream loop kept retrying a rejected subscription")
+	}
+	if matcher.streamCalls != 2 {
+		t.Fatalf("expected 2 subscription attempts, got %d", matcher.streamCalls)
+	}
+	if len(callbacks.errs) != 2 {
+		t.Fatalf("expected 2 OnError calls, got %d", len(callbacks.errs))
+	}
+	var subscriptionErr *StreamSubscriptionError
+	if !errors.As(callbacks.errs[1], &subscriptionErr) || subscriptionErr.Stream != "task" {
+		t.Fatalf("expected a task StreamSubscriptionError, got %v", callbacks.errs[1])
+	}
+}
+
+func TestTaskStreamLoopStopsReadingWhenAgentIsFull(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.MaxConcurrentTasks = 1
+		cfg.TaskQueueDepth = 1
+		cfg.BidTimeout = time.Minute
+	})
+	stream := &endlessTaskStream{}
+	sdk.matcherClient = &MatcherClient{client: &fakeMatcherService{taskStream: stream}, logger: sdk.logger}
+	handler := &blockingHandler{release: make(chan struct{})}
+	callbacks := &recordingCallbacks{}
+	sdk.RegisterHandler(handler)
+	sdk.RegisterCallbacks(callbacks)
+	sdk.running.Store(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sdk.matcherWG.Add(1)
+	go sdk.taskStreamLoop(ctx, context.Background())
+	time.Sleep(100 * time.Millisecond)
+
+	// One task runs, one waits for its slot and one is held by the blocked stream reader
+	if recvs := stream.recvs.Load(); recvs != 3 {
+		t.Fatalf("expected the stream to stop after 3 receives, got %d", recvs)
+	}
+	if queued := sdk.metrics.Snapshot().QueuedTasks; queued != 1 {
+		t.Fatalf("expected one queued task, got %d", queued)
+	}
+
+	cancel()
+	sdk.matcherWG.Wait()
+	close(handler.release)
+	sdk.taskWG.Wait()
+	if handler.executed != 2 || len(callbacks.rejected) != 0 {
+		t.Fatalf("expected both dispatched tasks to run without rejections, executed=%d rejected=%v", handler.executed, callbacks.rejected)
+	}
+}
+
+func TestClassifyStreamError(t *testing.T) {
+	var subscriptionErr *StreamSubscriptionError
+	if err := classifyStreamError("task", status.Error(codes.PermissionDenied, "denied")); !errors.As(err, &subscriptionErr) {
+		t.Fatalf("expected PermissionDenied to be a subscription error, got %T", err)
+	}
+	var recvErr *StreamReceiveError
+	if err := classifyStreamError("intent", status.Error(codes.Unavailable, "gone")); !errors.As(err, &recvErr) || recvErr.Stream != "intent" {
+		t.Fatalf("expected Unavailable to be a receive error, got %T", err)
+	}
+}
+
+func TestIntentFromUpdate(t *testing.T) {
+	intent := intentFromUpdate(&pb.MatcherIntentUpdate{IntentId: "intent-1", UpdateType: "compute", Timestamp: 1700000000})
+	if intent.ID != "intent-1" || intent.Type != "compute" || intent.CreatedAt.Unix() != 1700000000 {
+		t.Fatalf("unexpected intent %+v", intent)
+	}
+	if intent.Data != nil || intent.Metadata != nil {
+		t.Fatalf("expected no payload for an update without one, got %+v", intent)
+	}
+}
+
+func TestHandleExecutionTaskSkipsRedeliveredTasks(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.MaxConcurrentTasks = 1
+		cfg.BidTimeout = 20 * time.Millisecond
+	})
+	handler := &blockingHandler{release: make(chan struct{})}
+	close(handler.release)
+	callbacks := &recordingCallbacks{}
+	sdk.RegisterHandler(handler)
+	sdk.RegisterCallbacks(callbacks)
+	sdk.running.Store(true)
+
+	sdk.handleExecutionTask(context.Background(), &pb.ExecutionTask{TaskId: "task-1"})
+	sdk.handleExecutionTask(context.Background(), &pb.ExecutionTask{TaskId: "task-1"})
+	if handler.executed != 1 {
+		t.Fatalf("expected a re-delivered task to be skipped, executed %d times", handler.executed)
+	}
+
+	// A task declined at capacity may be delivered again
+	sdk.taskQueue.acquire(context.Background(), time.Time{}, nil)
+	sdk.handleExecutionTask(context.Background(), &pb.ExecutionTask{TaskId: "task-2"})
+	sdk.taskQueue.release()
+	sdk.handleExecutionTask(context.Background(), &pb.ExecutionTask{TaskId: "task-2"})
+	if handler.executed != 2 || len(callbacks.rejected) != 1 {
+		t.Fatalf("expected the declined task to run on re-delivery, executed=%d rejected=%v", handler.executed, callbacks.rejected)
+	}
+}
+
+func TestBidOutcomesReportedOnlyForOwnBids(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.PrivateKey = testPrivateKey
+	})
+	callbacks := &recordingCallbacks{}
+	sdk.RegisterCallbacks(callbacks)
+	sdk.matcherClient = &MatcherClient{client: &fakeMatcherService{bidAck: &pb.BidSubmissionAck{Accepted: true}}, logger: sdk.logger}
+	sdk.running.Store(true)
+
+	for _, intentID := range []string{"intent-won", "intent-lost"} {
+		if _, err := sdk.SubmitBid(context.Background(), intentID, &Bid{Price: 100}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	sdk.handleIntentUpdate(ctx, &pb.MatcherIntentUpdate{IntentId: "intent-won", UpdateType: IntentUpdateBidWon})
+	sdk.handleIntentUpdate(ctx, &pb.MatcherIntentUpdate{IntentId: "intent-lost", UpdateType: "BID_LOST"})
+	sdk.handleIntentUpdate(ctx, &pb.MatcherIntentUpdate{IntentId: "intent-other", UpdateType: IntentUpdateBidWon})
+	// A repeated outcome for an already resolved intent is not reported twice
+	sdk.handleIntentUpdate(ctx, &pb.MatcherIntentUpdate{IntentId: "intent-won", UpdateType: IntentUpdateBidWon})
+
+	if len(callbacks.won) != 1 || callbacks.won[0] != "intent-won" {
+		t.Fatalf("expected only intent-won to be reported as won, got %v", callbacks.won)
+	}
+	if len(callbacks.lost) != 1 || callbacks.lost[0] != "intent-lost" {
+		t.Fatalf("expected only intent-lost to be reported as lost, got %v", callbacks.lost)
+	}
+	if earnings := sdk.metrics.Snapshot().TotalEarnings; earnings != 100 {
+		t.Fatalf("expected earnings from the won bid only, got %d", earnings)
+	}
+}
+
+type fixedPriceStrategy uint64
+
+func (s fixedPriceStrategy) ShouldBid(intent *Intent) bool    { return true }
+func (s fixedPriceStrategy) CalculateBid(intent *Intent) *Bid { return &Bid{Price: uint64(s)} }
+
+type submittedBidCallbacks struct {
+	recordingCallbacks
+	submitted []*Bid
+}
+
+func (c *submittedBidCallbacks) OnBidSubmitted(intent *Intent, bid *Bid) {
+	c.submitted = append(c.submitted, bid)
+}
+
+func TestBidDryRunSkipsSubmission(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.BidDryRun = true
+	})
+	matcher := &fakeMatcherService{bidAck: &pb.BidSubmissionAck{Accepted: true}}
+	sdk.matcherClient = &MatcherClient{client: matcher, logger: sdk.logger}
+	callbacks := &submittedBidCallbacks{}
+	sdk.RegisterCallbacks(callbacks)
+	sdk.RegisterBiddingStrategy(fixedPriceStrategy(100))
+	sdk.running.Store(true)
+
+	sdk.handleIntentUpdate(context.Background(), &pb.MatcherIntentUpdate{IntentId: "intent-1", UpdateType: "new"})
+
+	if len(matcher.bids) != 0 {
+		t.Fatalf("expected no bid to reach the matcher, got %d", len(matcher.bids))
+	}
+	if len(callbacks.submitted) != 1 || !callbacks.submitted[0].DryRun || callbacks.submitted[0].Price != 100 {
+		t.Fatalf("expected OnBidSubmitted for a dry-run bid, got %v", callbacks.submitted)
+	}
+	if snap := sdk.metrics.Snapshot(); snap.SimulatedBids != 1 || snap.TotalBids != 0 {
+		t.Fatalf("expected a simulated bid kept apart from real bids, got %+v", snap)
+	}
+
+	// Bids submitted explicitly are not simulated
+	if _, err := sdk.SubmitBid(context.Background(), "intent-2", &Bid{Price: 100}); err != nil || len(matcher.bids) != 1 {
+		t.Fatalf("expected SubmitBid to reach the matcher, got %d bids and %v", len(matcher.bids), err)
+	}
+}
+
+func TestSubmitBidReturnsMatcherAck(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.PrivateKey = testPrivateKey
+	})
+	if _, err := sdk.SubmitBid(context.Background(), "intent-1", &Bid{Price: 100}); err == nil {
+		t.Fatal("expected an error before the SDK is running")
+	}
+
+	matcher := &fakeMatcherService{bidAck: &pb.BidSubmissionAck{Accepted: true, Status: pb.BidStatus_BID_STATUS_ACCEPTED, RecordedAt: 1700000000}}
+	sdk.matcherClient = &MatcherClient{client: matcher, logger: sdk.logger}
+	sdk.running.Store(true)
+
+	receipt, err := sdk.SubmitBid(context.Background(), "intent-1", &Bid{Price: 100, Currency: "PIN"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matcher.bids) != 1 {
+		t.Fatalf("expected one bid submitted, got %d", len(matcher.bids))
+	}
+	sent := matcher.bids[0]
+	if sent.IntentId != "intent-1" || sent.AgentId != "agent-1" || sent.Price != 100 || sent.Metadata[chainAddressMetadataKey] != sdk.GetChainAddress() {
+		t.Fatalf("unexpected bid sent to matcher: %+v", sent)
+	}
+	if !receipt.Accepted || receipt.BidID != sent.BidId || receipt.IntentID != "intent-1" || receipt.RecordedAt.Unix() != 1700000000 {
+		t.Fatalf("unexpected receipt: %+v", receipt)
+	}
+	if snapshot := sdk.metrics.Snapshot(); snapshot.TotalBids != 1 || snapshot.SuccessfulBids != 1 {
+		t.Fatalf("expected the bid to be recorded in metrics, got %+v", snapshot)
+	}
+}
+
+func TestTaskExecutionDeadlinePrefersEarliestLimit(t *testing.T) {
+	sdk := newTestSDK(t, func(cfg *Config) { cfg.TaskTimeout = time.Minute })
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		task *Task
+		want time.Time
+	}{
+		{"global timeout", &Task{}, now.Add(time.Minute)},
+		{"shorter metadata timeout", &Task{Metadata: map[string]string{TaskTimeoutMetadataKey: "10s"}}, now.Add(10 * time.Second)},
+		{"longer metadata timeout", &Task{Metadata: map[string]string{TaskTimeoutMetadataKey: "1h"}}, now.Add(time.Minute)},
+		{"invalid metadata timeout", &Task{Metadata: map[string]string{TaskTimeoutMetadataKey: "soon"}}, now.Add(time.Minute)},
+		{"earlier task deadline", &Task{Deadline: now.Add(5 * time.Second), Metadata: map[string]string{TaskTimeoutMetadataKey: "10s"}}, now.Add(5 * time.Second)},
+		{"past task deadline", &Task{Deadline: now.Add(-time.Second)}, now.Add(time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sdk.taskExecutionDeadline(tt.task, now); !got.Equal(tt.want) {
+				t.Fatalf("expected deadline %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
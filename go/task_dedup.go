@@ -0,0 +1,86 @@
+package agentsdk
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTaskDedupWindow   = 10 * time.Minute
+	defaultTaskDedupCapacity = 10000
+)
+
+// seenTask is a task ID and when it was first processed
+type seenTask struct {
+	id     string
+	seenAt time.Time
+}
+
+// taskDeduper remembers recently processed task IDs so re-delivered tasks are not executed twice.
+// Entries expire after window; when more than capacity IDs are tracked the oldest are evicted.
+type taskDeduper struct {
+	mu       sync.Mutex
+	window   time.Duration
+	capacity int
+	order    *list.List // oldest first
+	seen     map[string]*list.Element
+}
+
+// newTaskDeduper returns nil when window is negative, which disables deduplication
+func newTaskDeduper(window time.Duration, capacity int) *taskDeduper {
+	if window < 0 {
+		return nil
+	}
+	return &taskDeduper{
+		window:   window,
+		capacity: capacity,
+		order:    list.New(),
+		seen:     make(map[string]*list.Element),
+	}
+}
+
+// checkAndRecord reports whether id was already processed within the window, recording it if not
+func (d *taskDeduper) checkAndRecord(id string, now time.Time) bool {
+	if d == nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.evict(now)
+	if _, ok := d.seen[id]; ok {
+		return true
+	}
+	d.seen[id] = d.order.PushBack(seenTask{id: id, seenAt: now})
+	for d.order.Len() > d.capacity {
+		d.remove(d.order.Front())
+	}
+	return false
+}
+
+// forget drops id so a task that was declined can be delivered again
+func (d *taskDeduper) forget(id string) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if elem, ok := d.seen[id]; ok {
+		d.remove(elem)
+	}
+}
+
+func (d *taskDeduper) evict(now time.Time) {
+	for front := d.order.Front(); front != nil; front = d.order.Front() {
+		if now.Sub(front.Value.(seenTask).seenAt) < d.window {
+			return
+		}
+		d.remove(front)
+	}
+}
+
+func (d *taskDeduper) remove(elem *list.Element) {
+	d.order.Remove(elem)
+	delete(d.seen, elem.Value.(seenTask).id)
+}
@@ -0,0 +1,32 @@
+package agentsdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskDeduperExpiresAndEvicts(t *testing.T) {
+	dedup := newTaskDeduper(time.Minute, 2)
+	start := time.Now()
+
+	if dedup.checkAndRecord("task-1", start) {
+		t.Fatal("first delivery should not be a duplicate")
+	}
+	if !dedup.checkAndRecord("task-1", start.Add(30*time.Second)) {
+		t.Fatal("re-delivery within the window should be a duplicate")
+	}
+	if dedup.checkAndRecord("task-1", start.Add(2*time.Minute)) {
+		t.Fatal("re-delivery after the window should be processed again")
+	}
+
+	now := start.Add(3 * time.Minute)
+	dedup.checkAndRecord("task-2", now)
+	dedup.checkAndRecord("task-3", now)
+	if dedup.checkAndRecord("task-1", now) {
+		t.Fatal("oldest entry should be evicted once capacity is exceeded")
+	}
+
+	if disabled := newTaskDeduper(-1, 2); disabled.checkAndRecord("task-1", now) || disabled.checkAndRecord("task-1", now) {
+		t.Fatal("a negative window should disable deduplication")
+	}
+}
@@ -0,0 +1,129 @@
+package agentsdk
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultTaskQueueDepth = 100
+
+// taskQueue hands out the MaxConcurrentTasks execution slots. While every slot is busy, tasks wait
+// in a queue ordered by deadline (soonest first, tasks without a deadline last, ties in arrival
+// order) and each freed slot goes to the head of the queue, so a near-deadline task is not stuck
+// behind tasks that arrived earlier.
+type taskQueue struct {
+	mu       sync.Mutex
+	free     int
+	maxDepth int
+	seq      uint64
+	waiting  taskWaiters
+	metrics  *Metrics
+}
+
+// taskWaiter is a task waiting in the queue for a slot
+type taskWaiter struct {
+	deadline time.Time
+	seq      uint64
+	granted  chan struct{} // closed once the waiter holds a slot
+	index    int           // position in the heap, -1 once removed
+}
+
+func newTaskQueue(slots, maxDepth int, metrics *Metrics) *taskQueue {
+	return &taskQueue{free: slots, maxDepth: maxDepth, metrics: metrics}
+}
+
+// acquire takes a slot for a task with the given deadline, queueing the task while all slots are
+// busy. It returns "task queue full" when the queue is at its maximum depth and "at capacity" when
+// timeout fires or ctx is done before a slot frees up.
+func (q *taskQueue) acquire(ctx context.Context, deadline time.Time, timeout <-chan time.Time) (string, bool) {
+	q.mu.Lock()
+	if q.free > 0 && len(q.waiting) == 0 {
+		q.free--
+		q.mu.Unlock()
+		return "", true
+	}
+	if len(q.waiting) >= q.maxDepth {
+		q.mu.Unlock()
+		return "task queue full", false
+	}
+	q.seq++
+	waiter := &taskWaiter{deadline: deadline, seq: q.seq, granted: make(chan struct{})}
+	heap.Push(&q.waiting, waiter)
+	q.recordDepth()
+	q.mu.Unlock()
+
+	select {
+	case <-waiter.granted:
+		return "", true
+	case <-timeout:
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if waiter.index < 0 {
+		// The slot was handed over while giving up; keep it
+		return "", true
+	}
+	heap.Remove(&q.waiting, waiter.index)
+	q.recordDepth()
+	return "at capacity", false
+}
+
+// release frees a slot, handing it straight to the queued task with the soonest deadline
+func (q *taskQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.waiting) == 0 {
+		q.free++
+		return
+	}
+	waiter := heap.Pop(&q.waiting).(*taskWaiter)
+	q.recordDepth()
+	close(waiter.granted)
+}
+
+func (q *taskQueue) recordDepth() {
+	q.metrics.RecordQueuedTasks(len(q.waiting))
+}
+
+// taskWaiters is a container/heap of waiters ordered by deadline, then arrival
+type taskWaiters []*taskWaiter
+
+func (w taskWaiters) Len() int { return len(w) }
+
+func (w taskWaiters) Less(i, j int) bool {
+	a, b := w[i], w[j]
+	switch {
+	case a.deadline.IsZero() != b.deadline.IsZero():
+		return b.deadline.IsZero()
+	case !a.deadline.Equal(b.deadline):
+		return a.deadline.Before(b.deadline)
+	default:
+		return a.seq < b.seq
+	}
+}
+
+func (w taskWaiters) Swap(i, j int) {
+	w[i], w[j] = w[j], w[i]
+	w[i].index = i
+	w[j].index = j
+}
+
+func (w *taskWaiters) Push(x any) {
+	waiter := x.(*taskWaiter)
+	waiter.index = len(*w)
+	*w = append(*w, waiter)
+}
+
+func (w *taskWaiters) Pop() any {
+	old := *w
+	n := len(old)
+	waiter := old[n-1]
+	old[n-1] = nil
+	waiter.index = -1
+	*w = old[:n-1]
+	return waiter
+}
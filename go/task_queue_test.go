@@ -0,0 +1,96 @@
+package agentsdk
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTaskQueueGrantsSoonestDeadlineFirst(t *testing.T) {
+	metrics := NewMetrics()
+	queue := newTaskQueue(1, 10, metrics)
+	ctx := context.Background()
+	if _, ok := queue.acquire(ctx, time.Time{}, nil); !ok {
+		t.Fatal("expected the free slot to be granted immediately")
+	}
+
+	now := time.Now()
+	taskDeadlines := map[string]time.Time{
+		"none":  {},
+		"late":  now.Add(time.Hour),
+		"soon":  now.Add(time.Minute),
+		"later": now.Add(2 * time.Hour),
+	}
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	for name, taskDeadline := range taskDeadlines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := queue.acquire(ctx, taskDeadline, nil); !ok {
+				t.Errorf("task %s was not granted a slot", name)
+				return
+			}
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			queue.release()
+		}()
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for metrics.Snapshot().QueuedTasks < 4 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	queue.release()
+	wg.Wait()
+
+	want := []string{"soon", "late", "later", "none"}
+	for i, name := range want {
+		if order[i] != name {
+			t.Fatalf("expected slots in deadline order %v, got %v", want, order)
+		}
+	}
+	if depth := metrics.Snapshot().QueuedTasks; depth != 0 {
+		t.Fatalf("expected an empty queue, got depth %d", depth)
+	}
+}
+
+func TestTaskQueueRejectsWhenFullOrTimedOut(t *testing.T) {
+	metrics := NewMetrics()
+	queue := newTaskQueue(1, 1, metrics)
+	ctx := context.Background()
+	queue.acquire(ctx, time.Time{}, nil)
+
+	timeout := make(chan time.Time)
+	done := make(chan string)
+	go func() {
+		reason, _ := queue.acquire(ctx, time.Time{}, timeout)
+		done <- reason
+	}()
+	deadline := time.Now().Add(time.Second)
+	for metrics.Snapshot().QueuedTasks == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if reason, ok := queue.acquire(ctx, time.Time{}, nil); ok || reason != "task queue full" {
+		t.Fatalf("expected a full queue to reject the task, got %q", reason)
+	}
+
+	close(timeout)
+	if reason := <-done; reason != "at capacity" {
+		t.Fatalf("expected the queued task to give up at capacity, got %q", reason)
+	}
+	if depth := metrics.Snapshot().QueuedTasks; depth != 0 {
+		t.Fatalf("expected the timed-out task to leave the queue, got depth %d", depth)
+	}
+
+	queue.release()
+	if _, ok := queue.acquire(ctx, time.Time{}, nil); !ok {
+		t.Fatal("expected the released slot to be free again")
+	}
+}
@@ -0,0 +1,72 @@
+// Package testutil provides handlers for load-testing the SDK's task and report pipeline
+// without doing real work.
+package testutil
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	sdk "github.com/PIN-AI/subnet-sdk/go"
+)
+
+// NoopHandler completes every task successfully and immediately
+type NoopHandler struct {
+	executed atomic.Int64
+}
+
+// Execute returns a successful, empty result
+func (h *NoopHandler) Execute(ctx context.Context, task *sdk.Task) (*sdk.Result, error) {
+	h.executed.Add(1)
+	return &sdk.Result{Success: true}, nil
+}
+
+// Executed returns how many tasks the handler has run
+func (h *NoopHandler) Executed() int64 {
+	return h.executed.Load()
+}
+
+// LatencyHandler simulates compute by sleeping before completing each task successfully
+type LatencyHandler struct {
+	min      time.Duration
+	max      time.Duration
+	executed atomic.Int64
+}
+
+// NewLatencyHandler returns a handler that takes d to complete every task
+func NewLatencyHandler(d time.Duration) *LatencyHandler {
+	return &LatencyHandler{min: d, max: d}
+}
+
+// NewRandomLatencyHandler returns a handler whose tasks take a uniformly random duration in [min, max]
+func NewRandomLatencyHandler(min, max time.Duration) *LatencyHandler {
+	if max < min {
+		max = min
+	}
+	return &LatencyHandler{min: min, max: max}
+}
+
+// Execute sleeps for the configured latency, returning ctx.Err() if the task is cancelled first
+func (h *LatencyHandler) Execute(ctx context.Context, task *sdk.Task) (*sdk.Result, error) {
+	delay := h.min
+	if h.max > h.min {
+		delay += rand.N(h.max - h.min + 1)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		h.executed.Add(1)
+		return &sdk.Result{Success: true}, nil
+	}
+}
+
+// Executed returns how many tasks the handler has completed
+func (h *LatencyHandler) Executed() int64 {
+	return h.executed.Load()
+}
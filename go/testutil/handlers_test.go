@@ -0,0 +1,39 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/PIN-AI/subnet-sdk/go"
+)
+
+func TestLatencyHandlerWaitsForLatency(t *testing.T) {
+	handler := NewRandomLatencyHandler(10*time.Millisecond, 20*time.Millisecond)
+
+	start := time.Now()
+	result, err := handler.Execute(context.Background(), &sdk.Task{ID: "task-1"})
+	if err != nil || !result.Success {
+		t.Fatalf("expected success, got %+v, %v", result, err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Fatalf("expected at least 10ms latency, took %v", elapsed)
+	}
+	if handler.Executed() != 1 {
+		t.Fatalf("expected one executed task, got %d", handler.Executed())
+	}
+}
+
+func TestLatencyHandlerHonorsCancellation(t *testing.T) {
+	handler := NewLatencyHandler(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := handler.Execute(ctx, &sdk.Task{ID: "task-1"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if handler.Executed() != 0 {
+		t.Fatalf("cancelled task should not count as executed")
+	}
+}
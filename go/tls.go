@@ -0,0 +1,71 @@
+package agentsdk
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// newClientTLSConfig builds the TLS configuration for outgoing connections from CertFile/KeyFile
+// (client certificate for mutual TLS) and CAFile (root CAs; system roots when empty). It returns
+// nil when none of them is set and TLS is disabled. gRPC only uses it when UseTLS is set; the
+// HTTP client uses it for https registry and validator URLs.
+func newClientTLSConfig(config *Config) (*tls.Config, error) {
+	if !config.UseTLS && config.CertFile == "" && config.KeyFile == "" && config.CAFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		if config.CertFile == "" || config.KeyFile == "" {
+			return nil, errors.New("cert_file and key_file must be configured together")
+		}
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate %s: %w", config.CertFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if config.CAFile != "" {
+		pem, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_file %s contains no PEM certificates", config.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	// For testing against self-signed servers only; ignored unless TLS is enabled
+	if config.UseTLS && config.InsecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	return tlsConfig, nil
+}
+
+// newHTTPTransport returns a transport verifying servers with tlsConfig, or nil for the default transport
+func newHTTPTransport(tlsConfig *tls.Config) http.RoundTripper {
+	if tlsConfig == nil {
+		return nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig.Clone()
+	return transport
+}
+
+// tlsDialOption returns transport credentials for tlsConfig. Passed as an extra option to DialOption,
+// it replaces the default server-only TLS credentials.
+func tlsDialOption(tlsConfig *tls.Config) grpc.DialOption {
+	return grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+}
@@ -0,0 +1,169 @@
+package agentsdk
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTestCertificate writes a self-signed certificate and its key to dir
+func writeTestCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "agent-1"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestNewClientTLSConfigLoadsClientCertificateAndCA(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t, t.TempDir())
+
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.UseTLS = true
+		cfg.CertFile = certFile
+		cfg.KeyFile = keyFile
+		cfg.CAFile = certFile
+	})
+	if sdk.tlsConfig == nil || len(sdk.tlsConfig.Certificates) != 1 || sdk.tlsConfig.RootCAs == nil {
+		t.Fatalf("expected a client certificate and custom root CAs, got %+v", sdk.tlsConfig)
+	}
+	if len(sdk.grpcDialOptions()) != 1 {
+		t.Fatal("expected TLS transport credentials in the dial options")
+	}
+
+	sdk = newTestSDK(t, func(cfg *Config) { cfg.UseTLS = true })
+	if sdk.tlsConfig == nil || sdk.tlsConfig.RootCAs != nil || len(sdk.tlsConfig.Certificates) != 0 {
+		t.Fatalf("expected system roots and no client certificate, got %+v", sdk.tlsConfig)
+	}
+
+	if sdk := newTestSDK(t, func(cfg *Config) { cfg.CertFile, cfg.KeyFile = certFile, keyFile }); sdk.tlsConfig != nil {
+		t.Fatal("expected no TLS config when TLS is disabled")
+	}
+}
+
+func TestNewRejectsInvalidTLSFiles(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t, t.TempDir())
+
+	tests := []struct {
+		name   string
+		mutate func(cfg *Config)
+		errMsg string
+	}{
+		{"cert without key", func(cfg *Config) { cfg.CertFile = certFile }, "configured together"},
+		{"missing cert", func(cfg *Config) { cfg.CertFile, cfg.KeyFile = "missing.pem", keyFile }, "load client certificate"},
+		{"missing ca", func(cfg *Config) { cfg.CAFile = "missing-ca.pem" }, "ca_file"},
+		{"ca without certificates", func(cfg *Config) { cfg.CAFile = keyFile }, "no PEM certificates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{AgentID: "agent-1", MatcherAddr: "matcher:8090", Capabilities: []string{"compute"}, UseTLS: true}
+			tt.mutate(cfg)
+			if _, err := New(cfg); err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestRootCAsVerifyHTTPSReportTargets(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"report_id":"report-1","status":"accepted"}`))
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	newSDK := func(caFile string) *SDK {
+		return newTestSDK(t, func(cfg *Config) {
+			cfg.CAFile = caFile
+			cfg.ValidatorEndpointResolver = func(ctx context.Context) ([]ValidatorEndpoint, error) {
+				return []ValidatorEndpoint{{ID: "v1", Endpoint: server.URL}}, nil
+			}
+		})
+	}
+	report := &ExecutionReport{ReportID: "report-1", AssignmentID: "task-1", IntentID: "intent-1"}
+
+	if _, err := newSDK("").SubmitExecutionReport(context.Background(), report); err == nil {
+		t.Fatal("expected the internal CA to be rejected with system roots")
+	}
+	sdk := newSDK(caFile)
+	if sdk.tlsConfig != nil {
+		t.Fatal("expected gRPC to stay plaintext unless TLS is enabled")
+	}
+	if _, err := sdk.SubmitExecutionReport(context.Background(), report); err != nil {
+		t.Fatalf("expected the report to verify against the configured CA, got %v", err)
+	}
+}
+
+func TestInsecureSkipVerifyOnlyAppliesWithTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"report_id":"report-1","status":"accepted"}`))
+	}))
+	defer server.Close()
+
+	newSDK := func(useTLS bool) *SDK {
+		return newTestSDK(t, func(cfg *Config) {
+			cfg.UseTLS = useTLS
+			cfg.InsecureSkipVerify = true
+			cfg.ValidatorEndpointResolver = func(ctx context.Context) ([]ValidatorEndpoint, error) {
+				return []ValidatorEndpoint{{ID: "v1", Endpoint: server.URL}}, nil
+			}
+		})
+	}
+	report := &ExecutionReport{ReportID: "report-1", AssignmentID: "task-1", IntentID: "intent-1"}
+
+	if _, err := newSDK(false).SubmitExecutionReport(context.Background(), report); err == nil {
+		t.Fatal("expected InsecureSkipVerify to be ignored without TLS")
+	}
+	sdk := newSDK(true)
+	if !sdk.tlsConfig.InsecureSkipVerify {
+		t.Fatal("expected gRPC TLS to skip verification")
+	}
+	if _, err := sdk.SubmitExecutionReport(context.Background(), report); err != nil {
+		t.Fatalf("expected the self-signed validator to be accepted, got %v", err)
+	}
+}
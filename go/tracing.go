@@ -0,0 +1,105 @@
+package agentsdk
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Span names and attribute keys recorded by the SDK
+const (
+	SpanTaskExecute = "subnet.task"
+
+	AttrTaskID         = "subnet.task.id"
+	AttrIntentID       = "subnet.intent.id"
+	AttrTaskType       = "subnet.task.type"
+	AttrTaskAccepted   = "subnet.task.accepted"
+	AttrReportID       = "subnet.report.id"
+	AttrReportStatus   = "subnet.report.status"
+	AttrReportReceipts = "subnet.report.receipts"
+)
+
+// Report statuses recorded on the task span
+const (
+	reportStatusSubmitted = "submitted"
+	reportStatusFailed    = "failed"
+	reportStatusDropped   = "dropped"
+	reportStatusSkipped   = "skipped"
+)
+
+// Attribute is a span attribute
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Tracer creates the spans the SDK records around streamed tasks. It mirrors the subset of the
+// OpenTelemetry trace API the SDK needs, so the SDK takes no tracing dependency; see the API
+// reference for an adapter over an OpenTelemetry TracerProvider.
+type Tracer interface {
+	// Start begins a span named name as a child of any span carried by ctx
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+	// Inject writes the trace context of ctx into carrier, e.g. as W3C traceparent headers
+	Inject(ctx context.Context, carrier map[string]string)
+}
+
+// Span is an in-progress trace span
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// noopTracer is used when no Tracer is configured
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+func (noopTracer) Inject(ctx context.Context, carrier map[string]string) {}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+func (noopSpan) RecordError(err error)            {}
+func (noopSpan) End()                             {}
+
+// traceMetadata captures the trace context of ctx for propagation once the report is submitted
+// from a worker goroutine; it returns nil when tracing is disabled
+func (sdk *SDK) traceMetadata(ctx context.Context) map[string]string {
+	if _, ok := sdk.tracer.(noopTracer); ok {
+		return nil
+	}
+	carrier := make(map[string]string)
+	sdk.tracer.Inject(ctx, carrier)
+	return carrier
+}
+
+// withTraceMetadata adds propagated trace headers to the outgoing gRPC metadata of ctx
+func withTraceMetadata(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	kv := make([]string, 0, len(carrier)*2)
+	for key, value := range carrier {
+		kv = append(kv, key, value)
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// endSpan records the report outcome on the task span and ends it
+func (job reportJob) endSpan(status string, receipts int, err error) {
+	if job.span == nil {
+		return
+	}
+	job.span.SetAttributes(
+		Attribute{Key: AttrReportStatus, Value: status},
+		Attribute{Key: AttrReportReceipts, Value: strconv.Itoa(receipts)},
+	)
+	if err != nil {
+		job.span.RecordError(err)
+	}
+	job.span.End()
+}
@@ -0,0 +1,94 @@
+package agentsdk
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	pb "subnet/proto/subnet"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type recordingSpan struct {
+	mu    sync.Mutex
+	attrs map[string]string
+	ended chan struct{}
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error) {}
+func (s *recordingSpan) End()                  { close(s.ended) }
+
+type recordingTracer struct {
+	span *recordingSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	t.span.SetAttributes(attrs...)
+	return ctx, t.span
+}
+
+func (t *recordingTracer) Inject(ctx context.Context, carrier map[string]string) {
+	carrier["traceparent"] = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+}
+
+type metadataCapturingValidator struct {
+	pb.ValidatorServiceClient
+	md metadata.MD
+}
+
+func (v *metadataCapturingValidator) SubmitExecutionReport(ctx context.Context, in *pb.ExecutionReport, opts ...grpc.CallOption) (*pb.Receipt, error) {
+	v.md, _ = metadata.FromOutgoingContext(ctx)
+	return &pb.Receipt{ReportId: in.ReportId, Status: "accepted"}, nil
+}
+
+func TestTracerSpansTaskThroughReport(t *testing.T) {
+	span := &recordingSpan{attrs: map[string]string{}, ended: make(chan struct{})}
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.Tracer = &recordingTracer{span: span}
+	})
+	validator := &metadataCapturingValidator{}
+	sdk.validatorClient = &ValidatorClient{client: validator}
+	sdk.validators = []validatorTarget{{addr: "validator-1:9090", client: sdk.validatorClient}}
+	handler := &blockingHandler{release: make(chan struct{})}
+	close(handler.release)
+	sdk.RegisterHandler(handler)
+	sdk.running.Store(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sdk.taskCancel = cancel
+	sdk.startReportWorkers(ctx)
+	sdk.handleExecutionTask(ctx, &pb.ExecutionTask{TaskId: "task-1", IntentId: "intent-1", IntentType: "compute"})
+
+	select {
+	case <-span.ended:
+	case <-time.After(5 * time.Second):
+		t.Fatal("task span was not ended after the report was submitted")
+	}
+	sdk.drainTasks(time.Second)
+
+	for key, want := range map[string]string{
+		AttrTaskID:         "task-1",
+		AttrIntentID:       "intent-1",
+		AttrTaskAccepted:   "true",
+		AttrReportStatus:   reportStatusSubmitted,
+		AttrReportReceipts: "1",
+	} {
+		if got := span.attrs[key]; got != want {
+			t.Errorf("span attribute %s = %q, want %q", key, got, want)
+		}
+	}
+	if got := validator.md.Get("traceparent"); len(got) != 1 {
+		t.Fatalf("expected trace context in report metadata, got %v", validator.md)
+	}
+}
@@ -2,6 +2,8 @@ package agentsdk
 
 import (
 	"context"
+	"encoding/json"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -19,10 +21,33 @@ type Task struct {
 
 // Result represents the execution result
 type Result struct {
-	Data     []byte            // Result data
-	Success  bool              // Whether execution was successful
-	Error    string            // Error message if failed
-	Metadata map[string]string // Result metadata
+	Data         []byte            // Result data
+	Success      bool              // Whether execution was successful
+	Error        string            // Error message if failed
+	ErrorCode    string            // Error code reported to validators if failed (default "EXECUTION_FAILED")
+	ErrorDetails []byte            // Structured failure details such as captured output or exit code
+	Evidence     *Evidence         // Verification evidence forwarded to validators
+	Metadata     map[string]string // Result metadata
+}
+
+// Evidence is verification evidence a handler attaches to its result. It is sent with the
+// execution report for streamed tasks; the outputs hash is filled in by the SDK when
+// ResultHashAlgorithm is set.
+type Evidence struct {
+	EnvFingerprint []byte         // Image, wasm or code hash and version
+	InputsHash     []byte         // Hash of the task inputs
+	TranscriptRoot []byte         // Root of the execution transcript
+	ProofExec      []byte         // Serialized execution proof
+	ResourceUsage  *ResourceUsage // Resources consumed by the execution
+	ExternalRefs   []string       // References to externally stored artifacts
+}
+
+// ResourceUsage reports the resources a task execution consumed
+type ResourceUsage struct {
+	CPUMs        uint64 // CPU time in milliseconds
+	MemoryMB     uint64 // Peak memory in megabytes
+	IOOps        uint64 // I/O operations
+	NetworkBytes uint64 // Bytes sent and received
 }
 
 // ExecutionReportStatus represents execution report status values understood by validators
@@ -53,32 +78,67 @@ type ExecutionReceipt struct {
 	IntentID    string
 	ValidatorID string
 	Status      string
+	Phase       string // validation phase when the receipt was issued; empty if the validator sent none
 	ReceivedAt  time.Time
 	Message     string
 	Endpoint    string
 }
 
+// Validation phases a validator reports in ExecutionReceipt.Phase, in order. VERIFIED and
+// REJECTED are final.
+const (
+	ReportPhaseReceived   = "RECEIVED"
+	ReportPhaseValidating = "VALIDATING"
+	ReportPhaseVerified   = "VERIFIED"
+	ReportPhaseRejected   = "REJECTED"
+)
+
+// Final reports whether the receipt's phase is final, i.e. the validator has verified or rejected
+// the report
+func (r *ExecutionReceipt) Final() bool {
+	return r.Phase == ReportPhaseVerified || r.Phase == ReportPhaseRejected
+}
+
 // Intent represents an intent for bidding
+//
+// Intents built from matcher stream updates only carry ID, Type and CreatedAt:
+// MatcherIntentUpdate has no description, payload or requirements fields yet, so
+// Description, Data and Metadata stay empty until the matcher protocol provides them.
 type Intent struct {
-	ID          string    // Intent ID
-	Type        string    // Intent type
-	Description string    // Intent description
-	CreatedAt   time.Time // When the intent was created
+	ID          string            // Intent ID
+	Type        string            // Intent type
+	Description string            // Intent description
+	Data        []byte            // Intent payload
+	Metadata    map[string]string // Intent requirements and other attributes
+	CreatedAt   time.Time         // When the intent was created
 }
 
-// Bid represents a bid for an intent
-//
-// IMPORTANT: When implementing bid submission logic (e.g., SubmitBid), ensure that
-// the chain address is automatically included in bid metadata by calling:
-//
-//	bidMetadata := ensureChainAddressMetadata(bid.Metadata, sdk.GetChainAddress())
-//
-// This ensures consistency with ExecutionReport handling and matches the Python SDK behavior.
-// See ensureChainAddressMetadata() in sdk.go for the implementation.
+// Bid represents a bid for an intent. When the bid is submitted, whether by a bidding strategy or
+// SubmitBid, the agent's chain address is added to Metadata unless the caller set one.
 type Bid struct {
 	Price    uint64 // Bid price
 	Currency string // Currency (e.g., "PIN")
 	Metadata map[string]string
+	DryRun   bool // Set by the SDK on strategy bids simulated under BidDryRun; they are never submitted
+}
+
+// BidAck is the matcher's acknowledgement of a submitted bid
+type BidAck struct {
+	BidID      string    // Bid ID echoed by the matcher
+	Accepted   bool      // Whether the matcher accepted the bid
+	Reason     string    // Rejection reason, if any
+	Status     string    // Bid status recorded by the matcher (e.g. "BID_STATUS_ACCEPTED")
+	RecordedAt time.Time // When the matcher recorded the bid
+}
+
+// BidReceipt is returned by SDK.SubmitBid with the matcher's acknowledgement
+type BidReceipt struct {
+	BidID      string    // Bid ID echoed by the matcher
+	IntentID   string    // Intent the bid was submitted for
+	Accepted   bool      // Whether the matcher accepted the bid
+	Reason     string    // Rejection reason, if any
+	Status     string    // Bid status recorded by the matcher (e.g. "BID_STATUS_ACCEPTED")
+	RecordedAt time.Time // When the matcher recorded the bid
 }
 
 // AgentInfo contains agent information
@@ -94,6 +154,12 @@ type Handler interface {
 	Execute(ctx context.Context, task *Task) (*Result, error)
 }
 
+// TaskAcceptor is an optional extension of Handler that can decline a task before it executes.
+// Declined tasks are reported to the matcher with the reason so they can be reassigned.
+type TaskAcceptor interface {
+	CanAccept(task *Task) (accepted bool, reason string)
+}
+
 // BiddingStrategy defines custom bidding behavior (optional)
 type BiddingStrategy interface {
 	// ShouldBid decides whether to bid on an intent
@@ -103,6 +169,11 @@ type BiddingStrategy interface {
 }
 
 // Callbacks for lifecycle events (optional)
+//
+// Events added after the core set are delivered through optional extension interfaces
+// (BidAckCallbacks, HeartbeatCallbacks, ReadyCallbacks, ReportDropCallbacks, ReconnectCallbacks,
+// ReportAckCallbacks) that the SDK detects when callbacks are registered, so implementers only
+// add the methods for the events they care about. Embed BaseCallbacks to skip the core methods too.
 type Callbacks interface {
 	// OnStart is called when the agent starts
 	OnStart() error
@@ -124,17 +195,126 @@ type Callbacks interface {
 	OnError(err error)
 }
 
+// BaseCallbacks implements Callbacks with no-ops. Embed it to implement only some callbacks:
+//
+//	type myCallbacks struct{ agentsdk.BaseCallbacks }
+//
+//	func (myCallbacks) OnError(err error) { log.Println(err) }
+type BaseCallbacks struct{}
+
+func (BaseCallbacks) OnStart() error                                        { return nil }
+func (BaseCallbacks) OnStop() error                                         { return nil }
+func (BaseCallbacks) OnTaskAccepted(task *Task)                             {}
+func (BaseCallbacks) OnTaskRejected(task *Task, reason string)              {}
+func (BaseCallbacks) OnTaskCompleted(task *Task, result *Result, err error) {}
+func (BaseCallbacks) OnBidSubmitted(intent *Intent, bid *Bid)               {}
+func (BaseCallbacks) OnBidWon(intentID string)                              {}
+func (BaseCallbacks) OnBidLost(intentID string)                             {}
+func (BaseCallbacks) OnError(err error)                                     {}
+
+// BidAckCallbacks is an optional extension of Callbacks that receives the matcher's full bid acknowledgement.
+// Implement it alongside Callbacks; the SDK detects it when invoking callbacks.
+type BidAckCallbacks interface {
+	// OnBidAccepted is called when the matcher accepts a bid
+	OnBidAccepted(intent *Intent, bid *Bid, ack *BidAck)
+	// OnBidRejected is called when the matcher rejects a bid
+	OnBidRejected(intent *Intent, bid *Bid, ack *BidAck)
+}
+
+// HeartbeatCallbacks is an optional extension of Callbacks notified when registry heartbeats fail,
+// so operators can alert before the registry evicts the agent. Implement it alongside Callbacks.
+type HeartbeatCallbacks interface {
+	// OnHeartbeatFailed is called after every failed heartbeat with the number of failures in a row
+	OnHeartbeatFailed(err error, consecutiveFailures int)
+}
+
+// ReconnectCallbacks is an optional extension of Callbacks notified each time a matcher stream
+// reconnects. Implement it alongside Callbacks.
+type ReconnectCallbacks interface {
+	// OnStreamReconnect is called before the SDK reconnects stream ("task" or "intent"); err is nil
+	// when the matcher closed the stream cleanly
+	OnStreamReconnect(stream string, err error)
+}
+
+// ReportAckCallbacks is an optional extension of Callbacks notified of every receipt a validator
+// returns, on both the HTTP path (SubmitExecutionReport) and the gRPC path (streamed task reports,
+// batched or not), e.g. to keep a local audit log. Implement it alongside Callbacks. It may be
+// called concurrently while SubmitExecutionReport fans out.
+type ReportAckCallbacks interface {
+	// OnReportSubmitted is called once per acknowledging validator, named by receipt.Endpoint
+	// and receipt.ValidatorID
+	OnReportSubmitted(report *ExecutionReport, receipt *ExecutionReceipt)
+}
+
+// StartupSummary describes the effective configuration an agent started with
+type StartupSummary struct {
+	AgentID            string
+	SubnetID           string
+	SigningAddress     string   // Address of the signing key; empty when requests and reports are unsigned
+	MatcherAddr        string   // gRPC matcher target
+	ValidatorAddrs     []string // gRPC report targets, primary first
+	ValidatorDiscovery string   // Source of HTTP report targets: "resolver", "registry" or "static"
+	RegistryAddr       string
+	AgentEndpoint      string
+	Capabilities       []string
+	MaxConcurrentTasks int
+	TaskTypeLimits     map[string]int
+	TLS                bool
+	ReportOverflow     string
+	ReportMaxRetries   int
+	ResultHash         string
+	PersistResults     bool
+	Tracing            bool
+	StartedAt          time.Time
+}
+
+// ReadyCallbacks is an optional extension of Callbacks notified once Start has succeeded.
+// Implement it alongside Callbacks.
+type ReadyCallbacks interface {
+	// OnReady receives the effective configuration the agent is running with
+	OnReady(summary StartupSummary)
+}
+
 // Metrics represents agent metrics
 type Metrics struct {
 	TasksCompleted   int64
 	TasksFailed      int64
 	AverageExecTime  time.Duration
 	CurrentTasks     int32
+	QueuedTasks      int32 // tasks waiting for an execution slot
 	TotalBids        int64
 	SuccessfulBids   int64
+	SimulatedBids    int64 // strategy bids evaluated under BidDryRun and not submitted
+	BidsRateLimited  int64 // strategy bids dropped by the bid rate limit
 	TotalEarnings    uint64
 	ReportsSubmitted int64
 	ReportsFailed    int64
+	ReportsDropped   int64
+
+	// Retry metrics are kept apart from ReportsSubmitted/ReportsFailed, which count final outcomes
+	ReportRetries        int64
+	ReportRetryExhausted int64
+
+	reportAttemptsMu sync.Mutex
+	reportAttempts   map[string]map[int]int64
+
+	tasksByType    sync.Map // task type -> *int32 currently executing
+	outcomesByType sync.Map // task type -> *taskTypeOutcomes
+}
+
+// TaskTypeMetrics is the per-task-type breakdown of the task metrics, as returned by MetricsByType
+type TaskTypeMetrics struct {
+	TasksCompleted  int64         `json:"tasks_completed"`
+	TasksFailed     int64         `json:"tasks_failed"`
+	AverageExecTime time.Duration `json:"average_exec_time_ns"`
+	CurrentTasks    int32         `json:"current_tasks"`
+}
+
+// taskTypeOutcomes holds the per-type counters behind TaskTypeMetrics
+type taskTypeOutcomes struct {
+	completed   int64
+	failed      int64
+	avgExecTime int64
 }
 
 // NewMetrics creates new metrics instance
@@ -152,6 +332,108 @@ func (m *Metrics) RecordTaskFailure() {
 	atomic.AddInt64(&m.TasksFailed, 1)
 }
 
+// RecordEarnings adds the price of a won bid to TotalEarnings
+func (m *Metrics) RecordEarnings(amount uint64) {
+	atomic.AddUint64(&m.TotalEarnings, amount)
+}
+
+// RecordTaskStart records a task entering execution
+func (m *Metrics) RecordTaskStart() {
+	atomic.AddInt32(&m.CurrentTasks, 1)
+}
+
+// RecordTaskEnd records a task leaving execution
+func (m *Metrics) RecordTaskEnd() {
+	atomic.AddInt32(&m.CurrentTasks, -1)
+}
+
+// RecordQueuedTasks records how many tasks are waiting for an execution slot
+func (m *Metrics) RecordQueuedTasks(n int) {
+	atomic.StoreInt32(&m.QueuedTasks, int32(n))
+}
+
+// execTimeSmoothing is the weight (1/8) given to each new sample in AverageExecTime,
+// the same smoothing TCP uses for round-trip time estimates
+const execTimeSmoothing = 8
+
+// RecordExecTime folds a task execution duration into AverageExecTime as an exponential moving average.
+// The update is a compare-and-swap loop, so concurrent tasks never lose samples.
+func (m *Metrics) RecordExecTime(d time.Duration) {
+	foldExecTime((*int64)(&m.AverageExecTime), d)
+}
+
+// foldExecTime folds d into the moving average stored at avg
+func foldExecTime(avg *int64, d time.Duration) {
+	for {
+		old := atomic.LoadInt64(avg)
+		next := int64(d)
+		if old != 0 {
+			next = old + (int64(d)-old)/execTimeSmoothing
+		}
+		if atomic.CompareAndSwapInt64(avg, old, next) {
+			return
+		}
+	}
+}
+
+// RecordTaskTypeStart records a task of the given type entering execution
+func (m *Metrics) RecordTaskTypeStart(taskType string) {
+	counter, _ := m.tasksByType.LoadOrStore(taskType, new(int32))
+	atomic.AddInt32(counter.(*int32), 1)
+}
+
+// RecordTaskTypeEnd records a task of the given type leaving execution
+func (m *Metrics) RecordTaskTypeEnd(taskType string) {
+	if counter, ok := m.tasksByType.Load(taskType); ok {
+		atomic.AddInt32(counter.(*int32), -1)
+	}
+}
+
+// CurrentTasksByType returns the number of executing tasks per task type
+func (m *Metrics) CurrentTasksByType() map[string]int32 {
+	current := make(map[string]int32)
+	m.tasksByType.Range(func(key, value any) bool {
+		current[key.(string)] = atomic.LoadInt32(value.(*int32))
+		return true
+	})
+	return current
+}
+
+// RecordTaskTypeOutcome records a finished task of the given type and its execution duration in
+// the per-type breakdown returned by MetricsByType
+func (m *Metrics) RecordTaskTypeOutcome(taskType string, success bool, d time.Duration) {
+	value, _ := m.outcomesByType.LoadOrStore(taskType, new(taskTypeOutcomes))
+	outcomes := value.(*taskTypeOutcomes)
+	if success {
+		atomic.AddInt64(&outcomes.completed, 1)
+	} else {
+		atomic.AddInt64(&outcomes.failed, 1)
+	}
+	foldExecTime(&outcomes.avgExecTime, d)
+}
+
+// MetricsByType returns a copy of the task metrics broken down by task type, so a failing
+// capability stands out from healthy ones. The SDK has no Prometheus collector to carry a type
+// label; the breakdown is exposed here and as metrics_by_type in /metrics.json.
+func (m *Metrics) MetricsByType() map[string]TaskTypeMetrics {
+	byType := make(map[string]TaskTypeMetrics)
+	m.outcomesByType.Range(func(key, value any) bool {
+		outcomes := value.(*taskTypeOutcomes)
+		byType[key.(string)] = TaskTypeMetrics{
+			TasksCompleted:  atomic.LoadInt64(&outcomes.completed),
+			TasksFailed:     atomic.LoadInt64(&outcomes.failed),
+			AverageExecTime: time.Duration(atomic.LoadInt64(&outcomes.avgExecTime)),
+		}
+		return true
+	})
+	for taskType, current := range m.CurrentTasksByType() {
+		typeMetrics := byType[taskType]
+		typeMetrics.CurrentTasks = current
+		byType[taskType] = typeMetrics
+	}
+	return byType
+}
+
 // RecordBid records a bid attempt
 func (m *Metrics) RecordBid(success bool) {
 	atomic.AddInt64(&m.TotalBids, 1)
@@ -160,6 +442,16 @@ func (m *Metrics) RecordBid(success bool) {
 	}
 }
 
+// RecordSimulatedBid records a strategy bid evaluated in dry-run mode
+func (m *Metrics) RecordSimulatedBid() {
+	atomic.AddInt64(&m.SimulatedBids, 1)
+}
+
+// RecordBidRateLimited records a strategy bid dropped by the bid rate limit
+func (m *Metrics) RecordBidRateLimited() {
+	atomic.AddInt64(&m.BidsRateLimited, 1)
+}
+
 // RecordReportSuccess records a successful execution report submission
 func (m *Metrics) RecordReportSuccess() {
 	atomic.AddInt64(&m.ReportsSubmitted, 1)
@@ -170,6 +462,91 @@ func (m *Metrics) RecordReportFailure() {
 	atomic.AddInt64(&m.ReportsFailed, 1)
 }
 
+// RecordReportDropped records a report discarded because the report queue overflowed
+func (m *Metrics) RecordReportDropped() {
+	atomic.AddInt64(&m.ReportsDropped, 1)
+}
+
+// RecordReportRetry records a report submission being retried
+func (m *Metrics) RecordReportRetry() {
+	atomic.AddInt64(&m.ReportRetries, 1)
+}
+
+// RecordReportRetryExhausted records a report that still failed after all retries
+func (m *Metrics) RecordReportRetryExhausted() {
+	atomic.AddInt64(&m.ReportRetryExhausted, 1)
+}
+
+// RecordReportAttempts adds one observation to the endpoint's attempts-per-report histogram
+func (m *Metrics) RecordReportAttempts(endpoint string, attempts int) {
+	m.reportAttemptsMu.Lock()
+	defer m.reportAttemptsMu.Unlock()
+	if m.reportAttempts == nil {
+		m.reportAttempts = make(map[string]map[int]int64)
+	}
+	if m.reportAttempts[endpoint] == nil {
+		m.reportAttempts[endpoint] = make(map[int]int64)
+	}
+	m.reportAttempts[endpoint][attempts]++
+}
+
+// ReportAttemptHistogram returns, per validator endpoint, how many reports needed each number of attempts.
+// A flaky validator shows reports spread over several attempt counts; a dead one piles up at the retry limit.
+func (m *Metrics) ReportAttemptHistogram() map[string]map[int]int64 {
+	m.reportAttemptsMu.Lock()
+	defer m.reportAttemptsMu.Unlock()
+	histogram := make(map[string]map[int]int64, len(m.reportAttempts))
+	for endpoint, buckets := range m.reportAttempts {
+		copied := make(map[int]int64, len(buckets))
+		for attempts, count := range buckets {
+			copied[attempts] = count
+		}
+		histogram[endpoint] = copied
+	}
+	return histogram
+}
+
+// Reset zeroes every counter, the exec time averages and the report attempt histogram, and returns
+// a snapshot of the values it cleared, for operators who scrape on an interval so counters stay
+// small and each scrape is a per-interval delta. Use the returned snapshot rather than calling
+// Snapshot first: each counter is swapped to zero atomically, so a recording made during the reset
+// is counted either in the returned snapshot or after it, never lost. Gauges (CurrentTasks,
+// QueuedTasks and CurrentTasksByType) describe work in flight; they are reported but not cleared.
+// The per-type breakdown and the attempt histogram are cleared without being returned.
+func (m *Metrics) Reset() MetricsSnapshot {
+	cleared := MetricsSnapshot{
+		TasksCompleted:   atomic.SwapInt64(&m.TasksCompleted, 0),
+		TasksFailed:      atomic.SwapInt64(&m.TasksFailed, 0),
+		AverageExecTime:  time.Duration(atomic.SwapInt64((*int64)(&m.AverageExecTime), 0)),
+		CurrentTasks:     atomic.LoadInt32(&m.CurrentTasks),
+		QueuedTasks:      atomic.LoadInt32(&m.QueuedTasks),
+		TotalBids:        atomic.SwapInt64(&m.TotalBids, 0),
+		SuccessfulBids:   atomic.SwapInt64(&m.SuccessfulBids, 0),
+		SimulatedBids:    atomic.SwapInt64(&m.SimulatedBids, 0),
+		BidsRateLimited:  atomic.SwapInt64(&m.BidsRateLimited, 0),
+		TotalEarnings:    atomic.SwapUint64(&m.TotalEarnings, 0),
+		ReportsSubmitted: atomic.SwapInt64(&m.ReportsSubmitted, 0),
+		ReportsFailed:    atomic.SwapInt64(&m.ReportsFailed, 0),
+		ReportsDropped:   atomic.SwapInt64(&m.ReportsDropped, 0),
+
+		ReportRetries:        atomic.SwapInt64(&m.ReportRetries, 0),
+		ReportRetryExhausted: atomic.SwapInt64(&m.ReportRetryExhausted, 0),
+	}
+
+	m.outcomesByType.Range(func(_, value any) bool {
+		outcomes := value.(*taskTypeOutcomes)
+		atomic.StoreInt64(&outcomes.completed, 0)
+		atomic.StoreInt64(&outcomes.failed, 0)
+		atomic.StoreInt64(&outcomes.avgExecTime, 0)
+		return true
+	})
+
+	m.reportAttemptsMu.Lock()
+	m.reportAttempts = nil
+	m.reportAttemptsMu.Unlock()
+	return cleared
+}
+
 // GetStats returns current metrics
 func (m *Metrics) GetStats() (tasksCompleted, tasksFailed, totalBids, successfulBids int64) {
 	return atomic.LoadInt64(&m.TasksCompleted),
@@ -178,6 +555,48 @@ func (m *Metrics) GetStats() (tasksCompleted, tasksFailed, totalBids, successful
 		atomic.LoadInt64(&m.SuccessfulBids)
 }
 
+// MetricsSnapshot is a point-in-time copy of Metrics that is safe to read and log
+type MetricsSnapshot struct {
+	TasksCompleted   int64         `json:"tasks_completed"`
+	TasksFailed      int64         `json:"tasks_failed"`
+	AverageExecTime  time.Duration `json:"average_exec_time_ns"`
+	CurrentTasks     int32         `json:"current_tasks"`
+	QueuedTasks      int32         `json:"queued_tasks"`
+	TotalBids        int64         `json:"total_bids"`
+	SuccessfulBids   int64         `json:"successful_bids"`
+	SimulatedBids    int64         `json:"simulated_bids"`
+	BidsRateLimited  int64         `json:"bids_rate_limited"`
+	TotalEarnings    uint64        `json:"total_earnings"`
+	ReportsSubmitted int64         `json:"reports_submitted"`
+	ReportsFailed    int64         `json:"reports_failed"`
+	ReportsDropped   int64         `json:"reports_dropped"`
+
+	ReportRetries        int64 `json:"report_retries"`
+	ReportRetryExhausted int64 `json:"report_retry_exhausted"`
+}
+
+// Snapshot returns a copy of all metrics, each field loaded atomically
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	return MetricsSnapshot{
+		TasksCompleted:   atomic.LoadInt64(&m.TasksCompleted),
+		TasksFailed:      atomic.LoadInt64(&m.TasksFailed),
+		AverageExecTime:  time.Duration(atomic.LoadInt64((*int64)(&m.AverageExecTime))),
+		CurrentTasks:     atomic.LoadInt32(&m.CurrentTasks),
+		QueuedTasks:      atomic.LoadInt32(&m.QueuedTasks),
+		TotalBids:        atomic.LoadInt64(&m.TotalBids),
+		SuccessfulBids:   atomic.LoadInt64(&m.SuccessfulBids),
+		SimulatedBids:    atomic.LoadInt64(&m.SimulatedBids),
+		BidsRateLimited:  atomic.LoadInt64(&m.BidsRateLimited),
+		TotalEarnings:    atomic.LoadUint64(&m.TotalEarnings),
+		ReportsSubmitted: atomic.LoadInt64(&m.ReportsSubmitted),
+		ReportsFailed:    atomic.LoadInt64(&m.ReportsFailed),
+		ReportsDropped:   atomic.LoadInt64(&m.ReportsDropped),
+
+		ReportRetries:        atomic.LoadInt64(&m.ReportRetries),
+		ReportRetryExhausted: atomic.LoadInt64(&m.ReportRetryExhausted),
+	}
+}
+
 // Authentication types (temporary until proto is updated)
 
 // AuthRequest represents authentication request
@@ -203,13 +622,20 @@ type RegisterCapabilitiesRequest struct {
 	Capabilities []string
 	StakeAmount  uint64
 	Owner        string
+	Timestamp    int64 // unix seconds, bounds replay of the signed request
 	Signature    []byte
 }
 
-// Marshal marshals the request (mock implementation)
+// Marshal returns the canonical JSON the request signature covers: every field but Signature,
+// with sorted keys and no whitespace
 func (r *RegisterCapabilitiesRequest) Marshal() ([]byte, error) {
-	// This would use protobuf marshaling in real implementation
-	return []byte(r.AgentId), nil
+	return json.Marshal(map[string]interface{}{
+		"agent_id":     r.AgentId,
+		"capabilities": r.Capabilities,
+		"stake_amount": r.StakeAmount,
+		"owner":        r.Owner,
+		"timestamp":    r.Timestamp,
+	})
 }
 
 // RegisterCapabilitiesResponse represents registration response
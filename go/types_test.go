@@ -0,0 +1,170 @@
+package agentsdk
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMetricsSnapshotCopiesAllFields(t *testing.T) {
+	m := NewMetrics()
+	m.RecordTaskSuccess()
+	m.RecordTaskFailure()
+	m.RecordTaskStart()
+	m.RecordBid(true)
+	m.RecordBid(false)
+	m.RecordReportSuccess()
+	m.RecordReportFailure()
+	m.RecordReportDropped()
+	m.RecordEarnings(150)
+	m.RecordEarnings(50)
+
+	snapshot := m.Snapshot()
+	want := MetricsSnapshot{
+		TasksCompleted:   1,
+		TasksFailed:      1,
+		CurrentTasks:     1,
+		TotalBids:        2,
+		SuccessfulBids:   1,
+		TotalEarnings:    200,
+		ReportsSubmitted: 1,
+		ReportsFailed:    1,
+		ReportsDropped:   1,
+	}
+	if snapshot != want {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+
+	m.RecordTaskSuccess()
+	if snapshot.TasksCompleted != 1 {
+		t.Fatal("snapshot should not change after later updates")
+	}
+}
+
+func TestRecordExecTimeMovingAverage(t *testing.T) {
+	m := NewMetrics()
+	m.RecordExecTime(800 * time.Millisecond)
+	if avg := m.Snapshot().AverageExecTime; avg != 800*time.Millisecond {
+		t.Fatalf("first sample should seed the average, got %v", avg)
+	}
+
+	m.RecordExecTime(1600 * time.Millisecond)
+	if avg := m.Snapshot().AverageExecTime; avg != 900*time.Millisecond {
+		t.Fatalf("expected 900ms, got %v", avg)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.RecordExecTime(900 * time.Millisecond)
+		}()
+	}
+	wg.Wait()
+	if avg := m.Snapshot().AverageExecTime; avg != 900*time.Millisecond {
+		t.Fatalf("concurrent identical samples should keep the average at 900ms, got %v", avg)
+	}
+}
+
+func TestMetricsResetZeroesCountersAndKeepsGauges(t *testing.T) {
+	m := NewMetrics()
+	m.RecordTaskSuccess()
+	m.RecordTaskStart()
+	m.RecordQueuedTasks(2)
+	m.RecordBid(true)
+	m.RecordEarnings(100)
+	m.RecordExecTime(time.Second)
+	m.RecordReportAttempts("validator-1", 2)
+
+	m.Reset()
+	want := MetricsSnapshot{CurrentTasks: 1, QueuedTasks: 2}
+	if snapshot := m.Snapshot(); snapshot != want {
+		t.Fatalf("expected only gauges after reset, got %+v", snapshot)
+	}
+	if histogram := m.ReportAttemptHistogram(); len(histogram) != 0 {
+		t.Fatalf("expected an empty attempt histogram after reset, got %v", histogram)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			m.RecordTaskSuccess()
+			m.RecordReportAttempts("validator-1", 1)
+		}()
+		go func() {
+			defer wg.Done()
+			m.Reset()
+		}()
+	}
+	wg.Wait()
+	m.Reset()
+	m.RecordTaskSuccess()
+	if completed := m.Snapshot().TasksCompleted; completed != 1 {
+		t.Fatalf("expected counting to resume from zero, got %d", completed)
+	}
+}
+
+func TestMetricsResetLosesNoConcurrentIncrements(t *testing.T) {
+	m := NewMetrics()
+	const workers, perWorker = 8, 1000
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				m.RecordTaskSuccess()
+				m.RecordBid(true)
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	var completed, bids int64
+	for scraping := true; scraping; {
+		select {
+		case <-done:
+			scraping = false
+		default:
+		}
+		cleared := m.Reset()
+		completed += cleared.TasksCompleted
+		bids += cleared.SuccessfulBids
+	}
+
+	if completed != workers*perWorker || bids != workers*perWorker {
+		t.Fatalf("expected %d of each across resets, got %d completed and %d bids", workers*perWorker, completed, bids)
+	}
+}
+
+func TestMetricsByTypeBreaksDownOutcomes(t *testing.T) {
+	m := NewMetrics()
+	m.RecordTaskTypeOutcome("ml.inference", false, 2*time.Second)
+	m.RecordTaskTypeOutcome("ml.inference", false, 2*time.Second)
+	m.RecordTaskTypeOutcome("storage", true, 100*time.Millisecond)
+	m.RecordTaskTypeStart("storage")
+
+	byType := m.MetricsByType()
+	want := map[string]TaskTypeMetrics{
+		"ml.inference": {TasksFailed: 2, AverageExecTime: 2 * time.Second},
+		"storage":      {TasksCompleted: 1, AverageExecTime: 100 * time.Millisecond, CurrentTasks: 1},
+	}
+	if !reflect.DeepEqual(byType, want) {
+		t.Fatalf("unexpected per-type metrics %+v", byType)
+	}
+
+	byType["storage"] = TaskTypeMetrics{}
+	m.Reset()
+	if got := m.MetricsByType()["storage"]; got != (TaskTypeMetrics{CurrentTasks: 1}) {
+		t.Fatalf("expected reset to zero per-type counters and keep the gauge, got %+v", got)
+	}
+}
@@ -2,12 +2,26 @@ package agentsdk
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 
-	pb "subnet/proto/subnet"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	pb "subnet/proto/subnet"
+)
+
+// Report submissions carry their report IDs as an idempotency key so validators can drop
+// duplicates delivered by retries
+const (
+	IdempotencyKeyHeader   = "Idempotency-Key" // HTTP header
+	IdempotencyKeyMetadata = "idempotency-key" // gRPC metadata, one value per report in a batch
 )
 
+// verificationRecordLimit caps the records fetched per GetReportStatus call. Records are filtered
+// by intent and agent, so one report's records are well within it.
+const verificationRecordLimit = 100
+
 // ValidatorClient wraps the gRPC ValidatorService client
 type ValidatorClient struct {
 	conn   *grpc.ClientConn
@@ -15,8 +29,8 @@ type ValidatorClient struct {
 }
 
 // NewValidatorClient creates a new validator client
-func NewValidatorClient(target string, signingConfig *SigningConfig, secure bool) (*ValidatorClient, error) {
-	conn, err := DialOption(target, signingConfig, secure)
+func NewValidatorClient(target string, signingConfig *SigningConfig, secure bool, opts ...grpc.DialOption) (*ValidatorClient, error) {
+	conn, err := DialOption(target, signingConfig, secure, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial validator: %w", err)
 	}
@@ -35,14 +49,19 @@ func (c *ValidatorClient) Close() error {
 	return nil
 }
 
-// SubmitExecutionReport submits an execution report to the validator
+// SubmitExecutionReport submits an execution report to the validator, keyed by its report ID
 func (c *ValidatorClient) SubmitExecutionReport(ctx context.Context, req *pb.ExecutionReport) (*pb.Receipt, error) {
-	return c.client.SubmitExecutionReport(ctx, req)
+	return c.client.SubmitExecutionReport(withIdempotencyKeys(ctx, req.GetReportId()), req)
 }
 
-// SubmitExecutionReportBatch submits multiple execution reports to the validator in batch
+// SubmitExecutionReportBatch submits multiple execution reports to the validator in batch, keyed
+// by their report IDs in request order
 func (c *ValidatorClient) SubmitExecutionReportBatch(ctx context.Context, req *pb.ExecutionReportBatchRequest) (*pb.ExecutionReportBatchResponse, error) {
-	return c.client.SubmitExecutionReportBatch(ctx, req)
+	reportIDs := make([]string, 0, len(req.GetReports()))
+	for _, report := range req.GetReports() {
+		reportIDs = append(reportIDs, report.GetReportId())
+	}
+	return c.client.SubmitExecutionReportBatch(withIdempotencyKeys(ctx, reportIDs...), req)
 }
 
 // GetValidatorSet retrieves the validator set
@@ -50,6 +69,11 @@ func (c *ValidatorClient) GetValidatorSet(ctx context.Context, req *pb.GetCheckp
 	return c.client.GetValidatorSet(ctx, req)
 }
 
+// GetCheckpoint retrieves a checkpoint header; an empty selector returns the latest checkpoint
+func (c *ValidatorClient) GetCheckpoint(ctx context.Context, req *pb.GetCheckpointRequest) (*pb.CheckpointHeader, error) {
+	return c.client.GetCheckpoint(ctx, req)
+}
+
 // GetExecutionReport retrieves a single execution report by report ID
 func (c *ValidatorClient) GetExecutionReport(ctx context.Context, reportID string) (*pb.ExecutionReport, error) {
 	req := &pb.GetExecutionReportRequest{
@@ -65,4 +89,52 @@ func (c *ValidatorClient) ListExecutionReports(ctx context.Context, intentID str
 		Limit:    limit,
 	}
 	return c.client.ListExecutionReports(ctx, req)
-}
\ No newline at end of file
+}
+
+// GetReportStatus retrieves the verification records validators have produced for a report. The
+// verification RPC is filtered by intent and agent, so the report is looked up first to learn them
+// and the records are then narrowed to reportID. An empty result means no validator has verified
+// the report yet.
+func (c *ValidatorClient) GetReportStatus(ctx context.Context, reportID string) ([]*pb.VerificationRecord, error) {
+	report, err := c.GetExecutionReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := c.client.GetVerificationRecords(ctx, &pb.GetVerificationRecordsRequest{
+		IntentId: report.GetIntentId(),
+		AgentId:  report.GetAgentId(),
+		Limit:    verificationRecordLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*pb.VerificationRecord
+	for {
+		record, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if record.GetReportId() == reportID {
+			records = append(records, record)
+		}
+	}
+}
+
+// withIdempotencyKeys adds reportIDs to the outgoing idempotency-key metadata
+func withIdempotencyKeys(ctx context.Context, reportIDs ...string) context.Context {
+	kv := make([]string, 0, 2*len(reportIDs))
+	for _, reportID := range reportIDs {
+		if reportID != "" {
+			kv = append(kv, IdempotencyKeyMetadata, reportID)
+		}
+	}
+	if len(kv) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
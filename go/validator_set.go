@@ -0,0 +1,165 @@
+package agentsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	pb "subnet/proto/subnet"
+)
+
+const (
+	defaultValidatorSetCacheTTL = 30 * time.Second
+	defaultValidatorCacheTTL    = 15 * time.Second
+)
+
+// ValidatorMember is one validator in the active validator set
+type ValidatorMember struct {
+	ID        string
+	Endpoint  string
+	Weight    uint64
+	PublicKey []byte
+}
+
+// ValidatorSetInfo is the active validator set together with how current it is
+// relative to the latest checkpoint
+type ValidatorSetInfo struct {
+	Validators     []ValidatorMember
+	MinValidators  int32
+	ThresholdNum   int32
+	ThresholdDenom int32
+	Epoch          uint64 // Epoch from which the set is effective
+
+	CheckpointEpoch     uint64    // Epoch of the latest checkpoint
+	CheckpointSlot      uint64    // Slot within CheckpointEpoch; checkpoints carry no block height
+	CheckpointTimestamp time.Time // When the latest checkpoint was produced
+	CheckpointAge       time.Duration
+	EpochLag            uint64 // Checkpoint epochs elapsed since the set became effective
+
+	FetchedAt time.Time
+}
+
+// validatorCache holds the validator set for ValidatorSetCacheTTL and discovered report endpoints
+// for ValidatorCacheTTL
+type validatorCache struct {
+	mu               sync.Mutex
+	setInfo          *ValidatorSetInfo
+	setExpires       time.Time
+	endpoints        []ValidatorEndpoint
+	endpointsExpires time.Time
+}
+
+// ValidatorSetInfo returns the validator set combined with latest-checkpoint metadata so callers
+// can judge whether it is current enough for quorum calculations. Results are cached for
+// ValidatorSetCacheTTL; CheckpointAge is recomputed on every call.
+func (sdk *SDK) ValidatorSetInfo(ctx context.Context) (*ValidatorSetInfo, error) {
+	validatorClient := sdk.clients().validator
+	if validatorClient == nil {
+		return nil, errors.New("validator client not initialized")
+	}
+
+	sdk.validatorCache.mu.Lock()
+	cached, expires := sdk.validatorCache.setInfo, sdk.validatorCache.setExpires
+	sdk.validatorCache.mu.Unlock()
+
+	if cached == nil || !time.Now().Before(expires) {
+		req := &pb.GetCheckpointRequest{SubnetId: sdk.GetSubnetID()}
+
+		set, err := validatorClient.GetValidatorSet(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get validator set: %w", err)
+		}
+		checkpoint, err := validatorClient.GetCheckpoint(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest checkpoint: %w", err)
+		}
+
+		cached = validatorSetInfoFromProto(set, checkpoint)
+		sdk.validatorCache.mu.Lock()
+		sdk.validatorCache.setInfo = cached
+		sdk.validatorCache.setExpires = cached.FetchedAt.Add(sdk.config.ValidatorSetCacheTTL)
+		sdk.validatorCache.mu.Unlock()
+	}
+
+	info := *cached
+	info.Validators = append([]ValidatorMember(nil), cached.Validators...)
+	if !info.CheckpointTimestamp.IsZero() {
+		info.CheckpointAge = time.Since(info.CheckpointTimestamp)
+	}
+	return &info, nil
+}
+
+func validatorSetInfoFromProto(set *pb.ValidatorSet, checkpoint *pb.CheckpointHeader) *ValidatorSetInfo {
+	info := &ValidatorSetInfo{
+		MinValidators:  set.GetMinValidators(),
+		ThresholdNum:   set.GetThresholdNum(),
+		ThresholdDenom: set.GetThresholdDenom(),
+		Epoch:          set.GetEffectiveEpoch(),
+		FetchedAt:      time.Now(),
+	}
+	for _, v := range set.GetValidators() {
+		info.Validators = append(info.Validators, ValidatorMember{
+			ID:        v.GetId(),
+			Endpoint:  v.GetEndpoint(),
+			Weight:    v.GetWeight(),
+			PublicKey: v.GetPubkey(),
+		})
+	}
+
+	if checkpoint != nil {
+		info.CheckpointEpoch = checkpoint.GetEpoch()
+		info.CheckpointSlot = checkpoint.GetEpochSlot().GetSlot()
+		if ts := checkpoint.GetTimestamp(); ts > 0 {
+			info.CheckpointTimestamp = time.Unix(ts, 0)
+			info.CheckpointAge = info.FetchedAt.Sub(info.CheckpointTimestamp)
+		}
+		if info.CheckpointEpoch > info.Epoch {
+			info.EpochLag = info.CheckpointEpoch - info.Epoch
+		}
+	}
+
+	return info
+}
+
+// cachedValidatorEndpoints returns discovered report targets, refreshing them through discover
+// once ValidatorCacheTTL has elapsed. Failed lookups are not cached.
+func (sdk *SDK) cachedValidatorEndpoints(ctx context.Context, discover func(context.Context) ([]ValidatorEndpoint, error)) ([]ValidatorEndpoint, error) {
+	sdk.validatorCache.mu.Lock()
+	if sdk.validatorCache.endpoints != nil && time.Now().Before(sdk.validatorCache.endpointsExpires) {
+		endpoints := sdk.validatorCache.endpoints
+		sdk.validatorCache.mu.Unlock()
+		return endpoints, nil
+	}
+	sdk.validatorCache.mu.Unlock()
+
+	endpoints, err := discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if endpoints == nil {
+		endpoints = []ValidatorEndpoint{}
+	}
+
+	sdk.validatorCache.mu.Lock()
+	sdk.validatorCache.endpoints = endpoints
+	sdk.validatorCache.endpointsExpires = time.Now().Add(sdk.config.ValidatorCacheTTL)
+	sdk.validatorCache.mu.Unlock()
+	return endpoints, nil
+}
+
+// invalidateCachedEndpoint drops the discovered report targets when endpointURL is one of them, so
+// the next report re-runs discovery instead of retrying a validator that may have gone away
+func (sdk *SDK) invalidateCachedEndpoint(endpointURL string) {
+	sdk.validatorCache.mu.Lock()
+	defer sdk.validatorCache.mu.Unlock()
+
+	for _, validator := range sdk.validatorCache.endpoints {
+		if urlStr, err := buildExecutionReportURL(validator.Endpoint, sdk.config.ReportScheme, sdk.config.ReportPath); err == nil && urlStr == endpointURL {
+			sdk.validatorCache.endpoints = nil
+			sdk.logger.Debug("Invalidated cached validator endpoints", "endpoint", endpointURL)
+			return
+		}
+	}
+}
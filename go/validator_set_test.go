@@ -0,0 +1,100 @@
+package agentsdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "subnet/proto/subnet"
+)
+
+func TestValidatorSetInfoFromProtoReportsStaleness(t *testing.T) {
+	checkpointTime := time.Now().Add(-2 * time.Minute)
+	info := validatorSetInfoFromProto(
+		&pb.ValidatorSet{
+			Validators:     []*pb.Validator{{Id: "v1", Endpoint: "v1:9090", Weight: 10}},
+			MinValidators:  4,
+			ThresholdNum:   3,
+			ThresholdDenom: 4,
+			EffectiveEpoch: 7,
+		},
+		&pb.CheckpointHeader{Epoch: 10, Timestamp: checkpointTime.Unix(), EpochSlot: &pb.EpochSlot{Epoch: 10, Slot: 42}},
+	)
+
+	if len(info.Validators) != 1 || info.Validators[0].ID != "v1" || info.Validators[0].Weight != 10 {
+		t.Fatalf("unexpected validators %+v", info.Validators)
+	}
+	if info.Epoch != 7 || info.CheckpointEpoch != 10 || info.EpochLag != 3 || info.CheckpointSlot != 42 {
+		t.Fatalf("unexpected epoch info %+v", info)
+	}
+	if info.CheckpointAge < 2*time.Minute-time.Second {
+		t.Fatalf("expected checkpoint age of about 2m, got %v", info.CheckpointAge)
+	}
+}
+
+func TestValidatorReportEndpointsAreCached(t *testing.T) {
+	calls := 0
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ValidatorCacheTTL = time.Hour
+		cfg.ValidatorEndpointResolver = func(ctx context.Context) ([]ValidatorEndpoint, error) {
+			calls++
+			return []ValidatorEndpoint{{ID: "v1", Endpoint: "validator-1:8080"}}, nil
+		}
+	})
+
+	for i := 0; i < 3; i++ {
+		endpoints, errs := sdk.validatorReportEndpoints(context.Background())
+		if len(errs) != 0 || len(endpoints) != 1 {
+			t.Fatalf("unexpected endpoints %v, errs %v", endpoints, errs)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected resolver to be called once within the TTL, got %d", calls)
+	}
+}
+
+func TestValidatorReportEndpointsInvalidatedOnSubmissionFailure(t *testing.T) {
+	var failing atomic.Bool
+	validator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"report_id":"report-1","status":"accepted"}`))
+	}))
+	defer validator.Close()
+
+	var calls atomic.Int32
+	sdk := newTestSDK(t, func(cfg *Config) {
+		cfg.ValidatorCacheTTL = time.Hour
+		cfg.ValidatorEndpointResolver = func(ctx context.Context) ([]ValidatorEndpoint, error) {
+			calls.Add(1)
+			return []ValidatorEndpoint{{ID: "v1", Endpoint: validator.URL}}, nil
+		}
+	})
+
+	report := &ExecutionReport{ReportID: "report-1", AssignmentID: "task-1", IntentID: "intent-1"}
+	for i := 0; i < 2; i++ {
+		if _, err := sdk.SubmitExecutionReport(context.Background(), report); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected discovery to be cached across reports, got %d lookups", calls.Load())
+	}
+
+	failing.Store(true)
+	if _, err := sdk.SubmitExecutionReport(context.Background(), report); err == nil {
+		t.Fatal("expected the report to fail")
+	}
+	failing.Store(false)
+	if _, err := sdk.SubmitExecutionReport(context.Background(), report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected a failed submission to force rediscovery, got %d lookups", calls.Load())
+	}
+}
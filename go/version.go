@@ -0,0 +1,4 @@
+package agentsdk
+
+// Version is the SDK release, bound into request signatures when SignAgentInfo is enabled
+const Version = "0.1.0"
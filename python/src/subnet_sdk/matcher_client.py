@@ -84,6 +84,19 @@ class MatcherClient:
         return await self._signer.build_metadata(method, request)
 
 
+IDEMPOTENCY_KEY_METADATA = "idempotency-key"
+
+
+def _with_idempotency_keys(
+    metadata: Optional[Sequence[Tuple[str, str]]], report_ids: Sequence[str]
+) -> Optional[Sequence[Tuple[str, str]]]:
+    """Append report IDs as idempotency keys so validators can drop retried duplicates."""
+    keys = [(IDEMPOTENCY_KEY_METADATA, report_id) for report_id in report_ids if report_id]
+    if not keys:
+        return metadata
+    return list(metadata or []) + keys
+
+
 class ValidatorClient:
     """Async client for subnet ValidatorService."""
 
@@ -110,6 +123,7 @@ class ValidatorClient:
         self, request: execution_report_pb2.ExecutionReport
     ) -> report_pb2.Receipt:
         metadata = await self._metadata("/subnet.v1.ValidatorService/SubmitExecutionReport", request)
+        metadata = _with_idempotency_keys(metadata, [request.report_id])
         return await self._stub.SubmitExecutionReport(request, metadata=metadata)
 
     async def submit_execution_report_batch(
@@ -117,6 +131,7 @@ class ValidatorClient:
     ) -> service_pb2.ExecutionReportBatchResponse:
         """Submit multiple execution reports to the validator in batch."""
         metadata = await self._metadata("/subnet.v1.ValidatorService/SubmitExecutionReportBatch", request)
+        metadata = _with_idempotency_keys(metadata, [report.report_id for report in request.reports])
         return await self._stub.SubmitExecutionReportBatch(request, metadata=metadata)
 
     async def get_validator_set(
@@ -824,9 +824,9 @@ class SDK:
             intent_id=receipt.intent_id or "",
             validator_id=receipt.validator_id or "",
             status=receipt.status or "",
-            message=receipt.phase or None,
             received_at=received_at,
             endpoint=endpoint,
+            phase=receipt.phase or None,
         )
 
     async def _fire_callback(self, method: str, *args) -> None:
@@ -885,6 +885,7 @@ class SDK:
             async with session.post(
                 endpoint,
                 json=payload,
+                headers={"Idempotency-Key": payload["report_id"]},
                 timeout=ClientTimeout(total=request_timeout),
             ) as response:
                 if response.status >= 300:
@@ -904,6 +905,7 @@ class SDK:
             validator_id=data.get("validator_id", ""),
             status=data.get("status", ""),
             message=data.get("message"),
+            phase=data.get("phase") or None,
         )
         received_ts = data.get("received_ts")
         if isinstance(received_ts, (int, float)) and received_ts > 0:
@@ -80,6 +80,8 @@ class ExecutionReceipt:
     message: Optional[str] = None
     received_at: Optional[datetime] = None
     endpoint: Optional[str] = None
+    # Validation phase: RECEIVED, VALIDATING, VERIFIED or REJECTED; None if not reported
+    phase: Optional[str] = None
 
 
 @dataclass
@@ -12,6 +12,7 @@ from eth_keys import keys
 
 from subnet_sdk.grpc_transport import SigningConfig, SigningInterceptor
 from subnet_sdk.matcher_client import MatcherClient
+from subnet_sdk.proto.subnet import report_pb2
 
 from subnet_sdk import (
     Config,
@@ -100,6 +101,17 @@ def test_submit_execution_report_requires_endpoints():
         asyncio.run(sdk.submit_execution_report(report))
 
 
+def test_grpc_receipt_phase_is_not_reported_as_message():
+    sdk = SDK(_base_config())
+    receipt = sdk._convert_receipt_proto(
+        report_pb2.Receipt(report_id="report-1", status="accepted", phase="VALIDATING"),
+        "validator-1:9090",
+    )
+
+    assert receipt.phase == "VALIDATING"
+    assert receipt.message is None
+
+
 def test_signing_interceptor_metadata_contains_expected_fields():
     config = SigningConfig(private_key_hex="aa" * 32, chain_id="subnet-test")
     interceptor = SigningInterceptor(config)